@@ -0,0 +1,50 @@
+// Package blobstore stores attachment contents outside the database, on the
+// local filesystem or in an S3-compatible object store.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs under slash-separated keys.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob
+	// already stored there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key. It returns ErrNotFound when there
+	// is none. The caller must close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv builds the store selected by STORAGE_DRIVER: "local" (the default)
+// keeps blobs under STORAGE_PATH, "s3" uses the bucket described by the S3_*
+// variables.
+func FromEnv() (BlobStore, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		root := os.Getenv("STORAGE_PATH")
+		if root == "" {
+			root = "uploads"
+		}
+		return NewLocal(root)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") == "true",
+		})
+	default:
+		return nil, errors.New("unknown STORAGE_DRIVER " + driver)
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// IntEnv returns the integer value of the environment variable key, or def
+// when it is unset or malformed.
+func IntEnv(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q, using %d", key, v, def)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// maxCommentLength bounds comment bodies, in characters.
+const maxCommentLength = 10000
+
+// recordActivity adds system entries to a todo's timeline for the changes a
+// discussion refers to: the todo's lifecycle, its status, its group, its
+// assignee and its completion. Status and group names and assignee emails are
+// resolved now so that entries still read correctly after those are renamed
+// or deleted.
+func recordActivity(tx *gorm.DB, todoID, actorID uint, action string, changes models.FieldChanges) error {
+	var entries []models.Comment
+	add := func(event, body string) {
+		entries = append(entries, models.Comment{
+			TodoID:   todoID,
+			AuthorID: actorID,
+			Kind:     models.CommentKindActivity,
+			Event:    event,
+			Body:     body,
+		})
+	}
+
+	switch action {
+	case models.RevisionCreate:
+		add("created", "Created this todo")
+	case models.RevisionDelete:
+		add("deleted", "Moved this todo to the trash")
+	case models.RevisionRestore:
+		add("restored", "Restored this todo from the trash")
+	}
+
+	if action != models.RevisionCreate {
+		if change, ok := changes["status_id"]; ok {
+			from, err := statusName(tx, change.Before)
+			if err != nil {
+				return err
+			}
+			to, err := statusName(tx, change.After)
+			if err != nil {
+				return err
+			}
+			add("status_changed", fmt.Sprintf("Changed status from **%s** to **%s**", from, to))
+		} else if change, ok := changes["completed"]; ok {
+			if change.After == true {
+				add("completed", "Marked this todo as done")
+			} else {
+				add("reopened", "Reopened this todo")
+			}
+		}
+
+		if change, ok := changes["group_id"]; ok {
+			if change.After != nil {
+				name, err := groupName(tx, change.After)
+				if err != nil {
+					return err
+				}
+				add("moved", fmt.Sprintf("Moved to group **%s**", name))
+			} else {
+				name, err := groupName(tx, change.Before)
+				if err != nil {
+					return err
+				}
+				add("moved", fmt.Sprintf("Removed from group **%s**", name))
+			}
+		}
+
+		if change, ok := changes["assignee_id"]; ok {
+			if change.After != nil {
+				email, err := userEmail(tx, change.After)
+				if err != nil {
+					return err
+				}
+				add("assigned", fmt.Sprintf("Assigned to **%s**", email))
+			} else {
+				email, err := userEmail(tx, change.Before)
+				if err != nil {
+					return err
+				}
+				add("unassigned", fmt.Sprintf("Unassigned **%s**", email))
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	var todo models.Todo
+	if err := models.AllWorkspaces(tx).Unscoped().Select("workspace_id").First(&todo, todoID).Error; err != nil {
+		return fmt.Errorf("todo not found: %w", err)
+	}
+	for i := range entries {
+		entries[i].WorkspaceID = todo.WorkspaceID
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+func statusName(tx *gorm.DB, value interface{}) (string, error) {
+	id, err := stateID(value)
+	if err != nil || id == nil {
+		return "none", err
+	}
+	var status models.Status
+	if err := tx.Unscoped().Select("name").First(&status, *id).Error; err != nil {
+		return "unknown", nil
+	}
+	return status.Name, nil
+}
+
+func groupName(tx *gorm.DB, value interface{}) (string, error) {
+	id, err := stateID(value)
+	if err != nil || id == nil {
+		return "none", err
+	}
+	var group models.Group
+	if err := tx.Unscoped().Select("name").First(&group, *id).Error; err != nil {
+		return "unknown", nil
+	}
+	return group.Name, nil
+}
+
+func userEmail(tx *gorm.DB, value interface{}) (string, error) {
+	id, err := stateID(value)
+	if err != nil || id == nil {
+		return "nobody", err
+	}
+	var user models.User
+	if err := tx.Unscoped().Select("email").First(&user, *id).Error; err != nil {
+		return "unknown", nil
+	}
+	return user.Email, nil
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// checkUserAdmin allows actorID to manage users of the workspace tx is
+// scoped to if they administer it.
+func checkUserAdmin(tx *gorm.DB, actorID uint) error {
+	admin, err := isWorkspaceAdmin(tx, actorID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return fmt.Errorf("%w: requires workspace admin", ErrForbidden)
+	}
+	return nil
+}
+
+// managedUsers restricts a query on users to the members of the workspace tx
+// is scoped to, if any.
+func managedUsers(tx *gorm.DB) *gorm.DB {
+	if workspaceID, ok := currentWorkspace(tx); ok {
+		return tx.Where("id IN (?)", tx.Model(&models.WorkspaceMember{}).Select("user_id").Where("workspace_id = ?", workspaceID))
+	}
+	return tx
+}
+
+// managedUser loads userID for actorID, who must be allowed to manage the
+// users of the workspace tx is scoped to, in which userID must be a member.
+func managedUser(tx *gorm.DB, userID, actorID uint) (*models.User, error) {
+	if err := checkUserAdmin(tx, actorID); err != nil {
+		return nil, err
+	}
+	var user models.User
+	if err := managedUsers(tx).First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return &user, nil
+}
+
+// checkUserDeletable allows actorID to delete user, which takes them out of
+// every workspace they belong to, only if actorID administers all of those.
+func checkUserDeletable(tx *gorm.DB, user *models.User, actorID uint) error {
+	var workspaceIDs []uint
+	if err := models.AllWorkspaces(tx).Model(&models.WorkspaceMember{}).Where("user_id = ?", user.ID).Pluck("workspace_id", &workspaceIDs).Error; err != nil {
+		return fmt.Errorf("failed to fetch user's workspaces: %w", err)
+	}
+	for _, workspaceID := range workspaceIDs {
+		admin, err := workspaceAdmin(tx, workspaceID, actorID)
+		if err != nil {
+			return err
+		}
+		if !admin {
+			return fmt.Errorf("%w: user belongs to workspaces you do not administer", ErrForbidden)
+		}
+	}
+	return nil
+}
+
+// checkSuccessor allows successorID to take over the groups of user, and the
+// todos they added to other groups, only if they are already a member of
+// every workspace those are in, so that deleting a user cannot bring anyone
+// into a workspace.
+func checkSuccessor(tx *gorm.DB, user *models.User, successorID uint) error {
+	var workspaceIDs []uint
+	err := models.AllWorkspaces(tx).Unscoped().Model(&models.Group{}).
+		Where("user_id = ? OR id IN (?)", user.ID, models.AllWorkspaces(tx).Unscoped().Model(&models.Todo{}).Where("user_id = ? AND group_id IS NOT NULL", user.ID).Select("group_id")).
+		Distinct("workspace_id").Pluck("workspace_id", &workspaceIDs).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch user's workspaces: %w", err)
+	}
+	for _, workspaceID := range workspaceIDs {
+		role, err := workspaceRole(tx, workspaceID, successorID)
+		if err != nil {
+			return err
+		}
+		if role == "" {
+			return fmt.Errorf("%w: the successor must be a member of every workspace the user's groups are in", ErrInvalidInput)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-app/graph/model"
+	"todo-app/models"
+)
+
+func TestUserAdministration(t *testing.T) {
+	db := newTestDB(t)
+	root := &models.User{Email: "root@example.com", Password: "x", IsAdmin: true}
+	admin := &models.User{Email: "ann@example.com", Password: "x"}
+	member := &models.User{Email: "bob@example.com", Password: "x"}
+	shared := &models.User{Email: "cid@example.com", Password: "x"}
+	outsider := &models.User{Email: "dee@example.com", Password: "x"}
+	create(t, db, root, admin, member, shared, outsider)
+	first := &models.Workspace{Name: "First"}
+	second := &models.Workspace{Name: "Second"}
+	create(t, db, first, second)
+	create(t, db,
+		&models.WorkspaceMember{WorkspaceID: first.ID, UserID: admin.ID, Role: models.WorkspaceRoleAdmin},
+		&models.WorkspaceMember{WorkspaceID: first.ID, UserID: member.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: first.ID, UserID: shared.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: second.ID, UserID: shared.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: second.ID, UserID: outsider.ID, Role: models.WorkspaceRoleMember},
+	)
+
+	ctx := models.WithWorkspace(context.Background(), first.ID)
+	r := NewResolver(db).within(ctx)
+	query := &queryResolver{r}
+	mutation := &mutationResolver{r}
+
+	users, err := query.Users(ctx, id(admin.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 {
+		t.Errorf("Users listed %d users, want the 3 members of the workspace", len(users))
+	}
+	if _, err := query.Users(ctx, id(member.ID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Users for a member: error = %v, want ErrForbidden", err)
+	}
+	if _, err := query.ManagedUser(ctx, id(outsider.ID), id(admin.ID)); err == nil || errors.Is(err, ErrForbidden) {
+		t.Errorf("ManagedUser of another workspace's user: error = %v, want not found", err)
+	}
+	if _, err := mutation.DeleteUser(ctx, id(shared.ID), id(admin.ID), nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("DeleteUser of a user in another workspace too: error = %v, want ErrForbidden", err)
+	}
+	if _, err := mutation.UpdateUserAdmin(ctx, id(member.ID), id(admin.ID), model.UpdateUserAdminInput{IsAdmin: true}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("UpdateUserAdmin by a workspace admin: error = %v, want ErrForbidden", err)
+	}
+
+	deleted, err := mutation.DeleteUser(ctx, id(member.ID), id(admin.ID), nil)
+	if err != nil || !deleted {
+		t.Errorf("DeleteUser of a member = %v, %v; want deleted", deleted, err)
+	}
+
+	// Being a global admin administers no workspace.
+	if _, err := query.Users(ctx, id(root.ID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Users for a global admin: error = %v, want ErrForbidden", err)
+	}
+	if _, err := mutation.DeleteUser(ctx, id(shared.ID), id(root.ID), nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("DeleteUser by a global admin: error = %v, want ErrForbidden", err)
+	}
+	create(t, db, &models.WorkspaceMember{WorkspaceID: second.ID, UserID: admin.ID, Role: models.WorkspaceRoleAdmin})
+	deleted, err = mutation.DeleteUser(ctx, id(shared.ID), id(admin.ID), nil)
+	if err != nil || !deleted {
+		t.Errorf("DeleteUser by an admin of all their workspaces = %v, %v; want deleted", deleted, err)
+	}
+	if _, err := mutation.UpdateUserAdmin(ctx, id(admin.ID), id(root.ID), model.UpdateUserAdminInput{IsAdmin: true}); err != nil {
+		t.Errorf("UpdateUserAdmin by a global admin: %v", err)
+	}
+}
+
+func TestDeleteUserSuccessorMustBeMember(t *testing.T) {
+	db := newTestDB(t)
+	admin := &models.User{Email: "ann@example.com", Password: "x"}
+	leaving := &models.User{Email: "bob@example.com", Password: "x"}
+	outsider := &models.User{Email: "eve@example.com", Password: "x"}
+	colleague := &models.User{Email: "cid@example.com", Password: "x"}
+	create(t, db, admin, leaving, outsider, colleague)
+	workspace := &models.Workspace{Name: "Corp"}
+	elsewhere := &models.Workspace{Name: "Elsewhere"}
+	create(t, db, workspace, elsewhere)
+	create(t, db,
+		&models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: admin.ID, Role: models.WorkspaceRoleAdmin},
+		&models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: leaving.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: colleague.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: elsewhere.ID, UserID: outsider.ID, Role: models.WorkspaceRoleMember},
+	)
+	group := &models.Group{Name: "Plans", UserID: leaving.ID, WorkspaceID: workspace.ID}
+	create(t, db, group)
+
+	ctx := models.WithWorkspace(context.Background(), workspace.ID)
+	mutation := &mutationResolver{NewResolver(db).within(ctx)}
+	outsiderID := id(outsider.ID)
+	if _, err := mutation.DeleteUser(ctx, id(leaving.ID), id(admin.ID), &outsiderID); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("DeleteUser with an outsider as successor: error = %v, want ErrInvalidInput", err)
+	}
+	role, err := workspaceRole(db, workspace.ID, outsider.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != "" {
+		t.Errorf("outsider joined the workspace as %q", role)
+	}
+
+	colleagueID := id(colleague.ID)
+	if deleted, err := mutation.DeleteUser(ctx, id(leaving.ID), id(admin.ID), &colleagueID); err != nil || !deleted {
+		t.Errorf("DeleteUser with a member as successor = %v, %v; want deleted", deleted, err)
+	}
+}
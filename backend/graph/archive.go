@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// archivedGroupsSQL selects the IDs of archived groups. Their todos are left
+// out of the active todo view unless asked for by group.
+const archivedGroupsSQL = `SELECT id FROM groups WHERE archived_at IS NOT NULL`
+
+// applyGroupView narrows query to the groups shown in view.
+func applyGroupView(query *gorm.DB, view model.GroupView) *gorm.DB {
+	switch view {
+	case model.GroupViewArchived:
+		return query.Where("archived_at IS NOT NULL")
+	case model.GroupViewAll:
+		return query
+	default:
+		return query.Where("archived_at IS NULL")
+	}
+}
+
+// setGroupArchived archives or unarchives group together with its
+// subgroups, recording the change on each. userID must own every one, and a
+// subgroup cannot be unarchived while its parent is archived.
+func setGroupArchived(tx *gorm.DB, group *models.Group, archived bool, userID uint) error {
+	if !archived && group.ParentID != nil {
+		var parent models.Group
+		if err := tx.First(&parent, *group.ParentID).Error; err != nil {
+			return fmt.Errorf("parent group not found: %w", err)
+		}
+		if parent.ArchivedAt != nil {
+			return fmt.Errorf("%w: unarchive the parent group first", ErrInvalidInput)
+		}
+	}
+
+	subtree, err := groupSubtree(tx, group.ID)
+	if err != nil {
+		return err
+	}
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+	for _, id := range subtree {
+		g, err := groupAccess(tx, id, userID, models.RoleOwner)
+		if err != nil {
+			return err
+		}
+		if (g.ArchivedAt != nil) == archived {
+			continue
+		}
+		before := groupSnapshot(g)
+		g.ArchivedAt = archivedAt
+		if err := tx.Model(g).Update("archived_at", g.ArchivedAt).Error; err != nil {
+			return fmt.Errorf("failed to archive group: %w", err)
+		}
+		if err := recordRevision(tx, entityGroup, g.ID, userID, models.RevisionUpdate, diffSnapshots(before, groupSnapshot(g))); err != nil {
+			return err
+		}
+		if g.ID == group.ID {
+			*group = *g
+		}
+	}
+	return nil
+}
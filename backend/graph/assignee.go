@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"todo-app/models"
+	"todo-app/scheduler"
+
+	"gorm.io/gorm"
+)
+
+// assignable reports whether userID may be assigned todo: any member of its
+// group, or only its creator when it is ungrouped.
+func assignable(tx *gorm.DB, todo *models.Todo, userID uint) (bool, error) {
+	if todo.GroupID == nil {
+		return userID == todo.UserID, nil
+	}
+	role, err := groupRole(tx, *todo.GroupID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+// setAssignee assigns todo to the user with ID assigneeID, or unassigns it
+// when assigneeID is empty.
+func setAssignee(tx *gorm.DB, todo *models.Todo, assigneeID string) error {
+	if assigneeID == "" {
+		todo.AssigneeID = nil
+		return nil
+	}
+	aid, err := strconv.ParseUint(assigneeID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid assignee ID: %w", err)
+	}
+	ok, err := assignable(tx, todo, uint(aid))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: assignee must be a member of the todo's group", ErrInvalidInput)
+	}
+	id := uint(aid)
+	todo.AssigneeID = &id
+	return nil
+}
+
+// detachInvalidAssignee clears todo's assignee when they may no longer be
+// assigned it, e.g. after the todo moved to another group.
+func detachInvalidAssignee(tx *gorm.DB, todo *models.Todo) error {
+	if todo.AssigneeID == nil {
+		return nil
+	}
+	ok, err := assignable(tx, todo, *todo.AssigneeID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		todo.AssigneeID = nil
+	}
+	return nil
+}
+
+// notifyAssignee schedules a notification when todo has a new assignee
+// other than actorID, who made the change.
+func notifyAssignee(tx *gorm.DB, todo *models.Todo, previous *uint, actorID uint) error {
+	if todo.AssigneeID == nil || *todo.AssigneeID == actorID {
+		return nil
+	}
+	if previous != nil && *previous == *todo.AssigneeID {
+		return nil
+	}
+	return scheduler.ScheduleAssignment(tx, todo, actorID)
+}
+
+// unassignMember unassigns userID from the todos of groupID, recording the
+// change on each as actorID.
+func unassignMember(tx *gorm.DB, groupID, userID, actorID uint) error {
+	var todoIDs []uint
+	if err := tx.Model(&models.Todo{}).Where("group_id = ? AND assignee_id = ?", groupID, userID).Pluck("id", &todoIDs).Error; err != nil {
+		return fmt.Errorf("failed to fetch assigned todos: %w", err)
+	}
+	if len(todoIDs) == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.Todo{}).Where("id IN ?", todoIDs).Update("assignee_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to unassign todos: %w", err)
+	}
+	for _, todoID := range todoIDs {
+		changes := models.FieldChanges{"assignee_id": {Before: userID, After: nil}}
+		if err := recordRevision(tx, entityTodo, todoID, actorID, models.RevisionUpdate, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
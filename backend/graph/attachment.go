@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"todo-app/models"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var errBlobsNotConfigured = errors.New("attachment storage is not configured")
+
+// sniffContentType detects the type of file from its first bytes, ignoring
+// whatever the client claimed, and rewinds it.
+func sniffContentType(file io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// sanitizeFilename keeps the base name of a client-supplied file name
+// without control characters, so that it is safe to echo back in headers.
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		name = "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+func newStorageKey(attachment *models.Attachment) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	return fmt.Sprintf("attachments/%d/%d/%s", attachment.UserID, attachment.TodoID, hex.EncodeToString(buf)), nil
+}
+
+// openAttachment returns an attachment of the workspace r is scoped to
+// together with its contents, provided userID is still a member of the
+// workspace, can see the todo the attachment belongs to and the todo is not
+// deleted.
+func (r *Resolver) openAttachment(ctx context.Context, attachmentID, userID uint) (*models.Attachment, io.ReadCloser, error) {
+	if r.Blobs == nil {
+		return nil, nil, errBlobsNotConfigured
+	}
+	workspaceID, ok := currentWorkspace(r.DB)
+	if !ok {
+		return nil, nil, fmt.Errorf("attachment not found: %w", models.ErrNoWorkspace)
+	}
+	role, err := workspaceRole(r.DB, workspaceID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if role == "" {
+		return nil, nil, fmt.Errorf("attachment not found: %w", gorm.ErrRecordNotFound)
+	}
+
+	var attachment models.Attachment
+	err = r.DB.Joins("JOIN todos ON todos.id = attachments.todo_id AND todos.deleted_at IS NULL").
+		Where("attachments.id = ?", attachmentID).
+		Where(models.TodoAccess(userID, models.RoleViewer)).
+		First(&attachment).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("attachment not found: %w", err)
+	}
+
+	body, err := r.Blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &attachment, body, nil
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"todo-app/blobstore"
+	"todo-app/models"
+)
+
+func TestOpenAttachmentChecksWorkspace(t *testing.T) {
+	db := newTestDB(t)
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	first := &models.Workspace{Name: "First"}
+	second := &models.Workspace{Name: "Second"}
+	create(t, db, first, second)
+	create(t, db,
+		&models.WorkspaceMember{WorkspaceID: first.ID, UserID: user.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: second.ID, UserID: user.ID, Role: models.WorkspaceRoleMember},
+	)
+	todo := &models.Todo{Title: "A", UserID: user.ID, WorkspaceID: first.ID}
+	create(t, db, todo)
+	attachment := &models.Attachment{TodoID: todo.ID, UserID: user.ID, WorkspaceID: first.ID, Filename: "a.txt", ContentType: "text/plain", Size: 2, StorageKey: "a"}
+	create(t, db, attachment)
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blobs.Put(context.Background(), "a", strings.NewReader("hi"), 2, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	resolver := NewResolver(db)
+	resolver.Blobs = blobs
+	open := func(workspaceID uint) error {
+		ctx := models.WithWorkspace(context.Background(), workspaceID)
+		_, body, err := resolver.within(ctx).openAttachment(ctx, attachment.ID, user.ID)
+		if err == nil {
+			body.Close()
+		}
+		return err
+	}
+
+	if err := open(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := open(second.ID); err == nil {
+		t.Error("opened an attachment of another workspace")
+	}
+	if err := models.AllWorkspaces(db).Where("workspace_id = ? AND user_id = ?", first.ID, user.ID).Delete(&models.WorkspaceMember{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := open(first.ID); err == nil {
+		t.Error("opened an attachment after leaving its workspace")
+	}
+}
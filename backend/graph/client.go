@@ -2,8 +2,14 @@ package graph
 
 import (
         "context"
+        "fmt"
+        "io"
+        "strconv"
+        "time"
         "todo-app/graph/model"
         "todo-app/models"
+
+        "github.com/99designs/gqlgen/graphql"
 )
 
 type Client struct {
@@ -15,7 +21,7 @@ func NewClient(resolver *Resolver) *Client {
 }
 
 func (c *Client) CreateUser(ctx context.Context, email, password string) (*models.User, error) {
-        mutation := &mutationResolver{c.resolver}
+        mutation := &mutationResolver{c.resolver.within(ctx)}
         return mutation.CreateUser(ctx, model.CreateUserInput{
                 Email:    email,
                 Password: password,
@@ -23,92 +29,159 @@ func (c *Client) CreateUser(ctx context.Context, email, password string) (*model
 }
 
 func (c *Client) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-        query := &queryResolver{c.resolver}
+        query := &queryResolver{c.resolver.within(ctx)}
         return query.UserByEmail(ctx, email)
 }
 
 func (c *Client) GetUserByID(ctx context.Context, id string) (*models.User, error) {
-        query := &queryResolver{c.resolver}
+        query := &queryResolver{c.resolver.within(ctx)}
         return query.User(ctx, id)
 }
 
-func (c *Client) GetUsers(ctx context.Context) ([]*models.User, error) {
-        query := &queryResolver{c.resolver}
-        return query.Users(ctx)
+// GetUsers lists the members of the current workspace for one of its admins.
+func (c *Client) GetUsers(ctx context.Context, userID string) ([]*models.User, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Users(ctx, userID)
+}
+
+// GetManagedUser returns a member of the current workspace with their todos
+// in it for one of its admins.
+func (c *Client) GetManagedUser(ctx context.Context, id, userID string) (*models.User, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.ManagedUser(ctx, id, userID)
 }
 
-func (c *Client) DeleteUser(ctx context.Context, id string) (bool, error) {
-        mutation := &mutationResolver{c.resolver}
-        return mutation.DeleteUser(ctx, id)
+// DeleteUser deletes a user. When successorID is set, their groups and the
+// todos they added to shared groups are handed to that user instead.
+func (c *Client) DeleteUser(ctx context.Context, id, userID string, successorID *string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteUser(ctx, id, userID, successorID)
 }
 
-func (c *Client) UpdateUserAdmin(ctx context.Context, id string, isAdmin bool) (*models.User, error) {
-        mutation := &mutationResolver{c.resolver}
-        return mutation.UpdateUserAdmin(ctx, id, model.UpdateUserAdminInput{
+func (c *Client) UpdateUserAdmin(ctx context.Context, id, userID string, isAdmin bool) (*models.User, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateUserAdmin(ctx, id, userID, model.UpdateUserAdminInput{
                 IsAdmin: isAdmin,
         })
 }
 
 func (c *Client) GetUserCount(ctx context.Context) (int, error) {
-        query := &queryResolver{c.resolver}
+        query := &queryResolver{c.resolver.within(ctx)}
         return query.UserCount(ctx)
 }
 
-func (c *Client) CreateTodo(ctx context.Context, userID, title, description string, groupID *string) (*models.Todo, error) {
-        mutation := &mutationResolver{c.resolver}
-        return mutation.CreateTodo(ctx, userID, model.CreateTodoInput{
-                Title:       title,
-                Description: description,
-                GroupID:     groupID,
-        })
+func (c *Client) CreateTodo(ctx context.Context, userID string, input model.CreateTodoInput) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateTodo(ctx, userID, input)
 }
 
-func (c *Client) GetTodos(ctx context.Context, userID string) ([]*models.Todo, error) {
-        query := &queryResolver{c.resolver}
-        return query.Todos(ctx, userID)
+func (c *Client) GetTodos(ctx context.Context, userID string, filter *model.TodoFilter, sort *model.TodoSort) ([]*models.Todo, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Todos(ctx, userID, filter, sort)
 }
 
 func (c *Client) GetTodo(ctx context.Context, id, userID string) (*models.Todo, error) {
-        query := &queryResolver{c.resolver}
+        query := &queryResolver{c.resolver.within(ctx)}
         return query.Todo(ctx, id, userID)
 }
 
-func (c *Client) UpdateTodo(ctx context.Context, id, userID string, title, description *string, completed *bool, groupID *string) (*models.Todo, error) {
-        mutation := &mutationResolver{c.resolver}
-        return mutation.UpdateTodo(ctx, id, userID, model.UpdateTodoInput{
-                Title:       title,
-                Description: description,
-                Completed:   completed,
-                GroupID:     groupID,
-        })
+func (c *Client) UpdateTodo(ctx context.Context, id, userID string, input model.UpdateTodoInput) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateTodo(ctx, id, userID, input)
 }
 
 func (c *Client) DeleteTodo(ctx context.Context, id, userID string) (bool, error) {
-        mutation := &mutationResolver{c.resolver}
+        mutation := &mutationResolver{c.resolver.within(ctx)}
         return mutation.DeleteTodo(ctx, id, userID)
 }
 
-func (c *Client) CreateGroup(ctx context.Context, userID, name string, description, color *string) (*models.Group, error) {
-        mutation := &mutationResolver{c.resolver}
+func (c *Client) SkipTodoOccurrence(ctx context.Context, id, userID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.SkipTodoOccurrence(ctx, id, userID)
+}
+
+func (c *Client) CreateGroup(ctx context.Context, userID, name string, description, color, parentID *string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
         return mutation.CreateGroup(ctx, userID, model.CreateGroupInput{
                 Name:        name,
                 Description: description,
                 Color:       color,
+                ParentID:    parentID,
         })
 }
 
-func (c *Client) GetGroups(ctx context.Context, userID string) ([]*models.Group, error) {
-        query := &queryResolver{c.resolver}
-        return query.Groups(ctx, userID)
+func (c *Client) GetGroups(ctx context.Context, userID string, view *model.GroupView) ([]*models.Group, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Groups(ctx, userID, view)
 }
 
 func (c *Client) GetGroup(ctx context.Context, id, userID string) (*models.Group, error) {
-        query := &queryResolver{c.resolver}
+        query := &queryResolver{c.resolver.within(ctx)}
         return query.Group(ctx, id, userID)
 }
 
+// GetGroupTodos returns the todos in a group the user can see, after
+// checking they can see the group itself.
+func (c *Client) GetGroupTodos(ctx context.Context, id, userID string, filter *model.TodoFilter, sort *model.TodoSort) ([]*models.Todo, error) {
+        resolver := c.resolver.within(ctx)
+        group, err := (&queryResolver{resolver}).Group(ctx, id, userID)
+        if err != nil {
+                return nil, err
+        }
+        return (&groupResolver{resolver}).Todos(ctx, group, userID, filter, sort)
+}
+
+func (c *Client) GetSmartLists(ctx context.Context, userID string) ([]*models.SmartList, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.SmartLists(ctx, userID)
+}
+
+func (c *Client) GetSmartList(ctx context.Context, id, userID string) (*models.SmartList, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.SmartList(ctx, id, userID)
+}
+
+func (c *Client) CreateSmartList(ctx context.Context, userID string, input model.CreateSmartListInput) (*models.SmartList, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateSmartList(ctx, userID, input)
+}
+
+func (c *Client) UpdateSmartList(ctx context.Context, id, userID string, input model.UpdateSmartListInput) (*models.SmartList, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateSmartList(ctx, id, userID, input)
+}
+
+func (c *Client) DeleteSmartList(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteSmartList(ctx, id, userID)
+}
+
+// GetSmartListTodos returns the todos a smart list matches right now.
+func (c *Client) GetSmartListTodos(ctx context.Context, list *models.SmartList, sort *model.TodoSort) ([]*models.Todo, error) {
+        resolver := &smartListResolver{c.resolver.within(ctx)}
+        return resolver.Todos(ctx, list, sort)
+}
+
+// GetSmartListCount counts the todos a smart list matches right now.
+func (c *Client) GetSmartListCount(ctx context.Context, list *models.SmartList) (int, error) {
+        resolver := &smartListResolver{c.resolver.within(ctx)}
+        return resolver.Count(ctx, list)
+}
+
+// GetSmartListCounts counts the todos several smart lists match right now,
+// keyed by smart list ID.
+func (c *Client) GetSmartListCounts(ctx context.Context, lists []*models.SmartList) (map[uint]int, error) {
+        return smartListCounts(c.resolver.within(ctx).DB, lists, time.Now())
+}
+
+// GetGroupStats computes the stats of several groups at once, keyed by
+// group ID.
+func (c *Client) GetGroupStats(ctx context.Context, groups []*models.Group) (map[uint]*model.GroupStats, error) {
+        return groupStats(c.resolver.within(ctx).DB, groups)
+}
+
 func (c *Client) UpdateGroup(ctx context.Context, id, userID string, name, description, color *string) (*models.Group, error) {
-        mutation := &mutationResolver{c.resolver}
+        mutation := &mutationResolver{c.resolver.within(ctx)}
         return mutation.UpdateGroup(ctx, id, userID, model.UpdateGroupInput{
                 Name:        name,
                 Description: description,
@@ -116,7 +189,531 @@ func (c *Client) UpdateGroup(ctx context.Context, id, userID string, name, descr
         })
 }
 
-func (c *Client) DeleteGroup(ctx context.Context, id, userID string) (bool, error) {
-        mutation := &mutationResolver{c.resolver}
-        return mutation.DeleteGroup(ctx, id, userID)
+func (c *Client) DeleteGroup(ctx context.Context, id, userID string, mode *model.GroupDeleteMode) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteGroup(ctx, id, userID, mode)
+}
+
+func (c *Client) ArchiveGroup(ctx context.Context, id, userID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.ArchiveGroup(ctx, id, userID)
+}
+
+func (c *Client) UnarchiveGroup(ctx context.Context, id, userID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UnarchiveGroup(ctx, id, userID)
+}
+
+func (c *Client) MoveGroup(ctx context.Context, id, userID string, parentID *string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.MoveGroup(ctx, id, userID, parentID)
+}
+
+func (c *Client) MoveTodo(ctx context.Context, id, userID, statusID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.MoveTodo(ctx, id, userID, statusID)
+}
+
+func (c *Client) GetStatuses(ctx context.Context, userID string, groupID *string) ([]*models.Status, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Statuses(ctx, userID, groupID)
+}
+
+func (c *Client) CreateStatus(ctx context.Context, userID string, input model.CreateStatusInput) (*models.Status, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateStatus(ctx, userID, input)
+}
+
+func (c *Client) UpdateStatus(ctx context.Context, id, userID string, input model.UpdateStatusInput) (*models.Status, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateStatus(ctx, id, userID, input)
+}
+
+func (c *Client) DeleteStatus(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteStatus(ctx, id, userID)
+}
+
+func (c *Client) CreateReminder(ctx context.Context, userID string, input model.CreateReminderInput) (*models.Reminder, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateReminder(ctx, userID, input)
+}
+
+func (c *Client) GetReminders(ctx context.Context, todoID, userID string) ([]*models.Reminder, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Reminders(ctx, todoID, userID)
+}
+
+func (c *Client) DeleteReminder(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteReminder(ctx, id, userID)
+}
+
+func (c *Client) GetNotifications(ctx context.Context, userID string, unreadOnly bool) ([]*models.Notification, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Notifications(ctx, userID, &unreadOnly)
+}
+
+func (c *Client) MarkNotificationRead(ctx context.Context, id, userID string) (*models.Notification, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.MarkNotificationRead(ctx, id, userID)
+}
+
+func (c *Client) GetTrash(ctx context.Context, userID string) (*model.Trash, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Trash(ctx, userID)
+}
+
+func (c *Client) RestoreTodo(ctx context.Context, id, userID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RestoreTodo(ctx, id, userID)
+}
+
+func (c *Client) RestoreGroup(ctx context.Context, id, userID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RestoreGroup(ctx, id, userID)
+}
+
+func (c *Client) PurgeTodo(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.PurgeTodo(ctx, id, userID)
+}
+
+func (c *Client) PurgeGroup(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.PurgeGroup(ctx, id, userID)
+}
+
+func (c *Client) EmptyTrash(ctx context.Context, userID string) (int, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.EmptyTrash(ctx, userID)
+}
+
+func (c *Client) GetTodoHistory(ctx context.Context, id, userID string) ([]*models.Revision, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.TodoHistory(ctx, id, userID)
+}
+
+func (c *Client) GetGroupHistory(ctx context.Context, id, userID string) ([]*models.Revision, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.GroupHistory(ctx, id, userID)
+}
+
+func (c *Client) RevertTodo(ctx context.Context, id, userID, revisionID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RevertTodo(ctx, id, userID, revisionID)
+}
+
+func (c *Client) RevertGroup(ctx context.Context, id, userID, revisionID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RevertGroup(ctx, id, userID, revisionID)
+}
+
+func (c *Client) Undo(ctx context.Context, token, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.Undo(ctx, token, userID)
+}
+
+func (c *Client) GetComments(ctx context.Context, todoID, userID string, includeActivity bool) ([]*models.Comment, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        todo, err := query.Todo(ctx, todoID, userID)
+        if err != nil {
+                return nil, err
+        }
+        return (&todoResolver{c.resolver.within(ctx)}).Comments(ctx, todo, &includeActivity)
+}
+
+func (c *Client) AddComment(ctx context.Context, todoID, userID, body string) (*models.Comment, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.AddComment(ctx, todoID, userID, body)
+}
+
+func (c *Client) UpdateComment(ctx context.Context, id, userID, body string) (*models.Comment, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateComment(ctx, id, userID, body)
+}
+
+func (c *Client) DeleteComment(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteComment(ctx, id, userID)
+}
+
+func (c *Client) GetAttachments(ctx context.Context, todoID, userID string) ([]*models.Attachment, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Attachments(ctx, todoID, userID)
+}
+
+func (c *Client) UploadAttachment(ctx context.Context, todoID, userID string, file graphql.Upload) (*models.Attachment, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UploadAttachment(ctx, todoID, userID, file)
+}
+
+func (c *Client) DeleteAttachment(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteAttachment(ctx, id, userID)
+}
+
+// OpenAttachment returns an attachment and its contents for download. It is
+// not part of the GraphQL schema because the contents are streamed.
+func (c *Client) OpenAttachment(ctx context.Context, id, userID uint) (*models.Attachment, io.ReadCloser, error) {
+        return c.resolver.within(ctx).openAttachment(ctx, id, userID)
+}
+
+// MaxAttachmentSize is the largest upload UploadAttachment accepts, in bytes.
+func (c *Client) MaxAttachmentSize() int64 {
+        return c.resolver.MaxAttachmentSize
+}
+
+func (c *Client) Search(ctx context.Context, userID, query string, limit *int) ([]*model.SearchResult, error) {
+        q := &queryResolver{c.resolver.within(ctx)}
+        return q.Search(ctx, userID, query, limit)
+}
+
+func (c *Client) AddDependency(ctx context.Context, todoID, blockerID, userID string) (*models.Dependency, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.AddDependency(ctx, todoID, blockerID, userID)
+}
+
+func (c *Client) RemoveDependency(ctx context.Context, todoID, blockerID, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RemoveDependency(ctx, todoID, blockerID, userID)
+}
+
+func (c *Client) GetDependencies(ctx context.Context, todoID, userID string) (blockedBy, blocking []*models.Todo, blocked bool, err error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        todo, err := query.Todo(ctx, todoID, userID)
+        if err != nil {
+                return nil, nil, false, err
+        }
+        resolver := &todoResolver{c.resolver.within(ctx)}
+        if blockedBy, err = resolver.BlockedBy(ctx, todo); err != nil {
+                return nil, nil, false, err
+        }
+        if blocking, err = resolver.Blocking(ctx, todo); err != nil {
+                return nil, nil, false, err
+        }
+        if blocked, err = resolver.Blocked(ctx, todo); err != nil {
+                return nil, nil, false, err
+        }
+        return blockedBy, blocking, blocked, nil
+}
+
+func (c *Client) GetDependencyGraph(ctx context.Context, groupID, userID string) (*model.DependencyGraph, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.DependencyGraph(ctx, groupID, userID)
+}
+
+func (c *Client) StartTimer(ctx context.Context, todoID, userID string, note *string) (*models.TimeEntry, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.StartTimer(ctx, todoID, userID, note)
+}
+
+func (c *Client) StopTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.StopTimer(ctx, userID)
+}
+
+func (c *Client) GetRunningTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.RunningTimer(ctx, userID)
+}
+
+func (c *Client) GetTimeEntries(ctx context.Context, userID string, todoID *string, from, to *time.Time) ([]*models.TimeEntry, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.TimeEntries(ctx, userID, todoID, from, to)
+}
+
+func (c *Client) AddTimeEntry(ctx context.Context, todoID, userID string, input model.TimeEntryInput) (*models.TimeEntry, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.AddTimeEntry(ctx, todoID, userID, input)
+}
+
+func (c *Client) UpdateTimeEntry(ctx context.Context, id, userID string, input model.UpdateTimeEntryInput) (*models.TimeEntry, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateTimeEntry(ctx, id, userID, input)
+}
+
+func (c *Client) DeleteTimeEntry(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteTimeEntry(ctx, id, userID)
+}
+
+// GetTodoTime returns the time entries of a todo together with its total.
+func (c *Client) GetTodoTime(ctx context.Context, todoID, userID string) (*model.TodoTime, []*models.TimeEntry, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        todo, err := query.Todo(ctx, todoID, userID)
+        if err != nil {
+                return nil, nil, err
+        }
+        resolver := &todoResolver{c.resolver.within(ctx)}
+        entries, err := resolver.TimeEntries(ctx, todo)
+        if err != nil {
+                return nil, nil, err
+        }
+        tracked, err := resolver.TrackedSeconds(ctx, todo)
+        if err != nil {
+                return nil, nil, err
+        }
+        return &model.TodoTime{Todo: todo, TrackedSeconds: tracked, EstimateMinutes: todo.EstimateMinutes}, entries, nil
+}
+
+func (c *Client) GetGroupTime(ctx context.Context, groupID, userID string) (*model.GroupTime, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.GroupTime(ctx, groupID, userID)
+}
+
+func (c *Client) GetTimeEntryTodo(ctx context.Context, entry *models.TimeEntry) (*models.Todo, error) {
+        resolver := &timeEntryResolver{c.resolver.within(ctx)}
+        return resolver.Todo(ctx, entry)
+}
+
+func (c *Client) GetTemplates(ctx context.Context, userID string) ([]*models.Template, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Templates(ctx, userID)
+}
+
+func (c *Client) GetTemplate(ctx context.Context, id, userID string) (*models.Template, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Template(ctx, id, userID)
+}
+
+func (c *Client) CreateTemplate(ctx context.Context, userID string, input model.CreateTemplateInput) (*models.Template, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateTemplate(ctx, userID, input)
+}
+
+func (c *Client) UpdateTemplate(ctx context.Context, id, userID string, input model.UpdateTemplateInput) (*models.Template, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateTemplate(ctx, id, userID, input)
+}
+
+func (c *Client) DeleteTemplate(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeleteTemplate(ctx, id, userID)
+}
+
+func (c *Client) SaveGroupAsTemplate(ctx context.Context, groupID, userID, name string) (*models.Template, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.SaveGroupAsTemplate(ctx, groupID, userID, name)
+}
+
+func (c *Client) InstantiateTemplate(ctx context.Context, id, userID string, input *model.InstantiateTemplateInput) (*model.TemplateInstance, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.InstantiateTemplate(ctx, id, userID, input)
+}
+
+// TemplateVariables returns the names of the variables a template uses.
+func (c *Client) TemplateVariables(template *models.Template) []string {
+        return templateVariables(template)
+}
+
+func (c *Client) GetPins(ctx context.Context, userID string) (*model.Pins, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Pins(ctx, userID)
+}
+
+func (c *Client) PinGroup(ctx context.Context, id, userID string, position *int) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.PinGroup(ctx, id, userID, position)
+}
+
+func (c *Client) UnpinGroup(ctx context.Context, id, userID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UnpinGroup(ctx, id, userID)
+}
+
+func (c *Client) PinTodo(ctx context.Context, id, userID string, position *int) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.PinTodo(ctx, id, userID, position)
+}
+
+func (c *Client) UnpinTodo(ctx context.Context, id, userID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UnpinTodo(ctx, id, userID)
+}
+
+// PinnedGroupIDs returns the IDs of the groups the user pinned.
+func (c *Client) PinnedGroupIDs(ctx context.Context, userID string) (map[uint]bool, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        pins, err := userPins(c.resolver.within(ctx).DB, uint(uid), entityGroup)
+        if err != nil {
+                return nil, err
+        }
+        pinned := make(map[uint]bool, len(pins))
+        for _, pin := range pins {
+                pinned[pin.EntityID] = true
+        }
+        return pinned, nil
+}
+
+// PriorityFromLevel returns the priority stored as level.
+func (c *Client) PriorityFromLevel(level int) model.Priority {
+        return priorityFromLevel(level)
+}
+
+// QuickAddTodo creates a todo from quick-add text in input.Title and returns
+// it together with what was read from the text.
+func (c *Client) QuickAddTodo(ctx context.Context, userID string, input model.CreateTodoInput) (*models.Todo, *model.QuickAddParse, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        parsed, err := query.ParseQuickAdd(ctx, userID, input.Title, input.TimeZone)
+        if err != nil {
+                return nil, nil, err
+        }
+        if err := applyQuickAdd(&input, parsed); err != nil {
+                return nil, nil, err
+        }
+        input.QuickAdd = nil
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        todo, err := mutation.CreateTodo(ctx, userID, input)
+        if err != nil {
+                return nil, nil, err
+        }
+        return todo, parsed, nil
+}
+
+func (c *Client) ParseQuickAdd(ctx context.Context, userID, text string, timeZone *string) (*model.QuickAddParse, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.ParseQuickAdd(ctx, userID, text, timeZone)
+}
+
+func (c *Client) UpdateUserSettings(ctx context.Context, userID string, input model.UpdateUserSettingsInput) (*models.User, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateUserSettings(ctx, userID, input)
+}
+
+func (c *Client) SnoozeTodo(ctx context.Context, id, userID string, until *time.Time, preset *model.SnoozePreset) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.SnoozeTodo(ctx, id, userID, until, preset)
+}
+
+func (c *Client) UnsnoozeTodo(ctx context.Context, id, userID string) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UnsnoozeTodo(ctx, id, userID)
+}
+
+func (c *Client) SetSomeday(ctx context.Context, id, userID string, someday bool) (*models.Todo, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.SetSomeday(ctx, id, userID, someday)
+}
+
+func (c *Client) GetGroupMembers(ctx context.Context, groupID, userID string) ([]*models.GroupMember, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.GroupMembers(ctx, groupID, userID)
+}
+
+// MemberEmail returns the email address of the user behind member.
+func (c *Client) MemberEmail(ctx context.Context, member *models.GroupMember) (string, error) {
+        return c.resolver.within(ctx).GroupMember().Email(ctx, member)
+}
+
+func (c *Client) UpdateGroupMember(ctx context.Context, groupID, memberID, userID string, role model.GroupRole) (*models.GroupMember, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateGroupMember(ctx, groupID, memberID, userID, role)
+}
+
+func (c *Client) RemoveGroupMember(ctx context.Context, groupID, memberID, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RemoveGroupMember(ctx, groupID, memberID, userID)
+}
+
+func (c *Client) GetGroupInvitations(ctx context.Context, groupID, userID string) ([]*models.GroupInvitation, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.GroupInvitations(ctx, groupID, userID)
+}
+
+func (c *Client) GetInvitations(ctx context.Context, userID string) ([]*models.GroupInvitation, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Invitations(ctx, userID)
+}
+
+func (c *Client) InviteToGroup(ctx context.Context, groupID, userID, email string, role model.GroupRole) (*models.GroupInvitation, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.InviteToGroup(ctx, groupID, userID, email, role)
+}
+
+func (c *Client) RevokeInvitation(ctx context.Context, id, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RevokeInvitation(ctx, id, userID)
+}
+
+func (c *Client) AcceptInvitation(ctx context.Context, id, userID string) (*models.GroupMember, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.AcceptInvitation(ctx, id, userID)
+}
+
+func (c *Client) DeclineInvitation(ctx context.Context, id, userID string) (*models.GroupInvitation, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.DeclineInvitation(ctx, id, userID)
+}
+
+func (c *Client) TransferGroup(ctx context.Context, id, userID, newOwnerID string) (*models.Group, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.TransferGroup(ctx, id, userID, newOwnerID)
+}
+
+func (c *Client) GetWorkspaces(ctx context.Context, userID string) ([]*models.Workspace, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Workspaces(ctx, userID)
+}
+
+func (c *Client) GetWorkspace(ctx context.Context, id, userID string) (*models.Workspace, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.Workspace(ctx, id, userID)
+}
+
+// WorkspaceRole returns userID's role in workspace, or nil if they are not
+// a member.
+func (c *Client) WorkspaceRole(ctx context.Context, workspace *models.Workspace, userID string) (*model.WorkspaceRole, error) {
+        return c.resolver.within(ctx).Workspace().Role(ctx, workspace, userID)
+}
+
+// DefaultWorkspace returns the ID of the workspace userID joined first, or 0
+// if they belong to none. It is not part of the GraphQL schema because it
+// only seeds the workspace claim of new tokens.
+func (c *Client) DefaultWorkspace(ctx context.Context, userID uint) (uint, error) {
+        return defaultWorkspace(c.resolver.within(ctx).DB, userID)
+}
+
+func (c *Client) CreateWorkspace(ctx context.Context, userID, name string, allowedEmailDomains []string) (*models.Workspace, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.CreateWorkspace(ctx, userID, model.CreateWorkspaceInput{
+                Name:                name,
+                AllowedEmailDomains: allowedEmailDomains,
+        })
+}
+
+func (c *Client) UpdateWorkspace(ctx context.Context, id, userID string, name *string, allowedEmailDomains []string) (*models.Workspace, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateWorkspace(ctx, id, userID, model.UpdateWorkspaceInput{
+                Name:                name,
+                AllowedEmailDomains: allowedEmailDomains,
+        })
+}
+
+func (c *Client) GetWorkspaceMembers(ctx context.Context, workspaceID, userID string) ([]*models.WorkspaceMember, error) {
+        query := &queryResolver{c.resolver.within(ctx)}
+        return query.WorkspaceMembers(ctx, workspaceID, userID)
+}
+
+// WorkspaceMemberEmail returns the email address of the user behind member.
+func (c *Client) WorkspaceMemberEmail(ctx context.Context, member *models.WorkspaceMember) (string, error) {
+        return c.resolver.within(ctx).WorkspaceMember().Email(ctx, member)
+}
+
+func (c *Client) AddWorkspaceMember(ctx context.Context, workspaceID, userID, email string, role model.WorkspaceRole) (*models.WorkspaceMember, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.AddWorkspaceMember(ctx, workspaceID, userID, email, role)
+}
+
+func (c *Client) UpdateWorkspaceMember(ctx context.Context, workspaceID, memberID, userID string, role model.WorkspaceRole) (*models.WorkspaceMember, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.UpdateWorkspaceMember(ctx, workspaceID, memberID, userID, role)
+}
+
+func (c *Client) RemoveWorkspaceMember(ctx context.Context, workspaceID, memberID, userID string) (bool, error) {
+        mutation := &mutationResolver{c.resolver.within(ctx)}
+        return mutation.RemoveWorkspaceMember(ctx, workspaceID, memberID, userID)
 }
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"todo-app/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB returns a migrated database of its own for t, with the
+// workspace scope registered as in production.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Group{},
+		&models.Todo{},
+		&models.Status{},
+		&models.Reminder{},
+		&models.Job{},
+		&models.Notification{},
+		&models.Revision{},
+		&models.UndoToken{},
+		&models.Comment{},
+		&models.Attachment{},
+		&models.Dependency{},
+		&models.TimeEntry{},
+		&models.Template{},
+		&models.TemplateTodo{},
+		&models.GroupMember{},
+		&models.GroupInvitation{},
+		&models.Workspace{},
+		&models.WorkspaceMember{},
+		&models.SmartList{},
+		&models.Pin{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := MigrateGroupNames(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := models.RegisterWorkspaceScope(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// inTestWorkspace creates a workspace and returns db scoped to it, as a
+// request in it would be. db.Statement.Context is the request's context.
+func inTestWorkspace(t *testing.T, db *gorm.DB) *gorm.DB {
+	t.Helper()
+	workspace := &models.Workspace{Name: "Default"}
+	create(t, db, workspace)
+	return db.WithContext(models.WithWorkspace(context.Background(), workspace.ID))
+}
+
+// create inserts each record or fails t.
+func create(t *testing.T, db *gorm.DB, records ...interface{}) {
+	t.Helper()
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
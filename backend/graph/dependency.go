@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// openBlockers returns the IDs of the open, non-deleted todos that block
+// todoID.
+func openBlockers(tx *gorm.DB, todoID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.Dependency{}).
+		Joins("JOIN todos ON todos.id = dependencies.blocker_id AND todos.deleted_at IS NULL").
+		Where("dependencies.todo_id = ? AND todos.completed = ?", todoID, false).
+		Pluck("dependencies.blocker_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blockers: %w", err)
+	}
+	return ids, nil
+}
+
+// checkNotBlocked fails with ErrBlocked when todo still has open blockers.
+func checkNotBlocked(tx *gorm.DB, todo *models.Todo) error {
+	blockers, err := openBlockers(tx, todo.ID)
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		return fmt.Errorf("%w: %v", ErrBlocked, blockers)
+	}
+	return nil
+}
+
+// createsCycle reports whether making todoID blocked by blockerID would close
+// a cycle, i.e. whether blockerID already depends on todoID, directly or
+// through other todos. Like openBlockers it ignores todos in the trash.
+func createsCycle(tx *gorm.DB, todoID, blockerID uint) (bool, error) {
+	if todoID == blockerID {
+		return true, nil
+	}
+	var found int64
+	err := tx.Raw(`WITH RECURSIVE upstream(id) AS (
+			SELECT d.blocker_id FROM dependencies d
+			JOIN todos t ON t.id = d.blocker_id AND t.deleted_at IS NULL
+			WHERE d.todo_id = ?
+			UNION
+			SELECT d.blocker_id FROM dependencies d
+			JOIN upstream u ON d.todo_id = u.id
+			JOIN todos t ON t.id = d.blocker_id AND t.deleted_at IS NULL
+		)
+		SELECT COUNT(*) FROM upstream WHERE id = ?`, blockerID, todoID).Scan(&found).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check dependencies: %w", err)
+	}
+	return found > 0, nil
+}
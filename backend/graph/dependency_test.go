@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"testing"
+	"todo-app/models"
+)
+
+func TestCreatesCycle(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	a := &models.Todo{Title: "A", UserID: user.ID}
+	b := &models.Todo{Title: "B", UserID: user.ID}
+	c := &models.Todo{Title: "C", UserID: user.ID}
+	d := &models.Todo{Title: "D", UserID: user.ID}
+	create(t, db, a, b, c, d)
+
+	// A is blocked by B, which is blocked by C.
+	create(t, db,
+		&models.Dependency{TodoID: a.ID, BlockerID: b.ID},
+		&models.Dependency{TodoID: b.ID, BlockerID: c.ID},
+	)
+
+	tests := []struct {
+		name            string
+		todoID, blocker uint
+		want            bool
+	}{
+		{"self", a.ID, a.ID, true},
+		{"direct", b.ID, a.ID, true},
+		{"transitive", c.ID, a.ID, true},
+		{"same direction", a.ID, c.ID, false},
+		{"unrelated", d.ID, a.ID, false},
+		{"unrelated blocker", c.ID, d.ID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := createsCycle(db, tt.todoID, tt.blocker)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("createsCycle(%d, %d) = %v, want %v", tt.todoID, tt.blocker, got, tt.want)
+			}
+		})
+	}
+
+	// With B in the trash, C no longer reaches A.
+	if err := db.Delete(b).Error; err != nil {
+		t.Fatal(err)
+	}
+	got, err := createsCycle(db, c.ID, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got {
+		t.Error("createsCycle walked through a deleted todo")
+	}
+}
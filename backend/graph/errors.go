@@ -0,0 +1,37 @@
+package graph
+
+import "errors"
+
+// ErrInvalidInput is wrapped by resolver errors caused by the request rather
+// than by missing records or the database, so that REST handlers can answer
+// them with 400 Bad Request.
+var ErrInvalidInput = errors.New("invalid input")
+
+var (
+	// ErrUndoExpired is returned for undo tokens that were already used or
+	// whose window has passed.
+	ErrUndoExpired = errors.New("undo token expired")
+	// ErrUndoConflict is returned when something the operation touched has
+	// changed since, so that undoing it would discard that change.
+	ErrUndoConflict = errors.New("changed since the operation")
+)
+
+var (
+	// ErrAttachmentTooLarge is returned for uploads above the size limit.
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+	// ErrQuotaExceeded is returned when an upload would take the user over
+	// their storage quota.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+)
+
+var (
+	// ErrDependencyCycle is returned when a new dependency would make a todo
+	// transitively block itself.
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrBlocked is returned when completing a todo whose blockers are still
+	// open while completion of blocked todos is refused.
+	ErrBlocked = errors.New("todo is blocked by open todos")
+)
+
+// ErrForbidden is returned when the user may see a record but not change it.
+var ErrForbidden = errors.New("forbidden")
@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// invitationLifetime is how long an invitation can be accepted.
+const invitationLifetime = 14 * 24 * time.Hour
+
+var roleRank = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleOwner:  3,
+}
+
+// BackfillGroupOwners adds the owner of every group without members as its
+// owning member. Groups created before sharing existed have none, and their
+// todos would otherwise be invisible.
+func BackfillGroupOwners(db *gorm.DB) error {
+	err := db.Exec(`INSERT INTO group_members (group_id, user_id, role, created_at, updated_at)
+		SELECT groups.id, groups.user_id, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM groups
+		WHERE NOT EXISTS (SELECT 1 FROM group_members WHERE group_members.group_id = groups.id)`, models.RoleOwner).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill group owners: %w", err)
+	}
+	return nil
+}
+
+// groupRole returns userID's role in groupID, or "" if they are not a member.
+func groupRole(tx *gorm.DB, groupID, userID uint) (string, error) {
+	var members []models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Limit(1).Find(&members).Error; err != nil {
+		return "", fmt.Errorf("failed to check membership: %w", err)
+	}
+	if len(members) == 0 {
+		return "", nil
+	}
+	return members[0].Role, nil
+}
+
+// checkRole fails unless role permits what minRole does. Non-members are told
+// the record does not exist, members with too low a role that they may not
+// change it.
+func checkRole(role, minRole, entity string) error {
+	if role == "" {
+		return fmt.Errorf("%s not found: %w", entity, gorm.ErrRecordNotFound)
+	}
+	if roleRank[role] < roleRank[minRole] {
+		return fmt.Errorf("%w: requires %s access", ErrForbidden, minRole)
+	}
+	return nil
+}
+
+// groupAccess loads groupID through db for userID, who must hold at least
+// minRole in it. db may be Unscoped or otherwise restricted.
+func groupAccess(db *gorm.DB, groupID, userID uint, minRole string) (*models.Group, error) {
+	var group models.Group
+	if err := db.First(&group, groupID).Error; err != nil {
+		return nil, fmt.Errorf("group not found: %w", err)
+	}
+	role, err := groupRole(db.Session(&gorm.Session{NewDB: true}), group.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRole(role, minRole, "group"); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// todoRole returns userID's role on todo: that in its group, or owner of
+// their own ungrouped todos.
+func todoRole(tx *gorm.DB, todo *models.Todo, userID uint) (string, error) {
+	if todo.GroupID == nil {
+		if todo.UserID == userID {
+			return models.RoleOwner, nil
+		}
+		return "", nil
+	}
+	return groupRole(tx, *todo.GroupID, userID)
+}
+
+// todoAccess loads todoID through db for userID, who must hold at least
+// minRole on it. db may be Unscoped or otherwise restricted.
+func todoAccess(db *gorm.DB, todoID, userID uint, minRole string) (*models.Todo, error) {
+	var todo models.Todo
+	if err := db.First(&todo, todoID).Error; err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+	role, err := todoRole(db.Session(&gorm.Session{NewDB: true}), &todo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRole(role, minRole, "todo"); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// visibleTodos restricts query to the todos userID can see.
+func visibleTodos(query *gorm.DB, userID uint) *gorm.DB {
+	return query.Where(models.TodoAccess(userID, models.RoleViewer))
+}
+
+// visibleGroups restricts query to the groups userID is a member of.
+func visibleGroups(query *gorm.DB, userID uint) *gorm.DB {
+	return query.Where(models.GroupAccess(userID, models.RoleViewer))
+}
+
+func groupRoleFromModel(role model.GroupRole) string {
+	return strings.ToLower(string(role))
+}
+
+func groupRoleToModel(role string) model.GroupRole {
+	return model.GroupRole(strings.ToUpper(role))
+}
+
+// addGroupMember makes userID a member of groupID with role, or changes the
+// role of an existing member.
+func addGroupMember(tx *gorm.DB, groupID, userID uint, role string) (*models.GroupMember, error) {
+	var member models.GroupMember
+	err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	member.GroupID = groupID
+	member.UserID = userID
+	member.Role = role
+	if err := tx.Save(&member).Error; err != nil {
+		return nil, fmt.Errorf("failed to add group member: %w", err)
+	}
+	return &member, nil
+}
+
+// pendingInvitation loads invitation id addressed to userID's email address
+// that can still be answered.
+func pendingInvitation(tx *gorm.DB, id, userID uint) (*models.GroupInvitation, error) {
+	var user models.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	var invitation models.GroupInvitation
+	if err := tx.Where("id = ? AND email = ?", id, normalizeEmail(user.Email)).First(&invitation).Error; err != nil {
+		return nil, fmt.Errorf("invitation not found: %w", err)
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, fmt.Errorf("%w: invitation was already %s", ErrInvalidInput, invitation.Status)
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, fmt.Errorf("%w: invitation has expired", ErrInvalidInput)
+	}
+	return &invitation, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// createGroup validates group and adds it with its creator as the owning
+// member, recording its creation.
+func createGroup(tx *gorm.DB, group *models.Group) error {
+	if err := validateGroup(tx, group); err != nil {
+		return err
+	}
+	if err := groupWriteError(tx.Create(group).Error, "create group"); err != nil {
+		return err
+	}
+	if _, err := addGroupMember(tx, group.ID, group.UserID, models.RoleOwner); err != nil {
+		return err
+	}
+	return recordRevision(tx, entityGroup, group.ID, group.UserID, models.RevisionCreate, creationChanges(groupSnapshot(group)))
+}
@@ -2,16 +2,65 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"todo-app/models"
+)
+
 type CreateGroupInput struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
 	Color       *string `json:"color,omitempty"`
+	ParentID    *string `json:"parentId,omitempty"`
+}
+
+type CreateReminderInput struct {
+	TodoID        string     `json:"todoId"`
+	RemindAt      *time.Time `json:"remindAt,omitempty"`
+	OffsetMinutes *int       `json:"offsetMinutes,omitempty"`
+	Channel       *string    `json:"channel,omitempty"`
+}
+
+type CreateSmartListInput struct {
+	Name     string                `json:"name"`
+	Filter   *SmartListFilterInput `json:"filter"`
+	TimeZone *string               `json:"timeZone,omitempty"`
+}
+
+type CreateStatusInput struct {
+	Name     string  `json:"name"`
+	Color    *string `json:"color,omitempty"`
+	Position *int    `json:"position,omitempty"`
+	IsDone   *bool   `json:"isDone,omitempty"`
+	GroupID  *string `json:"groupId,omitempty"`
+}
+
+type CreateTemplateInput struct {
+	Name             string               `json:"name"`
+	Description      *string              `json:"description,omitempty"`
+	Shared           *bool                `json:"shared,omitempty"`
+	GroupName        *string              `json:"groupName,omitempty"`
+	GroupDescription *string              `json:"groupDescription,omitempty"`
+	GroupColor       *string              `json:"groupColor,omitempty"`
+	Todos            []*TemplateTodoInput `json:"todos"`
 }
 
 type CreateTodoInput struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	GroupID     *string `json:"groupId,omitempty"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	GroupID         *string    `json:"groupId,omitempty"`
+	StatusID        *string    `json:"statusId,omitempty"`
+	AssigneeID      *string    `json:"assigneeId,omitempty"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	EstimateMinutes *int       `json:"estimateMinutes,omitempty"`
+	Priority        *Priority  `json:"priority,omitempty"`
+	RecurrenceRule  *string    `json:"recurrenceRule,omitempty"`
+	QuickAdd        *bool      `json:"quickAdd,omitempty"`
+	TimeZone        *string    `json:"timeZone,omitempty"`
 }
 
 type CreateUserInput struct {
@@ -19,25 +68,822 @@ type CreateUserInput struct {
 	Password string `json:"password"`
 }
 
+type CreateWorkspaceInput struct {
+	Name                string   `json:"name"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains,omitempty"`
+}
+
+type DependencyGraph struct {
+	Nodes []*models.Todo       `json:"nodes"`
+	Edges []*models.Dependency `json:"edges"`
+}
+
+type GroupStats struct {
+	Total           int       `json:"total"`
+	Open            int       `json:"open"`
+	Completed       int       `json:"completed"`
+	PercentComplete float64   `json:"percentComplete"`
+	Overdue         int       `json:"overdue"`
+	LastActivityAt  time.Time `json:"lastActivityAt"`
+}
+
+type GroupTime struct {
+	GroupID         string      `json:"groupId"`
+	TrackedSeconds  int         `json:"trackedSeconds"`
+	EstimateMinutes int         `json:"estimateMinutes"`
+	Todos           []*TodoTime `json:"todos"`
+}
+
+type InstantiateTemplateInput struct {
+	Variables []*TemplateVariable `json:"variables,omitempty"`
+	StartDate *time.Time          `json:"startDate,omitempty"`
+}
+
 type Mutation struct {
 }
 
+type Pins struct {
+	Groups []*models.Group `json:"groups"`
+	Todos  []*models.Todo  `json:"todos"`
+}
+
 type Query struct {
 }
 
+type QuickAddParse struct {
+	Title          string        `json:"title"`
+	DueDate        *time.Time    `json:"dueDate,omitempty"`
+	DueDateText    *string       `json:"dueDateText,omitempty"`
+	Group          *models.Group `json:"group,omitempty"`
+	GroupText      *string       `json:"groupText,omitempty"`
+	Priority       Priority      `json:"priority"`
+	PriorityText   *string       `json:"priorityText,omitempty"`
+	RecurrenceRule *string       `json:"recurrenceRule,omitempty"`
+	RecurrenceText *string       `json:"recurrenceText,omitempty"`
+	TimeZone       string        `json:"timeZone"`
+}
+
+type RevisionChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+type SearchResult struct {
+	Todo    *models.Todo `json:"todo"`
+	Rank    float64      `json:"rank"`
+	Title   string       `json:"title"`
+	Snippet string       `json:"snippet"`
+}
+
+type SmartListFilterInput struct {
+	Completed        *bool         `json:"completed,omitempty"`
+	AssignedToMe     *bool         `json:"assignedToMe,omitempty"`
+	MinPriority      *Priority     `json:"minPriority,omitempty"`
+	Due              *SmartListDue `json:"due,omitempty"`
+	GroupIds         []string      `json:"groupIds,omitempty"`
+	IncludeSubgroups *bool         `json:"includeSubgroups,omitempty"`
+}
+
+type TemplateInstance struct {
+	Group *models.Group  `json:"group,omitempty"`
+	Todos []*models.Todo `json:"todos"`
+}
+
+type TemplateTodoInput struct {
+	Title          string   `json:"title"`
+	Description    *string  `json:"description,omitempty"`
+	DueOffsetDays  *int     `json:"dueOffsetDays,omitempty"`
+	RecurrenceRule *string  `json:"recurrenceRule,omitempty"`
+	Checklist      []string `json:"checklist,omitempty"`
+}
+
+type TemplateVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TimeEntryInput struct {
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	Note      *string   `json:"note,omitempty"`
+}
+
+type TodoFilter struct {
+	View             *TodoView  `json:"view,omitempty"`
+	Completed        *bool      `json:"completed,omitempty"`
+	CompletedAfter   *time.Time `json:"completedAfter,omitempty"`
+	CompletedBefore  *time.Time `json:"completedBefore,omitempty"`
+	AssignedToMe     *bool      `json:"assignedToMe,omitempty"`
+	GroupID          *string    `json:"groupId,omitempty"`
+	IncludeSubgroups *bool      `json:"includeSubgroups,omitempty"`
+}
+
+type TodoSort struct {
+	Field     TodoSortField  `json:"field"`
+	Direction *SortDirection `json:"direction,omitempty"`
+}
+
+type TodoTime struct {
+	Todo            *models.Todo `json:"todo"`
+	TrackedSeconds  int          `json:"trackedSeconds"`
+	EstimateMinutes *int         `json:"estimateMinutes,omitempty"`
+}
+
+type Trash struct {
+	Todos  []*models.Todo  `json:"todos"`
+	Groups []*models.Group `json:"groups"`
+}
+
 type UpdateGroupInput struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
 	Color       *string `json:"color,omitempty"`
 }
 
+type UpdateSmartListInput struct {
+	Name     *string               `json:"name,omitempty"`
+	Filter   *SmartListFilterInput `json:"filter,omitempty"`
+	TimeZone *string               `json:"timeZone,omitempty"`
+}
+
+type UpdateStatusInput struct {
+	Name     *string `json:"name,omitempty"`
+	Color    *string `json:"color,omitempty"`
+	Position *int    `json:"position,omitempty"`
+	IsDone   *bool   `json:"isDone,omitempty"`
+}
+
+type UpdateTemplateInput struct {
+	Name             *string              `json:"name,omitempty"`
+	Description      *string              `json:"description,omitempty"`
+	Shared           *bool                `json:"shared,omitempty"`
+	GroupName        *string              `json:"groupName,omitempty"`
+	GroupDescription *string              `json:"groupDescription,omitempty"`
+	GroupColor       *string              `json:"groupColor,omitempty"`
+	Todos            []*TemplateTodoInput `json:"todos,omitempty"`
+}
+
+type UpdateTimeEntryInput struct {
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+}
+
 type UpdateTodoInput struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Completed   *bool   `json:"completed,omitempty"`
-	GroupID     *string `json:"groupId,omitempty"`
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Completed       *bool      `json:"completed,omitempty"`
+	StatusID        *string    `json:"statusId,omitempty"`
+	GroupID         *string    `json:"groupId,omitempty"`
+	AssigneeID      *string    `json:"assigneeId,omitempty"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	EstimateMinutes *int       `json:"estimateMinutes,omitempty"`
+	Priority        *Priority  `json:"priority,omitempty"`
+	RecurrenceRule  *string    `json:"recurrenceRule,omitempty"`
+	Scope           *EditScope `json:"scope,omitempty"`
 }
 
 type UpdateUserAdminInput struct {
 	IsAdmin bool `json:"isAdmin"`
 }
+
+type UpdateUserSettingsInput struct {
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+type UpdateWorkspaceInput struct {
+	Name                *string  `json:"name,omitempty"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains,omitempty"`
+}
+
+type EditScope string
+
+const (
+	EditScopeThis   EditScope = "THIS"
+	EditScopeSeries EditScope = "SERIES"
+)
+
+var AllEditScope = []EditScope{
+	EditScopeThis,
+	EditScopeSeries,
+}
+
+func (e EditScope) IsValid() bool {
+	switch e {
+	case EditScopeThis, EditScopeSeries:
+		return true
+	}
+	return false
+}
+
+func (e EditScope) String() string {
+	return string(e)
+}
+
+func (e *EditScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EditScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EditScope", str)
+	}
+	return nil
+}
+
+func (e EditScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EditScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EditScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupDeleteMode string
+
+const (
+	GroupDeleteModeReparent GroupDeleteMode = "REPARENT"
+	GroupDeleteModeCascade  GroupDeleteMode = "CASCADE"
+)
+
+var AllGroupDeleteMode = []GroupDeleteMode{
+	GroupDeleteModeReparent,
+	GroupDeleteModeCascade,
+}
+
+func (e GroupDeleteMode) IsValid() bool {
+	switch e {
+	case GroupDeleteModeReparent, GroupDeleteModeCascade:
+		return true
+	}
+	return false
+}
+
+func (e GroupDeleteMode) String() string {
+	return string(e)
+}
+
+func (e *GroupDeleteMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupDeleteMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupDeleteMode", str)
+	}
+	return nil
+}
+
+func (e GroupDeleteMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupDeleteMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupDeleteMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupRole string
+
+const (
+	GroupRoleViewer GroupRole = "VIEWER"
+	GroupRoleEditor GroupRole = "EDITOR"
+	GroupRoleOwner  GroupRole = "OWNER"
+)
+
+var AllGroupRole = []GroupRole{
+	GroupRoleViewer,
+	GroupRoleEditor,
+	GroupRoleOwner,
+}
+
+func (e GroupRole) IsValid() bool {
+	switch e {
+	case GroupRoleViewer, GroupRoleEditor, GroupRoleOwner:
+		return true
+	}
+	return false
+}
+
+func (e GroupRole) String() string {
+	return string(e)
+}
+
+func (e *GroupRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupRole", str)
+	}
+	return nil
+}
+
+func (e GroupRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GroupView string
+
+const (
+	GroupViewActive   GroupView = "ACTIVE"
+	GroupViewArchived GroupView = "ARCHIVED"
+	GroupViewAll      GroupView = "ALL"
+)
+
+var AllGroupView = []GroupView{
+	GroupViewActive,
+	GroupViewArchived,
+	GroupViewAll,
+}
+
+func (e GroupView) IsValid() bool {
+	switch e {
+	case GroupViewActive, GroupViewArchived, GroupViewAll:
+		return true
+	}
+	return false
+}
+
+func (e GroupView) String() string {
+	return string(e)
+}
+
+func (e *GroupView) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GroupView(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GroupView", str)
+	}
+	return nil
+}
+
+func (e GroupView) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GroupView) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GroupView) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Priority string
+
+const (
+	PriorityNone   Priority = "NONE"
+	PriorityLow    Priority = "LOW"
+	PriorityMedium Priority = "MEDIUM"
+	PriorityHigh   Priority = "HIGH"
+)
+
+var AllPriority = []Priority{
+	PriorityNone,
+	PriorityLow,
+	PriorityMedium,
+	PriorityHigh,
+}
+
+func (e Priority) IsValid() bool {
+	switch e {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+func (e Priority) String() string {
+	return string(e)
+}
+
+func (e *Priority) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Priority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Priority", str)
+	}
+	return nil
+}
+
+func (e Priority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Priority) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Priority) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SmartListDue string
+
+const (
+	SmartListDueOverdue SmartListDue = "OVERDUE"
+	SmartListDueToday   SmartListDue = "TODAY"
+	SmartListDueWeek    SmartListDue = "WEEK"
+	SmartListDueMonth   SmartListDue = "MONTH"
+	SmartListDueNone    SmartListDue = "NONE"
+)
+
+var AllSmartListDue = []SmartListDue{
+	SmartListDueOverdue,
+	SmartListDueToday,
+	SmartListDueWeek,
+	SmartListDueMonth,
+	SmartListDueNone,
+}
+
+func (e SmartListDue) IsValid() bool {
+	switch e {
+	case SmartListDueOverdue, SmartListDueToday, SmartListDueWeek, SmartListDueMonth, SmartListDueNone:
+		return true
+	}
+	return false
+}
+
+func (e SmartListDue) String() string {
+	return string(e)
+}
+
+func (e *SmartListDue) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SmartListDue(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SmartListDue", str)
+	}
+	return nil
+}
+
+func (e SmartListDue) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SmartListDue) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SmartListDue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SnoozePreset string
+
+const (
+	SnoozePresetLaterToday SnoozePreset = "LATER_TODAY"
+	SnoozePresetTomorrow   SnoozePreset = "TOMORROW"
+	SnoozePresetNextWeek   SnoozePreset = "NEXT_WEEK"
+)
+
+var AllSnoozePreset = []SnoozePreset{
+	SnoozePresetLaterToday,
+	SnoozePresetTomorrow,
+	SnoozePresetNextWeek,
+}
+
+func (e SnoozePreset) IsValid() bool {
+	switch e {
+	case SnoozePresetLaterToday, SnoozePresetTomorrow, SnoozePresetNextWeek:
+		return true
+	}
+	return false
+}
+
+func (e SnoozePreset) String() string {
+	return string(e)
+}
+
+func (e *SnoozePreset) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SnoozePreset(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SnoozePreset", str)
+	}
+	return nil
+}
+
+func (e SnoozePreset) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SnoozePreset) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SnoozePreset) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SortDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SortDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TodoSortField string
+
+const (
+	TodoSortFieldCreatedAt   TodoSortField = "CREATED_AT"
+	TodoSortFieldUpdatedAt   TodoSortField = "UPDATED_AT"
+	TodoSortFieldDueDate     TodoSortField = "DUE_DATE"
+	TodoSortFieldCompletedAt TodoSortField = "COMPLETED_AT"
+	TodoSortFieldPriority    TodoSortField = "PRIORITY"
+)
+
+var AllTodoSortField = []TodoSortField{
+	TodoSortFieldCreatedAt,
+	TodoSortFieldUpdatedAt,
+	TodoSortFieldDueDate,
+	TodoSortFieldCompletedAt,
+	TodoSortFieldPriority,
+}
+
+func (e TodoSortField) IsValid() bool {
+	switch e {
+	case TodoSortFieldCreatedAt, TodoSortFieldUpdatedAt, TodoSortFieldDueDate, TodoSortFieldCompletedAt, TodoSortFieldPriority:
+		return true
+	}
+	return false
+}
+
+func (e TodoSortField) String() string {
+	return string(e)
+}
+
+func (e *TodoSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TodoSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TodoSortField", str)
+	}
+	return nil
+}
+
+func (e TodoSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TodoSortField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TodoSortField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TodoView string
+
+const (
+	TodoViewActive  TodoView = "ACTIVE"
+	TodoViewSnoozed TodoView = "SNOOZED"
+	TodoViewSomeday TodoView = "SOMEDAY"
+	TodoViewAll     TodoView = "ALL"
+)
+
+var AllTodoView = []TodoView{
+	TodoViewActive,
+	TodoViewSnoozed,
+	TodoViewSomeday,
+	TodoViewAll,
+}
+
+func (e TodoView) IsValid() bool {
+	switch e {
+	case TodoViewActive, TodoViewSnoozed, TodoViewSomeday, TodoViewAll:
+		return true
+	}
+	return false
+}
+
+func (e TodoView) String() string {
+	return string(e)
+}
+
+func (e *TodoView) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TodoView(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TodoView", str)
+	}
+	return nil
+}
+
+func (e TodoView) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TodoView) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TodoView) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleMember WorkspaceRole = "MEMBER"
+	WorkspaceRoleAdmin  WorkspaceRole = "ADMIN"
+)
+
+var AllWorkspaceRole = []WorkspaceRole{
+	WorkspaceRoleMember,
+	WorkspaceRoleAdmin,
+}
+
+func (e WorkspaceRole) IsValid() bool {
+	switch e {
+	case WorkspaceRoleMember, WorkspaceRoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e WorkspaceRole) String() string {
+	return string(e)
+}
+
+func (e *WorkspaceRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WorkspaceRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WorkspaceRole", str)
+	}
+	return nil
+}
+
+func (e WorkspaceRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WorkspaceRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WorkspaceRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// groupSubtreeCTE defines subtree as the groups with the IDs given, live or
+// not, and all live groups below them, with their depth below their root.
+// The walk ends after a thousand levels so that a cycle, which setGroupParent
+// keeps from forming, could not make it run forever.
+const groupSubtreeCTE = `WITH RECURSIVE subtree(id, depth) AS (
+	SELECT id, 0 FROM groups WHERE id IN ?
+	UNION
+	SELECT groups.id, subtree.depth + 1 FROM groups JOIN subtree ON groups.parent_id = subtree.id
+	WHERE groups.deleted_at IS NULL AND subtree.depth < 1000
+)`
+
+// groupSubtreeSQL selects the IDs of the subtrees of a list of groups.
+const groupSubtreeSQL = groupSubtreeCTE + ` SELECT id FROM subtree`
+
+// groupSubtree returns rootID and the IDs of all live groups below it, level
+// by level, so that every group comes after its parent.
+func groupSubtree(tx *gorm.DB, rootID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Raw(groupSubtreeCTE+` SELECT id FROM subtree GROUP BY id ORDER BY MIN(depth), id`, []uint{rootID}).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subgroups: %w", err)
+	}
+	return ids, nil
+}
+
+// setGroupParent moves group under the group with ID parentID, or to the top
+// level when parentID is empty. userID must be able to edit the new parent,
+// and a group cannot move below itself.
+func setGroupParent(tx *gorm.DB, group *models.Group, parentID string, userID uint) error {
+	if parentID == "" {
+		group.ParentID = nil
+		return nil
+	}
+	pid, err := strconv.ParseUint(parentID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid parent group ID: %w", err)
+	}
+	parent, err := groupAccess(tx, uint(pid), userID, models.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if parent.ArchivedAt != nil && group.ArchivedAt == nil {
+		return fmt.Errorf("%w: cannot move a group into an archived group", ErrInvalidInput)
+	}
+	if group.ID != 0 {
+		subtree, err := groupSubtree(tx, group.ID)
+		if err != nil {
+			return err
+		}
+		for _, id := range subtree {
+			if id == parent.ID {
+				return fmt.Errorf("%w: a group cannot be moved into itself or one of its subgroups", ErrInvalidInput)
+			}
+		}
+	}
+	group.ParentID = &parent.ID
+	return nil
+}
+
+// detachInvalidParent moves group to the top level when a restored parent ID
+// no longer refers to a live group or would put the group below itself.
+func detachInvalidParent(tx *gorm.DB, group *models.Group) error {
+	if group.ParentID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.Group{}).Where("id = ?", *group.ParentID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check parent group: %w", err)
+	}
+	if count == 0 {
+		group.ParentID = nil
+		return nil
+	}
+	subtree, err := groupSubtree(tx, group.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range subtree {
+		if id == *group.ParentID {
+			group.ParentID = nil
+			break
+		}
+	}
+	return nil
+}
+
+// reparentChildren moves the direct subgroups of group up to its parent,
+// recording the move on each. userID must be able to edit every one.
+func reparentChildren(tx *gorm.DB, group *models.Group, userID uint) error {
+	var children []models.Group
+	if err := tx.Where("parent_id = ?", group.ID).Find(&children).Error; err != nil {
+		return fmt.Errorf("failed to fetch subgroups: %w", err)
+	}
+	for i := range children {
+		child := &children[i]
+		if _, err := groupAccess(tx, child.ID, userID, models.RoleEditor); err != nil {
+			return err
+		}
+		before := groupSnapshot(child)
+		child.ParentID = group.ParentID
+		if err := tx.Model(child).Update("parent_id", child.ParentID).Error; err != nil {
+			return fmt.Errorf("failed to move subgroup: %w", err)
+		}
+		if err := recordRevision(tx, entityGroup, child.ID, userID, models.RevisionUpdate, diffSnapshots(before, groupSnapshot(child))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteGroup moves group to the trash. Its todos stay with their creators,
+// ungrouped, and its status columns go with it.
+func deleteGroup(tx *gorm.DB, group *models.Group, userID uint) (bool, error) {
+	var ungrouped []models.Todo
+	if err := tx.Where("group_id = ?", group.ID).Find(&ungrouped).Error; err != nil {
+		return false, fmt.Errorf("failed to fetch group todos: %w", err)
+	}
+
+	// Ungrouped todos can only be assigned to their creator.
+	if err := tx.Model(&models.Todo{}).Where("group_id = ? AND assignee_id <> user_id", group.ID).Update("assignee_id", nil).Error; err != nil {
+		return false, fmt.Errorf("failed to unassign todos: %w", err)
+	}
+	if err := tx.Model(&models.Todo{}).Where("group_id = ?", group.ID).Update("group_id", nil).Error; err != nil {
+		return false, fmt.Errorf("failed to unlink todos from group: %w", err)
+	}
+	for _, todo := range ungrouped {
+		changes := models.FieldChanges{"group_id": {Before: group.ID, After: nil}}
+		if todo.AssigneeID != nil && *todo.AssigneeID != todo.UserID {
+			changes["assignee_id"] = models.FieldChange{Before: *todo.AssigneeID, After: nil}
+		}
+		if err := recordRevision(tx, entityTodo, todo.ID, userID, models.RevisionUpdate, changes); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Where("group_id = ?", group.ID).Delete(&models.Status{}).Error; err != nil {
+		return false, fmt.Errorf("failed to delete group statuses: %w", err)
+	}
+
+	result := tx.Delete(group)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete group: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, recordRevision(tx, entityGroup, group.ID, userID, models.RevisionDelete, nil)
+}
+
+// restoreGroupParent moves a group just restored from the trash to the top
+// level when its parent is still gone, and returns that change, if any.
+func restoreGroupParent(tx *gorm.DB, group *models.Group) (models.FieldChanges, error) {
+	before := groupSnapshot(group)
+	if err := detachInvalidParent(tx, group); err != nil {
+		return nil, err
+	}
+	changes := diffSnapshots(before, groupSnapshot(group))
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if err := tx.Model(group).Update("parent_id", group.ParentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to move group: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+	"todo-app/models"
+)
+
+func TestGroupSubtree(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+
+	// The grandchild is older than the groups above it, so IDs alone would
+	// put it first.
+	grandchild := &models.Group{Name: "Grandchild", UserID: user.ID}
+	root := &models.Group{Name: "Root", UserID: user.ID}
+	create(t, db, grandchild, root)
+	child := &models.Group{Name: "Child", UserID: user.ID, ParentID: &root.ID}
+	deleted := &models.Group{Name: "Deleted", UserID: user.ID, ParentID: &root.ID}
+	other := &models.Group{Name: "Other", UserID: user.ID}
+	create(t, db, child, deleted, other)
+	if err := db.Model(grandchild).Update("parent_id", child.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	belowDeleted := &models.Group{Name: "Below deleted", UserID: user.ID, ParentID: &deleted.ID}
+	create(t, db, belowDeleted)
+	if err := db.Delete(deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		rootID uint
+		want   []uint
+	}{
+		{"parents first", root.ID, []uint{root.ID, child.ID, grandchild.ID}},
+		{"leaf", grandchild.ID, []uint{grandchild.ID}},
+		{"deleted root", deleted.ID, []uint{deleted.ID, belowDeleted.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := groupSubtree(db, tt.rootID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupSubtree(%d) = %v, want %v", tt.rootID, got, tt.want)
+			}
+		})
+	}
+
+	// Every caller shares the CTE, and a cycle must not hang it.
+	if err := db.Model(root).Update("parent_id", grandchild.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint
+	if err := db.Raw(groupSubtreeSQL, []uint{root.ID, other.ID}).Scan(&ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) == 0 {
+		t.Error("groupSubtreeSQL found nothing")
+	}
+}
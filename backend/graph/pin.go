@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// userPins returns userID's pins of entityType in order.
+func userPins(tx *gorm.DB, userID uint, entityType string) ([]models.Pin, error) {
+	var pins []models.Pin
+	if err := tx.Where("user_id = ? AND entity_type = ?", userID, entityType).Order("position").Order("id").Find(&pins).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pins: %w", err)
+	}
+	return pins, nil
+}
+
+// renumberPins stores each pin's index in pins as its position.
+func renumberPins(tx *gorm.DB, pins []models.Pin) error {
+	for i := range pins {
+		if pins[i].Position == i {
+			continue
+		}
+		if err := tx.Model(&models.Pin{}).Where("id = ?", pins[i].ID).Update("position", i).Error; err != nil {
+			return fmt.Errorf("failed to reorder pins: %w", err)
+		}
+		pins[i].Position = i
+	}
+	return nil
+}
+
+// pinEntity pins entityID for userID at position among their pins of
+// entityType, or after them when position is nil. Pinning something already
+// pinned moves it.
+func pinEntity(tx *gorm.DB, userID uint, entityType string, entityID uint, position *int) error {
+	if position != nil && *position < 0 {
+		return fmt.Errorf("%w: position must not be negative", ErrInvalidInput)
+	}
+	pins, err := userPins(tx, userID, entityType)
+	if err != nil {
+		return err
+	}
+
+	pin := models.Pin{UserID: userID, EntityType: entityType, EntityID: entityID}
+	for i := range pins {
+		if pins[i].EntityID == entityID {
+			pin = pins[i]
+			pins = append(pins[:i], pins[i+1:]...)
+			break
+		}
+	}
+	at := len(pins)
+	if position != nil && *position < at {
+		at = *position
+	}
+	if pin.ID == 0 {
+		pin.Position = at
+		if err := tx.Create(&pin).Error; err != nil {
+			return fmt.Errorf("failed to pin: %w", err)
+		}
+	}
+
+	pins = append(pins[:at], append([]models.Pin{pin}, pins[at:]...)...)
+	return renumberPins(tx, pins)
+}
+
+// unpinEntity removes userID's pin of entityID, if any, closing the gap it
+// leaves. Undo puts the pin back where it was.
+func unpinEntity(tx *gorm.DB, userID uint, entityType string, entityID uint) error {
+	var pin []models.Pin
+	if err := tx.Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).Limit(1).Find(&pin).Error; err != nil {
+		return fmt.Errorf("failed to fetch pin: %w", err)
+	}
+	if len(pin) == 0 {
+		return nil
+	}
+	if err := deleteRecord(tx, entityPin, pin[0].ID, userID); err != nil {
+		return err
+	}
+	pins, err := userPins(tx, userID, entityType)
+	if err != nil {
+		return err
+	}
+	return renumberPins(tx, pins)
+}
+
+// isPinned reports whether userID pinned entityID.
+func isPinned(tx *gorm.DB, userID uint, entityType string, entityID uint) (bool, error) {
+	var count int64
+	err := tx.Model(&models.Pin{}).
+		Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check pin: %w", err)
+	}
+	return count > 0, nil
+}
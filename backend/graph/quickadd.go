@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+	"todo-app/quickadd"
+
+	"gorm.io/gorm"
+)
+
+// Priorities are stored as integers so that todos sort by them.
+var priorityLevels = map[model.Priority]int{
+	model.PriorityNone:   0,
+	model.PriorityLow:    1,
+	model.PriorityMedium: 2,
+	model.PriorityHigh:   3,
+}
+
+var quickAddPriorities = map[string]model.Priority{
+	quickadd.PriorityLow:    model.PriorityLow,
+	quickadd.PriorityMedium: model.PriorityMedium,
+	quickadd.PriorityHigh:   model.PriorityHigh,
+}
+
+func priorityLevel(priority model.Priority) int {
+	return priorityLevels[priority]
+}
+
+func priorityFromLevel(level int) model.Priority {
+	for priority, l := range priorityLevels {
+		if l == level {
+			return priority
+		}
+	}
+	return model.PriorityNone
+}
+
+// userLocation returns the time zone quick-add phrases are read in: timeZone
+// when given, otherwise the user's own, otherwise UTC.
+func userLocation(tx *gorm.DB, userID uint, timeZone *string) (*time.Location, error) {
+	name := ""
+	if timeZone != nil {
+		name = *timeZone
+	} else {
+		var user models.User
+		if err := tx.Select("id", "time_zone").First(&user, userID).Error; err != nil {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		name = user.TimeZone
+	}
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidInput, name)
+	}
+	return loc, nil
+}
+
+// groupRefKey reduces a group name to the form it can be referenced by in
+// quick-add text, so that "#side-project" finds "Side Project".
+func groupRefKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// parseQuickAdd reads the structured fields of a todo out of text. Only #name
+// tokens naming a group the user can add todos to are taken as references;
+// the first becomes the todo's group.
+func parseQuickAdd(tx *gorm.DB, userID uint, text string, timeZone *string) (*model.QuickAddParse, error) {
+	loc, err := userLocation(tx, userID, timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*models.Group
+	if err := tx.Where(models.GroupAccess(userID, models.RoleEditor)).Where("archived_at IS NULL").Order("id").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+	byKey := make(map[string]*models.Group, len(groups))
+	for _, group := range groups {
+		if key := groupRefKey(group.Name); byKey[key] == nil {
+			byKey[key] = group
+		}
+	}
+
+	result := quickadd.Parse(text, time.Now().In(loc), func(name string) bool {
+		return byKey[groupRefKey(name)] != nil
+	})
+
+	parsed := &model.QuickAddParse{
+		Title:    result.Title,
+		DueDate:  result.Due,
+		Priority: model.PriorityNone,
+		TimeZone: loc.String(),
+	}
+	if result.DueText != "" {
+		parsed.DueDateText = &result.DueText
+	}
+	if len(result.Refs) > 0 {
+		parsed.Group = byKey[groupRefKey(result.Refs[0])]
+		groupText := "#" + result.Refs[0]
+		parsed.GroupText = &groupText
+	}
+	if result.Priority != "" {
+		parsed.Priority = quickAddPriorities[result.Priority]
+		parsed.PriorityText = &result.PriorityText
+	}
+	if result.Recurrence != "" {
+		parsed.RecurrenceRule = &result.Recurrence
+		parsed.RecurrenceText = &result.RecurrenceText
+	}
+	return parsed, nil
+}
+
+// applyQuickAdd fills the fields of input that were not given explicitly
+// from parsed, and replaces the title with the parsed one.
+func applyQuickAdd(input *model.CreateTodoInput, parsed *model.QuickAddParse) error {
+	if parsed.Title == "" {
+		return fmt.Errorf("%w: title is empty once dates and markers are removed", ErrInvalidInput)
+	}
+	input.Title = parsed.Title
+	if input.DueDate == nil {
+		input.DueDate = parsed.DueDate
+	}
+	if input.GroupID == nil && parsed.Group != nil {
+		groupID := strconv.FormatUint(uint64(parsed.Group.ID), 10)
+		input.GroupID = &groupID
+	}
+	if input.Priority == nil && parsed.Priority != model.PriorityNone {
+		priority := parsed.Priority
+		input.Priority = &priority
+	}
+	if input.RecurrenceRule == nil {
+		input.RecurrenceRule = parsed.RecurrenceRule
+	}
+	return nil
+}
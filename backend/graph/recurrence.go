@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+	"todo-app/recurrence"
+	"todo-app/scheduler"
+
+	"gorm.io/gorm"
+)
+
+// startSeries turns todo into the first occurrence of a recurring series.
+// It must be called after the todo has been created so that its ID is known.
+func startSeries(todo *models.Todo) {
+	if todo.SeriesID == nil {
+		seriesID := todo.ID
+		todo.SeriesID = &seriesID
+		todo.Occurrence = 1
+	}
+}
+
+// firstOccurrence returns the first todo of the series todo belongs to. Its
+// due date, or creation time when it has none, is the series' DTSTART.
+func firstOccurrence(tx *gorm.DB, todo *models.Todo) (*models.Todo, error) {
+	var first models.Todo
+	if err := tx.Unscoped().Where("series_id = ? AND occurrence = 1", *todo.SeriesID).First(&first).Error; err != nil {
+		return nil, fmt.Errorf("failed to find first occurrence: %w", err)
+	}
+	return &first, nil
+}
+
+// createNextOccurrence creates the occurrence following todo in its series.
+// It returns nil without error when the series has ended or a later
+// occurrence already exists, including one in the trash, which restoring
+// would otherwise duplicate.
+func createNextOccurrence(tx *gorm.DB, todo *models.Todo) (*models.Todo, error) {
+	if todo.RecurrenceRule == "" || todo.SeriesID == nil {
+		return nil, nil
+	}
+
+	rule, err := recurrence.Parse(todo.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	var later int64
+	if err := tx.Unscoped().Model(&models.Todo{}).Where("series_id = ? AND occurrence > ?", *todo.SeriesID, todo.Occurrence).Count(&later).Error; err != nil {
+		return nil, fmt.Errorf("failed to check series: %w", err)
+	}
+	if later > 0 {
+		return nil, nil
+	}
+
+	first, err := firstOccurrence(tx, todo)
+	if err != nil {
+		return nil, err
+	}
+	dtstart := first.CreatedAt
+	if first.DueDate != nil {
+		dtstart = *first.DueDate
+	}
+
+	due, ok := rule.Nth(dtstart, todo.Occurrence+1)
+	if !ok {
+		return nil, nil
+	}
+
+	next := &models.Todo{
+		Title:           todo.Title,
+		Description:     todo.Description,
+		UserID:          todo.UserID,
+		WorkspaceID:     todo.WorkspaceID,
+		GroupID:         todo.GroupID,
+		AssigneeID:      todo.AssigneeID,
+		DueDate:         &due,
+		EstimateMinutes: todo.EstimateMinutes,
+		Priority:        todo.Priority,
+		RecurrenceRule:  todo.RecurrenceRule,
+		SeriesID:        todo.SeriesID,
+		Occurrence:      todo.Occurrence + 1,
+	}
+	if err := tx.Create(next).Error; err != nil {
+		return nil, fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+	actorID := todo.UserID
+	if todo.CompletedBy != nil {
+		actorID = *todo.CompletedBy
+	}
+	if err := recordRevision(tx, entityTodo, next.ID, actorID, models.RevisionCreate, creationChanges(todoSnapshot(next))); err != nil {
+		return nil, err
+	}
+
+	// Reminders carry over to the next occurrence: relative ones as they
+	// are, absolute ones moved along with the due date. Absolute reminders
+	// of a todo without a due date have nothing to move with.
+	var reminders []models.Reminder
+	if err := tx.Where("todo_id = ?", todo.ID).Find(&reminders).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch reminders: %w", err)
+	}
+	for _, reminder := range reminders {
+		copied := &models.Reminder{
+			TodoID:        next.ID,
+			UserID:        reminder.UserID,
+			WorkspaceID:   next.WorkspaceID,
+			OffsetMinutes: reminder.OffsetMinutes,
+			Channel:       reminder.Channel,
+		}
+		if reminder.OffsetMinutes == nil {
+			if reminder.RemindAt == nil || todo.DueDate == nil {
+				continue
+			}
+			remindAt := reminder.RemindAt.Add(due.Sub(*todo.DueDate))
+			copied.RemindAt = &remindAt
+		}
+		if err := tx.Create(copied).Error; err != nil {
+			return nil, fmt.Errorf("failed to copy reminder: %w", err)
+		}
+		if err := scheduler.ScheduleReminder(tx, copied, next); err != nil {
+			return nil, err
+		}
+	}
+
+	return next, nil
+}
+
+// updateSeries applies updates to the open occurrences of todo's series other
+// than todo itself, recording a revision for each occurrence it changes.
+func updateSeries(tx *gorm.DB, todo *models.Todo, actorID uint, updates map[string]interface{}) error {
+	siblings := tx.Model(&models.Todo{}).Where("series_id = ? AND user_id = ? AND completed = ? AND id <> ?", *todo.SeriesID, todo.UserID, false, todo.ID)
+
+	var before []models.Todo
+	if err := siblings.Session(&gorm.Session{}).Find(&before).Error; err != nil {
+		return fmt.Errorf("failed to fetch series: %w", err)
+	}
+	if len(before) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(before))
+	for i := range before {
+		ids[i] = before[i].ID
+	}
+	if err := tx.Model(&models.Todo{}).Where("id IN ?", ids).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+
+	var after []models.Todo
+	if err := tx.Where("id IN ?", ids).Find(&after).Error; err != nil {
+		return fmt.Errorf("failed to fetch series: %w", err)
+	}
+	updated := make(map[uint]*models.Todo, len(after))
+	for i := range after {
+		updated[after[i].ID] = &after[i]
+	}
+	for i := range before {
+		if sibling, ok := updated[before[i].ID]; ok {
+			changes := diffSnapshots(todoSnapshot(&before[i]), todoSnapshot(sibling))
+			if err := recordRevision(tx, entityTodo, sibling.ID, actorID, models.RevisionUpdate, changes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"testing"
+	"time"
+	"todo-app/models"
+)
+
+func TestNextOccurrenceCopiesTodo(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	owner := &models.User{Email: "owner@example.com", Password: "x"}
+	assignee := &models.User{Email: "assignee@example.com", Password: "x"}
+	create(t, db, owner, assignee)
+
+	due := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	estimate := 30
+	todo := &models.Todo{
+		Title:           "Water plants",
+		UserID:          owner.ID,
+		AssigneeID:      &assignee.ID,
+		DueDate:         &due,
+		EstimateMinutes: &estimate,
+		Priority:        3,
+		RecurrenceRule:  "FREQ=DAILY",
+	}
+	create(t, db, todo)
+	startSeries(todo)
+	if err := db.Save(todo).Error; err != nil {
+		t.Fatal(err)
+	}
+	offset := -60
+	remindAt := due.Add(-2 * time.Hour)
+	create(t, db,
+		&models.Reminder{TodoID: todo.ID, UserID: owner.ID, OffsetMinutes: &offset, Channel: "in_app"},
+		&models.Reminder{TodoID: todo.ID, UserID: owner.ID, RemindAt: &remindAt, Channel: "email"},
+	)
+
+	next, err := createNextOccurrence(db, todo)
+	if err != nil || next == nil {
+		t.Fatalf("createNextOccurrence = %v, %v", next, err)
+	}
+	if next.AssigneeID == nil || *next.AssigneeID != assignee.ID {
+		t.Errorf("assignee = %v, want %d", next.AssigneeID, assignee.ID)
+	}
+	if next.EstimateMinutes == nil || *next.EstimateMinutes != estimate {
+		t.Errorf("estimate = %v, want %d", next.EstimateMinutes, estimate)
+	}
+	if next.Priority != todo.Priority {
+		t.Errorf("priority = %d, want %d", next.Priority, todo.Priority)
+	}
+	if want := due.AddDate(0, 0, 1); next.DueDate == nil || !next.DueDate.Equal(want) {
+		t.Fatalf("due date = %v, want %v", next.DueDate, want)
+	}
+
+	var reminders []models.Reminder
+	if err := db.Where("todo_id = ?", next.ID).Order("id").Find(&reminders).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(reminders) != 2 {
+		t.Fatalf("copied %d reminders, want 2", len(reminders))
+	}
+	if r := reminders[0]; r.OffsetMinutes == nil || *r.OffsetMinutes != offset || r.RemindAt != nil {
+		t.Errorf("relative reminder = %+v, want offset %d", r, offset)
+	}
+	if r, want := reminders[1], next.DueDate.Add(-2*time.Hour); r.RemindAt == nil || !r.RemindAt.Equal(want) || r.Channel != "email" {
+		t.Errorf("absolute reminder = %+v, want it at %v by email", r, want)
+	}
+}
@@ -2,12 +2,42 @@ package graph
 
 //go:generate go run github.com/99designs/gqlgen generate
 
-import "gorm.io/gorm"
+import (
+        "context"
+        "time"
+        "todo-app/blobstore"
+
+        "gorm.io/gorm"
+)
 
 type Resolver struct {
         DB *gorm.DB
+        // UndoWindow is how long after an operation its undo token is valid.
+        UndoWindow time.Duration
+        // Blobs stores attachment contents; uploads fail while it is nil.
+        Blobs blobstore.BlobStore
+        // MaxAttachmentSize and AttachmentQuota limit single uploads and the
+        // total size of a user's attachments, in bytes.
+        MaxAttachmentSize int64
+        AttachmentQuota   int64
+        // RefuseBlockedCompletion makes completing a todo with open blockers
+        // fail with ErrBlocked instead of being allowed.
+        RefuseBlockedCompletion bool
 }
 
 func NewResolver(db *gorm.DB) *Resolver {
-        return &Resolver{DB: db}
+        return &Resolver{
+                DB:                db,
+                UndoWindow:        5 * time.Minute,
+                MaxAttachmentSize: 10 << 20,
+                AttachmentQuota:   100 << 20,
+        }
+}
+
+// within returns a copy of r whose database calls carry ctx, and with it the
+// workspace they are scoped to.
+func (r *Resolver) within(ctx context.Context) *Resolver {
+        scoped := *r
+        scoped.DB = r.DB.WithContext(ctx)
+        return &scoped
 }
@@ -0,0 +1,279 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	entityTodo  = "todo"
+	entityGroup = "group"
+)
+
+// todoSnapshot returns the fields of todo tracked in its history, keyed by
+// column name. Pointers are flattened to nil or their value and times are
+// formatted as RFC 3339 so that snapshots compare with == and survive a JSON
+// round trip.
+func todoSnapshot(todo *models.Todo) map[string]interface{} {
+	return map[string]interface{}{
+		"title":            todo.Title,
+		"description":      todo.Description,
+		"completed":        todo.Completed,
+		"completed_at":     optionalTime(todo.CompletedAt),
+		"completed_by":     optionalID(todo.CompletedBy),
+		"status_id":        optionalID(todo.StatusID),
+		"group_id":         optionalID(todo.GroupID),
+		"assignee_id":      optionalID(todo.AssigneeID),
+		"due_date":         optionalTime(todo.DueDate),
+		"estimate_minutes": optionalInt(todo.EstimateMinutes),
+		"priority":         todo.Priority,
+		"snoozed_until":    optionalTime(todo.SnoozedUntil),
+		"someday":          todo.Someday,
+		"recurrence_rule":  todo.RecurrenceRule,
+	}
+}
+
+func groupSnapshot(group *models.Group) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        group.Name,
+		"description": group.Description,
+		"color":       group.Color,
+		"parent_id":   optionalID(group.ParentID),
+		"archived_at": optionalTime(group.ArchivedAt),
+	}
+}
+
+func optionalID(id *uint) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+func optionalInt(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func diffSnapshots(before, after map[string]interface{}) models.FieldChanges {
+	changes := models.FieldChanges{}
+	for field, old := range before {
+		if now := after[field]; old != now {
+			changes[field] = models.FieldChange{Before: old, After: now}
+		}
+	}
+	return changes
+}
+
+// recordRevision stores a history entry, and the matching activity entries
+// for todos. Updates that changed nothing are not recorded.
+func recordRevision(tx *gorm.DB, entityType string, entityID, actorID uint, action string, changes models.FieldChanges) error {
+	if action == models.RevisionUpdate && len(changes) == 0 {
+		return nil
+	}
+	revision := &models.Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		ActorID:    actorID,
+		Action:     action,
+		Changes:    changes,
+	}
+	if err := joinUndoOperation(tx, revision); err != nil {
+		return err
+	}
+	if err := tx.Create(revision).Error; err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	if entityType == entityTodo {
+		return recordActivity(tx, entityID, actorID, action, changes)
+	}
+	return nil
+}
+
+// creationChanges describes a newly created entity as changes from nothing.
+func creationChanges(snapshot map[string]interface{}) models.FieldChanges {
+	changes := models.FieldChanges{}
+	for field, value := range snapshot {
+		changes[field] = models.FieldChange{After: value}
+	}
+	return changes
+}
+
+// stateAt reconstructs the tracked fields of an entity as they were right
+// after revisionID by undoing every later revision on top of current.
+func stateAt(tx *gorm.DB, entityType string, entityID, revisionID uint, current map[string]interface{}) (map[string]interface{}, error) {
+	var target models.Revision
+	if err := tx.Where("id = ? AND entity_type = ? AND entity_id = ?", revisionID, entityType, entityID).First(&target).Error; err != nil {
+		return nil, fmt.Errorf("revision not found: %w", err)
+	}
+
+	var later []models.Revision
+	if err := tx.Where("entity_type = ? AND entity_id = ? AND id > ?", entityType, entityID, revisionID).Order("id DESC").Find(&later).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch revisions: %w", err)
+	}
+
+	state := current
+	for _, revision := range later {
+		state = previousState(state, revision.Changes)
+	}
+	return state, nil
+}
+
+// previousState returns current with the fields changed by a revision set
+// back to their values before it.
+func previousState(current map[string]interface{}, changes models.FieldChanges) map[string]interface{} {
+	state := make(map[string]interface{}, len(current))
+	for field, value := range current {
+		state[field] = value
+	}
+	for field, change := range changes {
+		if _, tracked := state[field]; tracked {
+			state[field] = change.Before
+		}
+	}
+	return state
+}
+
+// applyTodoState writes snapshot values back onto todo. Values may come from
+// decoded JSON, so IDs can arrive as float64.
+func applyTodoState(todo *models.Todo, state map[string]interface{}) error {
+	var err error
+	if v, ok := state["title"].(string); ok {
+		todo.Title = v
+	}
+	if v, ok := state["description"].(string); ok {
+		todo.Description = v
+	}
+	if v, ok := state["completed"].(bool); ok {
+		todo.Completed = v
+	}
+	if v, ok := state["recurrence_rule"].(string); ok {
+		todo.RecurrenceRule = v
+	}
+	if v, ok := state["someday"].(bool); ok {
+		todo.Someday = v
+	}
+	if todo.StatusID, err = stateID(state["status_id"]); err != nil {
+		return err
+	}
+	if todo.GroupID, err = stateID(state["group_id"]); err != nil {
+		return err
+	}
+	if todo.AssigneeID, err = stateID(state["assignee_id"]); err != nil {
+		return err
+	}
+	if todo.CompletedBy, err = stateID(state["completed_by"]); err != nil {
+		return err
+	}
+	if todo.CompletedAt, err = stateTime(state["completed_at"]); err != nil {
+		return err
+	}
+	if todo.DueDate, err = stateTime(state["due_date"]); err != nil {
+		return err
+	}
+	if todo.EstimateMinutes, err = stateInt(state["estimate_minutes"]); err != nil {
+		return err
+	}
+	if todo.SnoozedUntil, err = stateTime(state["snoozed_until"]); err != nil {
+		return err
+	}
+	priority, err := stateInt(state["priority"])
+	if err != nil {
+		return err
+	}
+	if priority != nil {
+		todo.Priority = *priority
+	}
+	return nil
+}
+
+// detachMissingGroup clears todo's group when a restored group ID no longer
+// refers to a live group.
+func detachMissingGroup(tx *gorm.DB, todo *models.Todo) error {
+	if todo.GroupID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.Group{}).Where("id = ?", *todo.GroupID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check group: %w", err)
+	}
+	if count == 0 {
+		todo.GroupID = nil
+	}
+	return nil
+}
+
+func applyGroupState(group *models.Group, state map[string]interface{}) error {
+	if v, ok := state["name"].(string); ok {
+		group.Name = v
+	}
+	if v, ok := state["description"].(string); ok {
+		group.Description = v
+	}
+	if v, ok := state["color"].(string); ok {
+		group.Color = v
+	}
+	var err error
+	if group.ParentID, err = stateID(state["parent_id"]); err != nil {
+		return err
+	}
+	group.ArchivedAt, err = stateTime(state["archived_at"])
+	return err
+}
+
+func stateID(value interface{}) (*uint, error) {
+	var id uint
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case uint:
+		id = v
+	case float64:
+		id = uint(v)
+	default:
+		return nil, fmt.Errorf("unexpected ID value %v in revision", value)
+	}
+	return &id, nil
+}
+
+func stateInt(value interface{}) (*int, error) {
+	var n int
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case int:
+		n = v
+	case float64:
+		n = int(v)
+	default:
+		return nil, fmt.Errorf("unexpected integer value %v in revision", value)
+	}
+	return &n, nil
+}
+
+func stateTime(value interface{}) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected time value %v in revision", value)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected time value %q in revision", s)
+	}
+	return &t, nil
+}
@@ -7,12 +7,72 @@ package graph
 
 import (
         "context"
+        "errors"
         "fmt"
+        "log"
+        "sort"
         "strconv"
+        "strings"
+        "time"
         "todo-app/graph/model"
         "todo-app/models"
+        "todo-app/notifier"
+        "todo-app/recurrence"
+        "todo-app/scheduler"
+        "todo-app/search"
+        "todo-app/trash"
+        "unicode/utf8"
+
+        "github.com/99designs/gqlgen/graphql"
+        "gorm.io/gorm"
+        "gorm.io/gorm/clause"
 )
 
+// ID is the resolver for the id field.
+func (r *attachmentResolver) ID(ctx context.Context, obj *models.Attachment) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// TodoID is the resolver for the todoId field.
+func (r *attachmentResolver) TodoID(ctx context.Context, obj *models.Attachment) (string, error) {
+        return strconv.FormatUint(uint64(obj.TodoID), 10), nil
+}
+
+// UserID is the resolver for the userId field.
+func (r *attachmentResolver) UserID(ctx context.Context, obj *models.Attachment) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
+
+// ID is the resolver for the id field.
+func (r *commentResolver) ID(ctx context.Context, obj *models.Comment) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// TodoID is the resolver for the todoId field.
+func (r *commentResolver) TodoID(ctx context.Context, obj *models.Comment) (string, error) {
+        return strconv.FormatUint(uint64(obj.TodoID), 10), nil
+}
+
+// AuthorID is the resolver for the authorId field.
+func (r *commentResolver) AuthorID(ctx context.Context, obj *models.Comment) (string, error) {
+        return strconv.FormatUint(uint64(obj.AuthorID), 10), nil
+}
+
+// ID is the resolver for the id field.
+func (r *dependencyResolver) ID(ctx context.Context, obj *models.Dependency) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// TodoID is the resolver for the todoId field.
+func (r *dependencyResolver) TodoID(ctx context.Context, obj *models.Dependency) (string, error) {
+        return strconv.FormatUint(uint64(obj.TodoID), 10), nil
+}
+
+// BlockerID is the resolver for the blockerId field.
+func (r *dependencyResolver) BlockerID(ctx context.Context, obj *models.Dependency) (string, error) {
+        return strconv.FormatUint(uint64(obj.BlockerID), 10), nil
+}
+
 // ID is the resolver for the id field.
 func (r *groupResolver) ID(ctx context.Context, obj *models.Group) (string, error) {
         return strconv.FormatUint(uint64(obj.ID), 10), nil
@@ -23,6 +83,165 @@ func (r *groupResolver) UserID(ctx context.Context, obj *models.Group) (string,
         return strconv.FormatUint(uint64(obj.UserID), 10), nil
 }
 
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *groupResolver) WorkspaceID(ctx context.Context, obj *models.Group) (string, error) {
+        return strconv.FormatUint(uint64(obj.WorkspaceID), 10), nil
+}
+
+// ParentID is the resolver for the parentId field.
+func (r *groupResolver) ParentID(ctx context.Context, obj *models.Group) (*string, error) {
+        if obj.ParentID == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.ParentID), 10)
+        return &id, nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *groupResolver) Parent(ctx context.Context, obj *models.Group) (*models.Group, error) {
+        if obj.ParentID == nil {
+                return nil, nil
+        }
+        var group models.Group
+        if err := r.DB.First(&group, *obj.ParentID).Error; err != nil {
+                return nil, nil
+        }
+        return &group, nil
+}
+
+// Children is the resolver for the children field.
+func (r *groupResolver) Children(ctx context.Context, obj *models.Group, userID string) ([]*models.Group, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var groups []*models.Group
+        if err := visibleGroups(r.DB, uint(uid)).Where("parent_id = ?", obj.ID).Find(&groups).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch subgroups: %w", err)
+        }
+        return groups, nil
+}
+
+// Archived is the resolver for the archived field.
+func (r *groupResolver) Archived(ctx context.Context, obj *models.Group) (bool, error) {
+        return obj.ArchivedAt != nil, nil
+}
+
+// DeletedAt is the resolver for the deletedAt field.
+func (r *groupResolver) DeletedAt(ctx context.Context, obj *models.Group) (*time.Time, error) {
+        if !obj.DeletedAt.Valid {
+                return nil, nil
+        }
+        return &obj.DeletedAt.Time, nil
+}
+
+// Todos is the resolver for the todos field.
+func (r *groupResolver) Todos(ctx context.Context, obj *models.Group, userID string, filter *model.TodoFilter, sort *model.TodoSort) ([]*models.Todo, error) {
+        f := model.TodoFilter{}
+        if filter != nil {
+                f = *filter
+        }
+        groupID := strconv.FormatUint(uint64(obj.ID), 10)
+        f.GroupID = &groupID
+        return (&queryResolver{r.Resolver}).Todos(ctx, userID, &f, sort)
+}
+
+// Stats is the resolver for the stats field.
+func (r *groupResolver) Stats(ctx context.Context, obj *models.Group) (*model.GroupStats, error) {
+        stats, err := groupStats(r.DB, []*models.Group{obj})
+        if err != nil {
+                return nil, err
+        }
+        return stats[obj.ID], nil
+}
+
+// Pinned is the resolver for the pinned field.
+func (r *groupResolver) Pinned(ctx context.Context, obj *models.Group, userID string) (bool, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return isPinned(r.DB, uint(uid), entityGroup, obj.ID)
+}
+
+// Role is the resolver for the role field.
+func (r *groupResolver) Role(ctx context.Context, obj *models.Group, userID string) (*model.GroupRole, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        role, err := groupRole(r.DB, obj.ID, uint(uid))
+        if err != nil || role == "" {
+                return nil, err
+        }
+        result := groupRoleToModel(role)
+        return &result, nil
+}
+
+// Members is the resolver for the members field.
+func (r *groupResolver) Members(ctx context.Context, obj *models.Group) ([]*models.GroupMember, error) {
+        var members []*models.GroupMember
+        if err := r.DB.Where("group_id = ?", obj.ID).Order("created_at, id").Find(&members).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch members: %w", err)
+        }
+        return members, nil
+}
+
+// ID is the resolver for the id field.
+func (r *groupInvitationResolver) ID(ctx context.Context, obj *models.GroupInvitation) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// GroupID is the resolver for the groupId field.
+func (r *groupInvitationResolver) GroupID(ctx context.Context, obj *models.GroupInvitation) (string, error) {
+        return strconv.FormatUint(uint64(obj.GroupID), 10), nil
+}
+
+// Group is the resolver for the group field.
+func (r *groupInvitationResolver) Group(ctx context.Context, obj *models.GroupInvitation) (*models.Group, error) {
+        var group models.Group
+        if err := models.AllWorkspaces(r.DB).First(&group, obj.GroupID).Error; err != nil {
+                return nil, nil
+        }
+        return &group, nil
+}
+
+// Role is the resolver for the role field.
+func (r *groupInvitationResolver) Role(ctx context.Context, obj *models.GroupInvitation) (model.GroupRole, error) {
+        return groupRoleToModel(obj.Role), nil
+}
+
+// InvitedBy is the resolver for the invitedBy field.
+func (r *groupInvitationResolver) InvitedBy(ctx context.Context, obj *models.GroupInvitation) (string, error) {
+        return strconv.FormatUint(uint64(obj.InvitedBy), 10), nil
+}
+
+// GroupID is the resolver for the groupId field.
+func (r *groupMemberResolver) GroupID(ctx context.Context, obj *models.GroupMember) (string, error) {
+        return strconv.FormatUint(uint64(obj.GroupID), 10), nil
+}
+
+// UserID is the resolver for the userId field.
+func (r *groupMemberResolver) UserID(ctx context.Context, obj *models.GroupMember) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
+
+// Email is the resolver for the email field.
+func (r *groupMemberResolver) Email(ctx context.Context, obj *models.GroupMember) (string, error) {
+        var user models.User
+        if err := r.DB.Unscoped().Select("email").First(&user, obj.UserID).Error; err != nil {
+                return "", fmt.Errorf("failed to fetch member: %w", err)
+        }
+        return user.Email, nil
+}
+
+// Role is the resolver for the role field.
+func (r *groupMemberResolver) Role(ctx context.Context, obj *models.GroupMember) (model.GroupRole, error) {
+        return groupRoleToModel(obj.Role), nil
+}
+
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*models.User, error) {
         user := &models.User{
@@ -39,46 +258,132 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUse
                 user.IsAdmin = true
         }
 
-        if err := r.DB.Create(user).Error; err != nil {
-                return nil, fmt.Errorf("failed to create user: %w", err)
+        err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Create(user).Error; err != nil {
+                        return fmt.Errorf("failed to create user: %w", err)
+                }
+                return setupWorkspaces(tx, user)
+        })
+        if err != nil {
+                return nil, err
         }
 
         return user, nil
 }
 
 // DeleteUser is the resolver for the deleteUser field.
-func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
-        userID, err := strconv.ParseUint(id, 10, 64)
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string, userID string, successorID *string) (bool, error) {
+        targetID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+        uid, err := strconv.ParseUint(userID, 10, 64)
         if err != nil {
                 return false, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        var user models.User
-        if err := r.DB.First(&user, userID).Error; err != nil {
-                return false, fmt.Errorf("user not found: %w", err)
+        user, err := managedUser(r.DB, uint(targetID), uint(uid))
+        if err != nil {
+                return false, err
+        }
+        if err := checkUserDeletable(r.DB, user, uint(uid)); err != nil {
+                return false, err
         }
 
-        if err := r.DB.Where("user_id = ?", userID).Delete(&models.Todo{}).Error; err != nil {
-                return false, fmt.Errorf("failed to delete user's todos: %w", err)
+        var successor *models.User
+        if successorID != nil && *successorID != "" {
+                sid, err := strconv.ParseUint(*successorID, 10, 64)
+                if err != nil {
+                        return false, fmt.Errorf("invalid successor ID: %w", err)
+                }
+                if uint(sid) == user.ID {
+                        return false, fmt.Errorf("%w: a user cannot succeed themselves", ErrInvalidInput)
+                }
+                successor = &models.User{}
+                if err := r.DB.First(successor, sid).Error; err != nil {
+                        return false, fmt.Errorf("%w: successor not found", ErrInvalidInput)
+                }
         }
 
-        result := r.DB.Delete(&user)
-        if result.Error != nil {
-                return false, fmt.Errorf("failed to delete user: %w", result.Error)
+        var deleted bool
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                tx = models.AllWorkspaces(tx)
+
+                // With a successor their groups, and what they added to
+                // other people's groups, live on; only personal todos go.
+                if successor != nil {
+                        var groups []models.Group
+                        if err := tx.Unscoped().Where("user_id = ?", user.ID).Find(&groups).Error; err != nil {
+                                return fmt.Errorf("failed to fetch user's groups: %w", err)
+                        }
+                        if err := checkSuccessor(tx, user, successor.ID); err != nil {
+                                return err
+                        }
+                        for i := range groups {
+                                if err := transferGroup(tx, &groups[i], successor.ID, false, uint(uid)); err != nil {
+                                        return err
+                                }
+                        }
+                        if err := tx.Unscoped().Model(&models.Todo{}).Where("user_id = ? AND group_id IS NOT NULL", user.ID).Update("user_id", successor.ID).Error; err != nil {
+                                return fmt.Errorf("failed to reassign user's todos: %w", err)
+                        }
+                }
+
+                if err := tx.Where("user_id = ?", user.ID).Delete(&models.Todo{}).Error; err != nil {
+                        return fmt.Errorf("failed to delete user's todos: %w", err)
+                }
+                if err := tx.Model(&models.Todo{}).Where("assignee_id = ?", user.ID).Update("assignee_id", nil).Error; err != nil {
+                        return fmt.Errorf("failed to unassign user's todos: %w", err)
+                }
+                if err := tx.Where("user_id = ?", user.ID).Delete(&models.GroupMember{}).Error; err != nil {
+                        return fmt.Errorf("failed to remove user's memberships: %w", err)
+                }
+                if err := tx.Where("user_id = ?", user.ID).Delete(&models.WorkspaceMember{}).Error; err != nil {
+                        return fmt.Errorf("failed to remove user's workspace memberships: %w", err)
+                }
+                if err := tx.Where("user_id = ?", user.ID).Delete(&models.SmartList{}).Error; err != nil {
+                        return fmt.Errorf("failed to delete user's smart lists: %w", err)
+                }
+                if err := tx.Where("user_id = ?", user.ID).Delete(&models.Pin{}).Error; err != nil {
+                        return fmt.Errorf("failed to delete user's pins: %w", err)
+                }
+
+                result := tx.Delete(user)
+                if result.Error != nil {
+                        return fmt.Errorf("failed to delete user: %w", result.Error)
+                }
+                deleted = result.RowsAffected > 0
+                return nil
+        })
+        if err != nil {
+                return false, err
         }
 
-        return result.RowsAffected > 0, nil
+        return deleted, nil
 }
 
 // UpdateUserAdmin is the resolver for the updateUserAdmin field.
-func (r *mutationResolver) UpdateUserAdmin(ctx context.Context, id string, input model.UpdateUserAdminInput) (*models.User, error) {
-        userID, err := strconv.ParseUint(id, 10, 64)
+func (r *mutationResolver) UpdateUserAdmin(ctx context.Context, id string, userID string, input model.UpdateUserAdminInput) (*models.User, error) {
+        targetID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        uid, err := strconv.ParseUint(userID, 10, 64)
         if err != nil {
                 return nil, fmt.Errorf("invalid user ID: %w", err)
         }
 
+        // The global admin flag is platform-level, outside any workspace:
+        // only global admins may grant or revoke it, for any user.
+        admin, err := isAdmin(r.DB, uint(uid))
+        if err != nil {
+                return nil, err
+        }
+        if !admin {
+                return nil, fmt.Errorf("%w: requires global admin", ErrForbidden)
+        }
         var user models.User
-        if err := r.DB.First(&user, userID).Error; err != nil {
+        if err := r.DB.First(&user, targetID).Error; err != nil {
                 return nil, fmt.Errorf("user not found: %w", err)
         }
 
@@ -97,10 +402,36 @@ func (r *mutationResolver) CreateTodo(ctx context.Context, userID string, input
                 return nil, fmt.Errorf("invalid user ID: %w", err)
         }
 
+        if input.QuickAdd != nil && *input.QuickAdd {
+                parsed, err := parseQuickAdd(r.DB, uint(uid), input.Title, input.TimeZone)
+                if err != nil {
+                        return nil, err
+                }
+                if err := applyQuickAdd(&input, parsed); err != nil {
+                        return nil, err
+                }
+        }
+
         todo := &models.Todo{
                 Title:       input.Title,
                 Description: input.Description,
                 UserID:      uint(uid),
+                DueDate:     input.DueDate,
+        }
+        if input.Priority != nil {
+                todo.Priority = priorityLevel(*input.Priority)
+        }
+
+        if todo.EstimateMinutes, err = validateEstimate(input.EstimateMinutes); err != nil {
+                return nil, err
+        }
+
+        if input.RecurrenceRule != nil && *input.RecurrenceRule != "" {
+                rule, err := recurrence.Normalize(*input.RecurrenceRule)
+                if err != nil {
+                        return nil, err
+                }
+                todo.RecurrenceRule = rule
         }
 
         if input.GroupID != nil && *input.GroupID != "" {
@@ -108,16 +439,49 @@ func (r *mutationResolver) CreateTodo(ctx context.Context, userID string, input
                 if err != nil {
                         return nil, fmt.Errorf("invalid group ID: %w", err)
                 }
-                var group models.Group
-                if err := r.DB.Where("id = ? AND user_id = ?", gid, uid).First(&group).Error; err != nil {
-                        return nil, fmt.Errorf("group not found or not owned by user")
+                if _, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleEditor); err != nil {
+                        return nil, err
                 }
                 groupID := uint(gid)
                 todo.GroupID = &groupID
         }
 
-        if err := r.DB.Create(todo).Error; err != nil {
-                return nil, fmt.Errorf("failed to create todo: %w", err)
+        if input.AssigneeID != nil {
+                if err := setAssignee(r.DB, todo, *input.AssigneeID); err != nil {
+                        return nil, err
+                }
+        }
+
+        if input.StatusID != nil && *input.StatusID != "" {
+                sid, err := strconv.ParseUint(*input.StatusID, 10, 64)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid status ID: %w", err)
+                }
+                if err := setTodoStatus(r.DB, todo, uint(sid)); err != nil {
+                        return nil, err
+                }
+        } else if err := syncTodoStatus(r.DB, todo); err != nil {
+                return nil, err
+        }
+        markCompletion(todo, false, uint(uid))
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Create(todo).Error; err != nil {
+                        return fmt.Errorf("failed to create todo: %w", err)
+                }
+                if todo.RecurrenceRule != "" {
+                        startSeries(todo)
+                        if err := tx.Save(todo).Error; err != nil {
+                                return fmt.Errorf("failed to create todo: %w", err)
+                        }
+                }
+                if err := notifyAssignee(tx, todo, nil, uint(uid)); err != nil {
+                        return err
+                }
+                return recordRevision(tx, entityTodo, todo.ID, uint(uid), models.RevisionCreate, creationChanges(todoSnapshot(todo)))
+        })
+        if err != nil {
+                return nil, err
         }
 
         return todo, nil
@@ -135,20 +499,60 @@ func (r *mutationResolver) UpdateTodo(ctx context.Context, id string, userID str
                 return nil, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        var todo models.Todo
-        if err := r.DB.Where("id = ? AND user_id = ?", todoID, uid).First(&todo).Error; err != nil {
-                return nil, fmt.Errorf("todo not found: %w", err)
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
         }
+        before := todoSnapshot(todo)
+        previousAssignee := todo.AssigneeID
+
+        scope := model.EditScopeThis
+        if input.Scope != nil {
+                scope = *input.Scope
+        }
+        if scope == model.EditScopeSeries && todo.SeriesID == nil {
+                return nil, fmt.Errorf("todo is not part of a recurring series")
+        }
+        seriesUpdates := map[string]interface{}{}
 
         if input.Title != nil {
                 todo.Title = *input.Title
+                seriesUpdates["title"] = todo.Title
         }
         if input.Description != nil {
                 todo.Description = *input.Description
+                seriesUpdates["description"] = todo.Description
         }
+        wasCompleted := todo.Completed
         if input.Completed != nil {
                 todo.Completed = *input.Completed
         }
+        if input.DueDate != nil {
+                todo.DueDate = input.DueDate
+        }
+        if input.EstimateMinutes != nil {
+                if todo.EstimateMinutes, err = validateEstimate(input.EstimateMinutes); err != nil {
+                        return nil, err
+                }
+                seriesUpdates["estimate_minutes"] = todo.EstimateMinutes
+        }
+        if input.Priority != nil {
+                todo.Priority = priorityLevel(*input.Priority)
+                seriesUpdates["priority"] = todo.Priority
+        }
+        if input.RecurrenceRule != nil {
+                if *input.RecurrenceRule == "" {
+                        todo.RecurrenceRule = ""
+                } else {
+                        rule, err := recurrence.Normalize(*input.RecurrenceRule)
+                        if err != nil {
+                                return nil, err
+                        }
+                        todo.RecurrenceRule = rule
+                        startSeries(todo)
+                }
+                seriesUpdates["recurrence_rule"] = todo.RecurrenceRule
+        }
         if input.GroupID != nil {
                 if *input.GroupID == "" {
                         todo.GroupID = nil
@@ -157,20 +561,85 @@ func (r *mutationResolver) UpdateTodo(ctx context.Context, id string, userID str
                         if err != nil {
                                 return nil, fmt.Errorf("invalid group ID: %w", err)
                         }
-                        var group models.Group
-                        if err := r.DB.Where("id = ? AND user_id = ?", gid, uid).First(&group).Error; err != nil {
-                                return nil, fmt.Errorf("group not found or not owned by user")
+                        if _, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleEditor); err != nil {
+                                return nil, err
                         }
                         groupID := uint(gid)
                         todo.GroupID = &groupID
                 }
+                seriesUpdates["group_id"] = todo.GroupID
         }
+        if input.AssigneeID != nil {
+                if err := setAssignee(r.DB, todo, *input.AssigneeID); err != nil {
+                        return nil, err
+                }
+                seriesUpdates["assignee_id"] = todo.AssigneeID
+        } else if input.GroupID != nil {
+                if err := detachInvalidAssignee(r.DB, todo); err != nil {
+                        return nil, err
+                }
+                seriesUpdates["assignee_id"] = todo.AssigneeID
+        }
+
+        if input.StatusID != nil && *input.StatusID != "" {
+                sid, err := strconv.ParseUint(*input.StatusID, 10, 64)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid status ID: %w", err)
+                }
+                if err := setTodoStatus(r.DB, todo, uint(sid)); err != nil {
+                        return nil, err
+                }
+        } else if input.StatusID != nil {
+                todo.StatusID = nil
+        } else if input.Completed != nil || input.GroupID != nil {
+                if err := syncTodoStatus(r.DB, todo); err != nil {
+                        return nil, err
+                }
+        }
+
+        if !wasCompleted && todo.Completed && r.RefuseBlockedCompletion {
+                if err := checkNotBlocked(r.DB, todo); err != nil {
+                        return nil, err
+                }
+        }
+
+        markCompletion(todo, wasCompleted, uint(uid))
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Save(todo).Error; err != nil {
+                        return fmt.Errorf("failed to update todo: %w", err)
+                }
+                if err := recordRevision(tx, entityTodo, todo.ID, uint(uid), models.RevisionUpdate, diffSnapshots(before, todoSnapshot(todo))); err != nil {
+                        return err
+                }
+                if err := notifyAssignee(tx, todo, previousAssignee, uint(uid)); err != nil {
+                        return err
+                }
+
+                if scope == model.EditScopeSeries && len(seriesUpdates) > 0 {
+                        if err := updateSeries(tx, todo, uint(uid), seriesUpdates); err != nil {
+                                return err
+                        }
+                }
 
-        if err := r.DB.Save(&todo).Error; err != nil {
-                return nil, fmt.Errorf("failed to update todo: %w", err)
+                if input.DueDate != nil {
+                        if err := scheduler.ScheduleTodoReminders(tx, todo); err != nil {
+                                return err
+                        }
+                }
+
+                if !wasCompleted && todo.Completed {
+                        if _, err := createNextOccurrence(tx, todo); err != nil {
+                                return err
+                        }
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
         }
 
-        return &todo, nil
+        return todo, nil
 }
 
 // DeleteTodo is the resolver for the deleteTodo field.
@@ -185,58 +654,318 @@ func (r *mutationResolver) DeleteTodo(ctx context.Context, id string, userID str
                 return false, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        result := r.DB.Where("id = ? AND user_id = ?", todoID, uid).Delete(&models.Todo{})
-        if result.Error != nil {
-                return false, fmt.Errorf("failed to delete todo: %w", result.Error)
+        var deleted bool
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                todo, err := todoAccess(tx, uint(todoID), uint(uid), models.RoleEditor)
+                if err != nil {
+                        return err
+                }
+                result := tx.Delete(todo)
+                if result.Error != nil {
+                        return fmt.Errorf("failed to delete todo: %w", result.Error)
+                }
+                if deleted = result.RowsAffected > 0; !deleted {
+                        return nil
+                }
+                return recordRevision(tx, entityTodo, uint(todoID), uint(uid), models.RevisionDelete, nil)
+        })
+        if err != nil {
+                return false, err
         }
 
-        return result.RowsAffected > 0, nil
+        return deleted, nil
 }
 
-// CreateGroup is the resolver for the createGroup field.
-func (r *mutationResolver) CreateGroup(ctx context.Context, userID string, input model.CreateGroupInput) (*models.Group, error) {
-        uid, err := strconv.ParseUint(userID, 10, 64)
+// SkipTodoOccurrence is the resolver for the skipTodoOccurrence field.
+func (r *mutationResolver) SkipTodoOccurrence(ctx context.Context, id string, userID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
         if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
         }
 
-        group := &models.Group{
-                Name:   input.Name,
-                UserID: uint(uid),
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        if input.Description != nil {
-                group.Description = *input.Description
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
         }
-        if input.Color != nil {
-                group.Color = *input.Color
+        if todo.RecurrenceRule == "" || todo.SeriesID == nil {
+                return nil, fmt.Errorf("todo is not recurring")
         }
 
-        if err := r.DB.Create(group).Error; err != nil {
-                return nil, fmt.Errorf("failed to create group: %w", err)
+        var next *models.Todo
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                var err error
+                if next, err = createNextOccurrence(tx, todo); err != nil {
+                        return err
+                }
+                if err := tx.Delete(todo).Error; err != nil {
+                        return fmt.Errorf("failed to skip occurrence: %w", err)
+                }
+                return recordRevision(tx, entityTodo, todo.ID, uint(uid), models.RevisionDelete, nil)
+        })
+        if err != nil {
+                return nil, err
         }
 
-        return group, nil
+        return next, nil
 }
 
-// UpdateGroup is the resolver for the updateGroup field.
-func (r *mutationResolver) UpdateGroup(ctx context.Context, id string, userID string, input model.UpdateGroupInput) (*models.Group, error) {
-        groupID, err := strconv.ParseUint(id, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid group ID: %w", err)
-        }
-
+// CreateWorkspace is the resolver for the createWorkspace field.
+func (r *mutationResolver) CreateWorkspace(ctx context.Context, userID string, input model.CreateWorkspaceInput) (*models.Workspace, error) {
         uid, err := strconv.ParseUint(userID, 10, 64)
         if err != nil {
                 return nil, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        var group models.Group
-        if err := r.DB.Where("id = ? AND user_id = ?", groupID, uid).First(&group).Error; err != nil {
-                return nil, fmt.Errorf("group not found: %w", err)
+        name := strings.TrimSpace(input.Name)
+        if name == "" {
+                return nil, fmt.Errorf("%w: workspace name is required", ErrInvalidInput)
+        }
+        workspace := &models.Workspace{Name: name}
+        if workspace.AllowedEmailDomains, err = normalizeDomains(input.AllowedEmailDomains); err != nil {
+                return nil, err
         }
 
-        if input.Name != nil {
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                var user models.User
+                if err := tx.First(&user, uid).Error; err != nil {
+                        return fmt.Errorf("user not found: %w", err)
+                }
+                if err := tx.Create(workspace).Error; err != nil {
+                        return fmt.Errorf("failed to create workspace: %w", err)
+                }
+                // The creator always joins, whatever the allowed domains.
+                member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: user.ID, Role: models.WorkspaceRoleAdmin}
+                if err := tx.Create(&member).Error; err != nil {
+                        return fmt.Errorf("failed to add workspace member: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return workspace, nil
+}
+
+// UpdateWorkspace is the resolver for the updateWorkspace field.
+func (r *mutationResolver) UpdateWorkspace(ctx context.Context, id string, userID string, input model.UpdateWorkspaceInput) (*models.Workspace, error) {
+        wid, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        workspace, err := workspaceAccess(r.DB, uint(wid), uint(uid), true)
+        if err != nil {
+                return nil, err
+        }
+
+        if input.Name != nil {
+                name := strings.TrimSpace(*input.Name)
+                if name == "" {
+                        return nil, fmt.Errorf("%w: workspace name is required", ErrInvalidInput)
+                }
+                workspace.Name = name
+        }
+        if input.AllowedEmailDomains != nil {
+                // Existing members stay; the domains only apply to new ones.
+                if workspace.AllowedEmailDomains, err = normalizeDomains(input.AllowedEmailDomains); err != nil {
+                        return nil, err
+                }
+        }
+
+        if err := r.DB.Save(workspace).Error; err != nil {
+                return nil, fmt.Errorf("failed to update workspace: %w", err)
+        }
+
+        return workspace, nil
+}
+
+// AddWorkspaceMember is the resolver for the addWorkspaceMember field.
+func (r *mutationResolver) AddWorkspaceMember(ctx context.Context, workspaceID string, userID string, email string, role model.WorkspaceRole) (*models.WorkspaceMember, error) {
+        wid, err := strconv.ParseUint(workspaceID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        workspace, err := workspaceAccess(r.DB, uint(wid), uint(uid), true)
+        if err != nil {
+                return nil, err
+        }
+
+        var user models.User
+        if err := r.DB.Where("LOWER(email) = ?", normalizeEmail(email)).First(&user).Error; err != nil {
+                return nil, fmt.Errorf("%w: no user with email %s", ErrInvalidInput, email)
+        }
+
+        return addWorkspaceMember(r.DB, workspace, &user, workspaceRoleFromModel(role))
+}
+
+// UpdateWorkspaceMember is the resolver for the updateWorkspaceMember field.
+func (r *mutationResolver) UpdateWorkspaceMember(ctx context.Context, workspaceID string, memberID string, userID string, role model.WorkspaceRole) (*models.WorkspaceMember, error) {
+        wid, err := strconv.ParseUint(workspaceID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        mid, err := strconv.ParseUint(memberID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid member ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        workspace, err := workspaceAccess(r.DB, uint(wid), uint(uid), true)
+        if err != nil {
+                return nil, err
+        }
+
+        var member models.WorkspaceMember
+        if err := r.DB.Where("workspace_id = ? AND user_id = ?", workspace.ID, mid).First(&member).Error; err != nil {
+                return nil, fmt.Errorf("member not found: %w", err)
+        }
+
+        newRole := workspaceRoleFromModel(role)
+        if member.Role == models.WorkspaceRoleAdmin && newRole != models.WorkspaceRoleAdmin {
+                last, err := lastWorkspaceAdmin(r.DB, workspace.ID, member.UserID)
+                if err != nil {
+                        return nil, err
+                }
+                if last {
+                        return nil, fmt.Errorf("%w: a workspace needs at least one admin", ErrInvalidInput)
+                }
+        }
+
+        member.Role = newRole
+        if err := r.DB.Save(&member).Error; err != nil {
+                return nil, fmt.Errorf("failed to update member: %w", err)
+        }
+
+        return &member, nil
+}
+
+// RemoveWorkspaceMember is the resolver for the removeWorkspaceMember field.
+func (r *mutationResolver) RemoveWorkspaceMember(ctx context.Context, workspaceID string, memberID string, userID string) (bool, error) {
+        wid, err := strconv.ParseUint(workspaceID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        mid, err := strconv.ParseUint(memberID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid member ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        // Members may leave on their own; removing someone else takes an admin.
+        workspace, err := workspaceAccess(r.DB, uint(wid), uint(uid), mid != uid)
+        if err != nil {
+                return false, err
+        }
+
+        role, err := workspaceRole(r.DB, workspace.ID, uint(mid))
+        if err != nil {
+                return false, err
+        }
+        if role == "" {
+                return false, fmt.Errorf("member not found: %w", gorm.ErrRecordNotFound)
+        }
+        if role == models.WorkspaceRoleAdmin {
+                last, err := lastWorkspaceAdmin(r.DB, workspace.ID, uint(mid))
+                if err != nil {
+                        return false, err
+                }
+                if last {
+                        return false, fmt.Errorf("%w: a workspace needs at least one admin", ErrInvalidInput)
+                }
+        }
+
+        var removed bool
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                removed, err = removeWorkspaceMember(tx, workspace.ID, uint(mid), uint(uid))
+                return err
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return removed, nil
+}
+
+// CreateGroup is the resolver for the createGroup field.
+func (r *mutationResolver) CreateGroup(ctx context.Context, userID string, input model.CreateGroupInput) (*models.Group, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group := &models.Group{
+                Name:   input.Name,
+                UserID: uint(uid),
+        }
+
+        if input.Description != nil {
+                group.Description = *input.Description
+        }
+        if input.Color != nil {
+                group.Color = *input.Color
+        }
+        if input.ParentID != nil {
+                if err := setGroupParent(r.DB, group, *input.ParentID, uint(uid)); err != nil {
+                        return nil, err
+                }
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return createGroup(tx, group)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// UpdateGroup is the resolver for the updateGroup field.
+func (r *mutationResolver) UpdateGroup(ctx context.Context, id string, userID string, input model.UpdateGroupInput) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        before := groupSnapshot(group)
+
+        if input.Name != nil {
                 group.Name = *input.Name
         }
         if input.Description != nil {
@@ -246,15 +975,24 @@ func (r *mutationResolver) UpdateGroup(ctx context.Context, id string, userID st
                 group.Color = *input.Color
         }
 
-        if err := r.DB.Save(&group).Error; err != nil {
-                return nil, fmt.Errorf("failed to update group: %w", err)
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := validateGroup(tx, group); err != nil {
+                        return err
+                }
+                if err := groupWriteError(tx.Save(group).Error, "update group"); err != nil {
+                        return err
+                }
+                return recordRevision(tx, entityGroup, group.ID, uint(uid), models.RevisionUpdate, diffSnapshots(before, groupSnapshot(group)))
+        })
+        if err != nil {
+                return nil, err
         }
 
-        return &group, nil
+        return group, nil
 }
 
 // DeleteGroup is the resolver for the deleteGroup field.
-func (r *mutationResolver) DeleteGroup(ctx context.Context, id string, userID string) (bool, error) {
+func (r *mutationResolver) DeleteGroup(ctx context.Context, id string, userID string, mode *model.GroupDeleteMode) (bool, error) {
         groupID, err := strconv.ParseUint(id, 10, 64)
         if err != nil {
                 return false, fmt.Errorf("invalid group ID: %w", err)
@@ -265,141 +1003,3081 @@ func (r *mutationResolver) DeleteGroup(ctx context.Context, id string, userID st
                 return false, fmt.Errorf("invalid user ID: %w", err)
         }
 
-        var group models.Group
-        if err := r.DB.Where("id = ? AND user_id = ?", groupID, uid).First(&group).Error; err != nil {
-                return false, fmt.Errorf("group not found or not owned by user")
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleOwner)
+        if err != nil {
+                return false, err
+        }
+
+        cascade := mode != nil && *mode == model.GroupDeleteModeCascade
+
+        var deleted bool
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if !cascade {
+                        if err := reparentChildren(tx, group, uint(uid)); err != nil {
+                                return err
+                        }
+                        deleted, err = deleteGroup(tx, group, uint(uid))
+                        return err
+                }
+
+                subtree, err := groupSubtree(tx, group.ID)
+                if err != nil {
+                        return err
+                }
+                // Deepest subgroups go first so that undoing restores every
+                // group before the ones below it.
+                for i := len(subtree) - 1; i > 0; i-- {
+                        subgroup, err := groupAccess(tx, subtree[i], uint(uid), models.RoleOwner)
+                        if err != nil {
+                                return err
+                        }
+                        if _, err := deleteGroup(tx, subgroup, uint(uid)); err != nil {
+                                return err
+                        }
+                }
+                deleted, err = deleteGroup(tx, group, uint(uid))
+                return err
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return deleted, nil
+}
+
+// MoveGroup is the resolver for the moveGroup field.
+func (r *mutationResolver) MoveGroup(ctx context.Context, id string, userID string, parentID *string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        before := groupSnapshot(group)
+
+        parent := ""
+        if parentID != nil {
+                parent = *parentID
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := setGroupParent(tx, group, parent, uint(uid)); err != nil {
+                        return err
+                }
+                if err := tx.Model(group).Update("parent_id", group.ParentID).Error; err != nil {
+                        return fmt.Errorf("failed to move group: %w", err)
+                }
+                return recordRevision(tx, entityGroup, group.ID, uint(uid), models.RevisionUpdate, diffSnapshots(before, groupSnapshot(group)))
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// ArchiveGroup is the resolver for the archiveGroup field.
+func (r *mutationResolver) ArchiveGroup(ctx context.Context, id string, userID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return setGroupArchived(tx, group, true, uint(uid))
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// UnarchiveGroup is the resolver for the unarchiveGroup field.
+func (r *mutationResolver) UnarchiveGroup(ctx context.Context, id string, userID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return setGroupArchived(tx, group, false, uint(uid))
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// CreateSmartList is the resolver for the createSmartList field.
+func (r *mutationResolver) CreateSmartList(ctx context.Context, userID string, input model.CreateSmartListInput) (*models.SmartList, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        list := &models.SmartList{
+                Name:   input.Name,
+                UserID: uint(uid),
+        }
+        if input.TimeZone != nil {
+                list.TimeZone = *input.TimeZone
+        }
+
+        v := &ValidationError{}
+        validateSmartList(list, v)
+        list.Filter, err = smartListFilterFromInput(r.DB, input.Filter, uint(uid), v)
+        if err != nil {
+                return nil, err
+        }
+        if err := v.err(); err != nil {
+                return nil, err
+        }
+
+        if err := r.DB.Create(list).Error; err != nil {
+                return nil, fmt.Errorf("failed to create smart list: %w", err)
+        }
+
+        return list, nil
+}
+
+// UpdateSmartList is the resolver for the updateSmartList field.
+func (r *mutationResolver) UpdateSmartList(ctx context.Context, id string, userID string, input model.UpdateSmartListInput) (*models.SmartList, error) {
+        listID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid smart list ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        list, err := ownSmartList(r.DB, uint(listID), uint(uid))
+        if err != nil {
+                return nil, err
         }
 
-        if err := r.DB.Model(&models.Todo{}).Where("group_id = ? AND user_id = ?", groupID, uid).Update("group_id", nil).Error; err != nil {
-                return false, fmt.Errorf("failed to unlink todos from group: %w", err)
+        if input.Name != nil {
+                list.Name = *input.Name
+        }
+        if input.TimeZone != nil {
+                list.TimeZone = *input.TimeZone
         }
 
-        result := r.DB.Delete(&group)
-        if result.Error != nil {
-                return false, fmt.Errorf("failed to delete group: %w", result.Error)
-        }
+        v := &ValidationError{}
+        validateSmartList(list, v)
+        if input.Filter != nil {
+                list.Filter, err = smartListFilterFromInput(r.DB, input.Filter, uint(uid), v)
+                if err != nil {
+                        return nil, err
+                }
+        }
+        if err := v.err(); err != nil {
+                return nil, err
+        }
+
+        if err := r.DB.Save(list).Error; err != nil {
+                return nil, fmt.Errorf("failed to update smart list: %w", err)
+        }
+
+        return list, nil
+}
+
+// DeleteSmartList is the resolver for the deleteSmartList field.
+func (r *mutationResolver) DeleteSmartList(ctx context.Context, id string, userID string) (bool, error) {
+        listID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid smart list ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var lists []models.SmartList
+        if err := r.DB.Where("id = ? AND user_id = ?", listID, uid).Limit(1).Find(&lists).Error; err != nil {
+                return false, fmt.Errorf("failed to fetch smart list: %w", err)
+        }
+        if len(lists) == 0 {
+                return false, nil
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entitySmartList, lists[0].ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// PinGroup is the resolver for the pinGroup field.
+func (r *mutationResolver) PinGroup(ctx context.Context, id string, userID string, position *int) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return pinEntity(tx, uint(uid), entityGroup, group.ID, position)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// UnpinGroup is the resolver for the unpinGroup field.
+func (r *mutationResolver) UnpinGroup(ctx context.Context, id string, userID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return unpinEntity(tx, uint(uid), entityGroup, group.ID)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// PinTodo is the resolver for the pinTodo field.
+func (r *mutationResolver) PinTodo(ctx context.Context, id string, userID string, position *int) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return pinEntity(tx, uint(uid), entityTodo, todo.ID, position)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// UnpinTodo is the resolver for the unpinTodo field.
+func (r *mutationResolver) UnpinTodo(ctx context.Context, id string, userID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return unpinEntity(tx, uint(uid), entityTodo, todo.ID)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// InviteToGroup is the resolver for the inviteToGroup field.
+func (r *mutationResolver) InviteToGroup(ctx context.Context, groupID string, userID string, email string, role model.GroupRole) (*models.GroupInvitation, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        email = normalizeEmail(email)
+        if !strings.Contains(email, "@") {
+                return nil, fmt.Errorf("%w: invalid email address", ErrInvalidInput)
+        }
+        if role == model.GroupRoleOwner {
+                return nil, fmt.Errorf("%w: invitations grant viewer or editor access", ErrInvalidInput)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        var workspace models.Workspace
+        if err := r.DB.First(&workspace, group.WorkspaceID).Error; err != nil {
+                return nil, fmt.Errorf("workspace not found: %w", err)
+        }
+        if !workspace.AllowsEmail(email) {
+                return nil, fmt.Errorf("%w: %s is not allowed to join this workspace", ErrInvalidInput, email)
+        }
+
+        var members int64
+        err = r.DB.Model(&models.GroupMember{}).
+                Joins("JOIN users ON users.id = group_members.user_id").
+                Where("group_members.group_id = ? AND LOWER(users.email) = ?", group.ID, email).
+                Count(&members).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to check membership: %w", err)
+        }
+        if members > 0 {
+                return nil, fmt.Errorf("%w: %s is already a member", ErrInvalidInput, email)
+        }
+
+        // Inviting the same address again updates and resends the pending
+        // invitation instead of adding another one.
+        var invitation models.GroupInvitation
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                err := tx.Where("group_id = ? AND email = ? AND status = ?", group.ID, email, models.InvitationPending).First(&invitation).Error
+                if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+                        return fmt.Errorf("failed to fetch invitation: %w", err)
+                }
+                invitation.GroupID = group.ID
+                invitation.Email = email
+                invitation.Role = groupRoleFromModel(role)
+                invitation.InvitedBy = uint(uid)
+                invitation.Status = models.InvitationPending
+                invitation.ExpiresAt = time.Now().Add(invitationLifetime)
+                if err := tx.Save(&invitation).Error; err != nil {
+                        return fmt.Errorf("failed to save invitation: %w", err)
+                }
+                return scheduler.ScheduleInvitation(tx, &invitation)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return &invitation, nil
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, id string, userID string) (bool, error) {
+        invitationID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid invitation ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var invitation models.GroupInvitation
+        if err := r.DB.Where("id = ? AND status = ?", invitationID, models.InvitationPending).First(&invitation).Error; err != nil {
+                return false, fmt.Errorf("invitation not found: %w", err)
+        }
+        if _, err := groupAccess(r.DB, invitation.GroupID, uint(uid), models.RoleOwner); err != nil {
+                return false, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityInvitation, invitation.ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// AcceptInvitation is the resolver for the acceptInvitation field.
+func (r *mutationResolver) AcceptInvitation(ctx context.Context, id string, userID string) (*models.GroupMember, error) {
+        invitationID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid invitation ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var member *models.GroupMember
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                invitation, err := pendingInvitation(tx, uint(invitationID), uint(uid))
+                if err != nil {
+                        return err
+                }
+                // The group may be in another workspace, which the
+                // invitee joins along with it.
+                var group models.Group
+                if err := models.AllWorkspaces(tx).First(&group, invitation.GroupID).Error; err != nil {
+                        return fmt.Errorf("group not found: %w", err)
+                }
+                if err := joinWorkspace(tx, group.WorkspaceID, uint(uid)); err != nil {
+                        return err
+                }
+
+                // An invitation never lowers the role of someone who already joined.
+                role, err := groupRole(tx, group.ID, uint(uid))
+                if err != nil {
+                        return err
+                }
+                if roleRank[role] < roleRank[invitation.Role] {
+                        role = invitation.Role
+                }
+                if member, err = addGroupMember(tx, group.ID, uint(uid), role); err != nil {
+                        return err
+                }
+
+                now := time.Now()
+                invitation.Status = models.InvitationAccepted
+                invitation.RespondedAt = &now
+                if err := tx.Save(invitation).Error; err != nil {
+                        return fmt.Errorf("failed to accept invitation: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return member, nil
+}
+
+// DeclineInvitation is the resolver for the declineInvitation field.
+func (r *mutationResolver) DeclineInvitation(ctx context.Context, id string, userID string) (*models.GroupInvitation, error) {
+        invitationID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid invitation ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        invitation, err := pendingInvitation(r.DB, uint(invitationID), uint(uid))
+        if err != nil {
+                return nil, err
+        }
+
+        now := time.Now()
+        invitation.Status = models.InvitationDeclined
+        invitation.RespondedAt = &now
+        if err := r.DB.Save(invitation).Error; err != nil {
+                return nil, fmt.Errorf("failed to decline invitation: %w", err)
+        }
+
+        return invitation, nil
+}
+
+// UpdateGroupMember is the resolver for the updateGroupMember field.
+func (r *mutationResolver) UpdateGroupMember(ctx context.Context, groupID string, memberID string, userID string, role model.GroupRole) (*models.GroupMember, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        mid, err := strconv.ParseUint(memberID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid member ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        if role == model.GroupRoleOwner {
+                return nil, fmt.Errorf("%w: members can be viewers or editors", ErrInvalidInput)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        var member models.GroupMember
+        if err := r.DB.Where("group_id = ? AND user_id = ?", group.ID, mid).First(&member).Error; err != nil {
+                return nil, fmt.Errorf("member not found: %w", err)
+        }
+        if member.Role == models.RoleOwner {
+                return nil, fmt.Errorf("%w: the owner's role cannot be changed", ErrInvalidInput)
+        }
+
+        member.Role = groupRoleFromModel(role)
+        if err := r.DB.Save(&member).Error; err != nil {
+                return nil, fmt.Errorf("failed to update member: %w", err)
+        }
+
+        return &member, nil
+}
+
+// RemoveGroupMember is the resolver for the removeGroupMember field.
+func (r *mutationResolver) RemoveGroupMember(ctx context.Context, groupID string, memberID string, userID string) (bool, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        mid, err := strconv.ParseUint(memberID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid member ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        // Members may leave on their own; removing someone else takes the owner.
+        minRole := models.RoleOwner
+        if mid == uid {
+                minRole = models.RoleViewer
+        }
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), minRole)
+        if err != nil {
+                return false, err
+        }
+
+        var member models.GroupMember
+        if err := r.DB.Where("group_id = ? AND user_id = ?", group.ID, mid).First(&member).Error; err != nil {
+                return false, fmt.Errorf("member not found: %w", err)
+        }
+        if member.Role == models.RoleOwner {
+                return false, fmt.Errorf("%w: the owner cannot leave the group", ErrInvalidInput)
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := deleteRecord(tx, entityGroupMember, member.ID, uint(uid)); err != nil {
+                        return err
+                }
+                return unassignMember(tx, group.ID, member.UserID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// TransferGroup is the resolver for the transferGroup field.
+func (r *mutationResolver) TransferGroup(ctx context.Context, id string, userID string, newOwnerID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        newOwner, err := strconv.ParseUint(newOwnerID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid new owner ID: %w", err)
+        }
+
+        var group models.Group
+        if err := r.DB.First(&group, groupID).Error; err != nil {
+                return nil, fmt.Errorf("group not found: %w", err)
+        }
+        if err := canTransferGroup(r.DB, &group, uint(uid)); err != nil {
+                return nil, err
+        }
+
+        var owner models.User
+        if err := r.DB.First(&owner, newOwner).Error; err != nil {
+                return nil, fmt.Errorf("%w: new owner not found", ErrInvalidInput)
+        }
+        role, err := workspaceRole(r.DB, group.WorkspaceID, owner.ID)
+        if err != nil {
+                return nil, err
+        }
+        if role == "" {
+                return nil, fmt.Errorf("%w: the new owner must be a member of the group's workspace", ErrInvalidInput)
+        }
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return transferGroup(tx, &group, owner.ID, true, uint(uid))
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return &group, nil
+}
+
+// CreateStatus is the resolver for the createStatus field.
+func (r *mutationResolver) CreateStatus(ctx context.Context, userID string, input model.CreateStatusInput) (*models.Status, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        status := &models.Status{
+                Name:   input.Name,
+                UserID: uint(uid),
+        }
+
+        if input.GroupID != nil && *input.GroupID != "" {
+                gid, err := strconv.ParseUint(*input.GroupID, 10, 64)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid group ID: %w", err)
+                }
+                group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleEditor)
+                if err != nil {
+                        return nil, err
+                }
+                status.GroupID = &group.ID
+                status.WorkspaceID = group.WorkspaceID
+        }
+
+        if input.Color != nil {
+                status.Color = *input.Color
+        }
+        if input.IsDone != nil {
+                status.IsDone = *input.IsDone
+        }
+        if input.Position != nil {
+                status.Position = *input.Position
+        } else {
+                var last models.Status
+                query := r.DB.Where("user_id = ? AND group_id IS NULL", uid)
+                if status.GroupID != nil {
+                        query = r.DB.Where("group_id = ?", *status.GroupID)
+                }
+                if err := query.Order("position DESC").First(&last).Error; err == nil {
+                        status.Position = last.Position + 1
+                }
+        }
+
+        if err := r.DB.Create(status).Error; err != nil {
+                return nil, fmt.Errorf("failed to create status: %w", err)
+        }
+
+        return status, nil
+}
+
+// UpdateStatus is the resolver for the updateStatus field.
+func (r *mutationResolver) UpdateStatus(ctx context.Context, id string, userID string, input model.UpdateStatusInput) (*models.Status, error) {
+        statusID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid status ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        status, err := statusAccess(r.DB, uint(statusID), uint(uid))
+        if err != nil {
+                return nil, err
+        }
+
+        if input.Name != nil {
+                status.Name = *input.Name
+        }
+        if input.Color != nil {
+                status.Color = *input.Color
+        }
+        if input.Position != nil {
+                status.Position = *input.Position
+        }
+        doneChanged := input.IsDone != nil && *input.IsDone != status.IsDone
+        if input.IsDone != nil {
+                status.IsDone = *input.IsDone
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Save(status).Error; err != nil {
+                        return fmt.Errorf("failed to update status: %w", err)
+                }
+                if doneChanged {
+                        // Completed is derived from the column, so flipping the flag
+                        // completes or reopens every todo in it.
+                        return resyncStatusTodos(tx, status.ID, uint(uid), func(todo *models.Todo) error {
+                                return setTodoStatus(tx, todo, status.ID)
+                        })
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return status, nil
+}
+
+// DeleteStatus is the resolver for the deleteStatus field.
+func (r *mutationResolver) DeleteStatus(ctx context.Context, id string, userID string) (bool, error) {
+        statusID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid status ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        status, err := statusAccess(r.DB, uint(statusID), uint(uid))
+        if err != nil {
+                return false, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := deleteRecord(tx, entityStatus, status.ID, uint(uid)); err != nil {
+                        return err
+                }
+
+                // The todos in the column move to the first remaining one that
+                // agrees with their completed flag, or to none.
+                if err := resyncStatusTodos(tx, status.ID, uint(uid), func(todo *models.Todo) error {
+                        todo.StatusID = nil
+                        return syncTodoStatus(tx, todo)
+                }); err != nil {
+                        return err
+                }
+                // Trashed todos are synced again when restored.
+                if err := tx.Unscoped().Model(&models.Todo{}).Where("status_id = ?", status.ID).Update("status_id", nil).Error; err != nil {
+                        return fmt.Errorf("failed to unlink todos from status: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// MoveTodo is the resolver for the moveTodo field.
+func (r *mutationResolver) MoveTodo(ctx context.Context, id string, userID string, statusID string) (*models.Todo, error) {
+        return r.UpdateTodo(ctx, id, userID, model.UpdateTodoInput{StatusID: &statusID})
+}
+
+// AddDependency is the resolver for the addDependency field.
+func (r *mutationResolver) AddDependency(ctx context.Context, todoID string, blockerID string, userID string) (*models.Dependency, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        bid, err := strconv.ParseUint(blockerID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid blocker ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var count int64
+        if err := r.DB.Model(&models.Todo{}).Where("id IN ?", []uint64{tid, bid}).Where(models.TodoAccess(uint(uid), models.RoleEditor)).Count(&count).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+        if tid == bid || count != 2 {
+                return nil, fmt.Errorf("%w: both todos must exist, differ and be editable by the user", ErrInvalidInput)
+        }
+
+        dependency := &models.Dependency{TodoID: uint(tid), BlockerID: uint(bid)}
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                // Serialize this user's dependency changes so that two concurrent
+                // additions cannot close a cycle between them.
+                if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, uid).Error; err != nil {
+                        return fmt.Errorf("user not found: %w", err)
+                }
+
+                cycle, err := createsCycle(tx, dependency.TodoID, dependency.BlockerID)
+                if err != nil {
+                        return err
+                }
+                if cycle {
+                        return ErrDependencyCycle
+                }
+
+                err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency).Error
+                if err != nil {
+                        return fmt.Errorf("failed to add dependency: %w", err)
+                }
+                return tx.Where("todo_id = ? AND blocker_id = ?", dependency.TodoID, dependency.BlockerID).First(dependency).Error
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return dependency, nil
+}
+
+// RemoveDependency is the resolver for the removeDependency field.
+func (r *mutationResolver) RemoveDependency(ctx context.Context, todoID string, blockerID string, userID string) (bool, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        bid, err := strconv.ParseUint(blockerID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid blocker ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleEditor)
+        if err != nil {
+                return false, err
+        }
+
+        var dependencies []models.Dependency
+        if err := r.DB.Where("todo_id = ? AND blocker_id = ?", todo.ID, bid).Limit(1).Find(&dependencies).Error; err != nil {
+                return false, fmt.Errorf("failed to fetch dependency: %w", err)
+        }
+        if len(dependencies) == 0 {
+                return false, nil
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityDependency, dependencies[0].ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// SnoozeTodo is the resolver for the snoozeTodo field.
+func (r *mutationResolver) SnoozeTodo(ctx context.Context, id string, userID string, until *time.Time, preset *model.SnoozePreset) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        if todo.Completed {
+                return nil, fmt.Errorf("%w: completed todos cannot be snoozed", ErrInvalidInput)
+        }
+
+        var wakeAt time.Time
+        switch {
+        case until != nil && preset != nil:
+                return nil, fmt.Errorf("%w: give either until or preset", ErrInvalidInput)
+        case until != nil:
+                wakeAt = *until
+        case preset != nil:
+                loc, err := userLocation(r.DB, uint(uid), nil)
+                if err != nil {
+                        return nil, err
+                }
+                wakeAt = snoozePresetTime(*preset, time.Now().In(loc))
+        default:
+                return nil, fmt.Errorf("%w: until or preset is required", ErrInvalidInput)
+        }
+        if !wakeAt.After(time.Now()) {
+                return nil, fmt.Errorf("%w: snooze time must be in the future", ErrInvalidInput)
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deferTodo(tx, todo, uint(uid), func(todo *models.Todo) {
+                        todo.SnoozedUntil = &wakeAt
+                        todo.Someday = false
+                })
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// UnsnoozeTodo is the resolver for the unsnoozeTodo field.
+func (r *mutationResolver) UnsnoozeTodo(ctx context.Context, id string, userID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deferTodo(tx, todo, uint(uid), func(todo *models.Todo) {
+                        todo.SnoozedUntil = nil
+                })
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// SetSomeday is the resolver for the setSomeday field.
+func (r *mutationResolver) SetSomeday(ctx context.Context, id string, userID string, someday bool) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deferTodo(tx, todo, uint(uid), func(todo *models.Todo) {
+                        todo.Someday = someday
+                        if someday {
+                                todo.SnoozedUntil = nil
+                        }
+                })
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// StartTimer is the resolver for the startTimer field.
+func (r *mutationResolver) StartTimer(ctx context.Context, todoID string, userID string, note *string) (*models.TimeEntry, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        entry := &models.TimeEntry{TodoID: todo.ID, UserID: uint(uid), WorkspaceID: todo.WorkspaceID}
+        if note != nil {
+                entry.Note = *note
+        }
+
+        // Locking the user row serialises concurrent starts; the partial unique
+        // index on running entries backs this up.
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, uid).Error; err != nil {
+                        return fmt.Errorf("user not found: %w", err)
+                }
+                now := time.Now()
+                if _, err := stopRunningTimer(tx, uint(uid), now); err != nil {
+                        return err
+                }
+                entry.StartedAt = now
+                if err := tx.Create(entry).Error; err != nil {
+                        return fmt.Errorf("failed to start timer: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return entry, nil
+}
+
+// StopTimer is the resolver for the stopTimer field.
+func (r *mutationResolver) StopTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var entry *models.TimeEntry
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                entry, err = stopRunningTimer(tx, uint(uid), time.Now())
+                return err
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return entry, nil
+}
+
+// AddTimeEntry is the resolver for the addTimeEntry field.
+func (r *mutationResolver) AddTimeEntry(ctx context.Context, todoID string, userID string, input model.TimeEntryInput) (*models.TimeEntry, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        if err := validateEntryRange(input.StartedAt, input.EndedAt); err != nil {
+                return nil, err
+        }
+
+        endedAt := input.EndedAt
+        entry := &models.TimeEntry{
+                TodoID:      todo.ID,
+                UserID:      uint(uid),
+                WorkspaceID: todo.WorkspaceID,
+                StartedAt:   input.StartedAt,
+                EndedAt:     &endedAt,
+        }
+        if input.Note != nil {
+                entry.Note = *input.Note
+        }
+        if err := r.DB.Create(entry).Error; err != nil {
+                return nil, fmt.Errorf("failed to add time entry: %w", err)
+        }
+
+        return entry, nil
+}
+
+// UpdateTimeEntry is the resolver for the updateTimeEntry field.
+func (r *mutationResolver) UpdateTimeEntry(ctx context.Context, id string, userID string, input model.UpdateTimeEntryInput) (*models.TimeEntry, error) {
+        entryID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid time entry ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var entry models.TimeEntry
+        if err := r.DB.Where("id = ? AND user_id = ?", entryID, uid).First(&entry).Error; err != nil {
+                return nil, fmt.Errorf("time entry not found: %w", err)
+        }
+
+        if input.StartedAt != nil {
+                entry.StartedAt = *input.StartedAt
+        }
+        if input.EndedAt != nil {
+                endedAt := *input.EndedAt
+                entry.EndedAt = &endedAt
+        }
+        if input.Note != nil {
+                entry.Note = *input.Note
+        }
+
+        if entry.EndedAt != nil {
+                if err := validateEntryRange(entry.StartedAt, *entry.EndedAt); err != nil {
+                        return nil, err
+                }
+        } else if entry.StartedAt.After(time.Now()) {
+                return nil, fmt.Errorf("%w: time entries cannot start in the future", ErrInvalidInput)
+        }
+
+        if err := r.DB.Save(&entry).Error; err != nil {
+                return nil, fmt.Errorf("failed to update time entry: %w", err)
+        }
+
+        return &entry, nil
+}
+
+// DeleteTimeEntry is the resolver for the deleteTimeEntry field.
+func (r *mutationResolver) DeleteTimeEntry(ctx context.Context, id string, userID string) (bool, error) {
+        entryID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid time entry ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var entries []models.TimeEntry
+        if err := r.DB.Where("id = ? AND user_id = ?", entryID, uid).Limit(1).Find(&entries).Error; err != nil {
+                return false, fmt.Errorf("failed to fetch time entry: %w", err)
+        }
+        if len(entries) == 0 {
+                return false, nil
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityTimeEntry, entries[0].ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// UpdateUserSettings is the resolver for the updateUserSettings field.
+func (r *mutationResolver) UpdateUserSettings(ctx context.Context, userID string, input model.UpdateUserSettingsInput) (*models.User, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var user models.User
+        if err := r.DB.First(&user, uid).Error; err != nil {
+                return nil, fmt.Errorf("user not found: %w", err)
+        }
+
+        if input.TimeZone != nil {
+                if _, err := time.LoadLocation(*input.TimeZone); err != nil {
+                        return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidInput, *input.TimeZone)
+                }
+                user.TimeZone = *input.TimeZone
+        }
+
+        if err := r.DB.Save(&user).Error; err != nil {
+                return nil, fmt.Errorf("failed to update user: %w", err)
+        }
+
+        return &user, nil
+}
+
+// CreateTemplate is the resolver for the createTemplate field.
+func (r *mutationResolver) CreateTemplate(ctx context.Context, userID string, input model.CreateTemplateInput) (*models.Template, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        if strings.TrimSpace(input.Name) == "" {
+                return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+        }
+
+        t := &models.Template{
+                Name:   input.Name,
+                UserID: uint(uid),
+        }
+        if input.Description != nil {
+                t.Description = *input.Description
+        }
+        if input.GroupName != nil {
+                t.GroupName = *input.GroupName
+        }
+        if input.GroupDescription != nil {
+                t.GroupDescription = *input.GroupDescription
+        }
+        if input.GroupColor != nil {
+                t.GroupColor = *input.GroupColor
+                if t.GroupColor != "" {
+                        color, ok := normalizeColor(t.GroupColor)
+                        if !ok {
+                                return nil, fmt.Errorf("%w: invalid group color %q", ErrInvalidInput, t.GroupColor)
+                        }
+                        t.GroupColor = color
+                }
+        }
+        if input.Shared != nil && *input.Shared {
+                admin, err := isWorkspaceAdmin(r.DB, uint(uid))
+                if err != nil {
+                        return nil, err
+                }
+                if !admin {
+                        return nil, fmt.Errorf("%w: only admins can share templates", ErrForbidden)
+                }
+                t.Shared = true
+        }
+        if t.Todos, err = buildTemplateTodos(input.Todos); err != nil {
+                return nil, err
+        }
+
+        if err := r.DB.Create(t).Error; err != nil {
+                return nil, fmt.Errorf("failed to create template: %w", err)
+        }
+
+        return t, nil
+}
+
+// UpdateTemplate is the resolver for the updateTemplate field.
+func (r *mutationResolver) UpdateTemplate(ctx context.Context, id string, userID string, input model.UpdateTemplateInput) (*models.Template, error) {
+        templateID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid template ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        t, err := visibleTemplate(r.DB, uint(templateID), uint(uid))
+        if err != nil {
+                return nil, err
+        }
+        if err := checkTemplateEditable(r.DB, t, uint(uid)); err != nil {
+                return nil, err
+        }
+
+        if input.Name != nil {
+                if strings.TrimSpace(*input.Name) == "" {
+                        return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+                }
+                t.Name = *input.Name
+        }
+        if input.Description != nil {
+                t.Description = *input.Description
+        }
+        if input.GroupName != nil {
+                t.GroupName = *input.GroupName
+        }
+        if input.GroupDescription != nil {
+                t.GroupDescription = *input.GroupDescription
+        }
+        if input.GroupColor != nil {
+                t.GroupColor = *input.GroupColor
+                if t.GroupColor != "" {
+                        color, ok := normalizeColor(t.GroupColor)
+                        if !ok {
+                                return nil, fmt.Errorf("%w: invalid group color %q", ErrInvalidInput, t.GroupColor)
+                        }
+                        t.GroupColor = color
+                }
+        }
+        if input.Shared != nil && *input.Shared != t.Shared {
+                admin, err := isWorkspaceAdmin(r.DB, uint(uid))
+                if err != nil {
+                        return nil, err
+                }
+                if !admin {
+                        return nil, fmt.Errorf("%w: only admins can share templates", ErrForbidden)
+                }
+                t.Shared = *input.Shared
+        }
+
+        var todos []models.TemplateTodo
+        if input.Todos != nil {
+                if todos, err = buildTemplateTodos(input.Todos); err != nil {
+                        return nil, err
+                }
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Omit("Todos").Save(t).Error; err != nil {
+                        return fmt.Errorf("failed to update template: %w", err)
+                }
+                if input.Todos == nil {
+                        return nil
+                }
+                // The todo list is replaced as a whole.
+                if err := tx.Where("template_id = ?", t.ID).Delete(&models.TemplateTodo{}).Error; err != nil {
+                        return fmt.Errorf("failed to update template todos: %w", err)
+                }
+                for i := range todos {
+                        todos[i].TemplateID = t.ID
+                }
+                if len(todos) > 0 {
+                        if err := tx.Create(&todos).Error; err != nil {
+                                return fmt.Errorf("failed to update template todos: %w", err)
+                        }
+                }
+                t.Todos = todos
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return t, nil
+}
+
+// DeleteTemplate is the resolver for the deleteTemplate field.
+func (r *mutationResolver) DeleteTemplate(ctx context.Context, id string, userID string) (bool, error) {
+        templateID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid template ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        t, err := visibleTemplate(r.DB, uint(templateID), uint(uid))
+        if err != nil {
+                return false, nil
+        }
+        if err := checkTemplateEditable(r.DB, t, uint(uid)); err != nil {
+                return false, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Where("template_id = ?", t.ID).Delete(&models.TemplateTodo{}).Error; err != nil {
+                        return fmt.Errorf("failed to delete template todos: %w", err)
+                }
+                if err := tx.Delete(t).Error; err != nil {
+                        return fmt.Errorf("failed to delete template: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// SaveGroupAsTemplate is the resolver for the saveGroupAsTemplate field.
+func (r *mutationResolver) SaveGroupAsTemplate(ctx context.Context, groupID string, userID string, name string) (*models.Template, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        if strings.TrimSpace(name) == "" {
+                return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        // Completed occurrences of recurring todos are history, not part of the
+        // list to recreate.
+        var todos []models.Todo
+        err = r.DB.Where("group_id = ? AND NOT (completed = ? AND series_id IS NOT NULL)", group.ID, true).
+                Order("due_date NULLS LAST, id").
+                Find(&todos).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+
+        t := templateFromGroup(group, todos)
+        t.Name = name
+        t.UserID = uint(uid)
+        if err := r.DB.Create(t).Error; err != nil {
+                return nil, fmt.Errorf("failed to create template: %w", err)
+        }
+
+        return t, nil
+}
+
+// InstantiateTemplate is the resolver for the instantiateTemplate field.
+func (r *mutationResolver) InstantiateTemplate(ctx context.Context, id string, userID string, input *model.InstantiateTemplateInput) (*model.TemplateInstance, error) {
+        templateID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid template ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        t, err := visibleTemplate(r.DB, uint(templateID), uint(uid))
+        if err != nil {
+                return nil, err
+        }
+
+        values := map[string]string{}
+        start := time.Now().UTC().Truncate(24 * time.Hour)
+        if input != nil {
+                for _, variable := range input.Variables {
+                        values[variable.Name] = variable.Value
+                }
+                if input.StartDate != nil {
+                        start = *input.StartDate
+                }
+        }
+        if err := checkTemplateVariables(t, values); err != nil {
+                return nil, err
+        }
+
+        var instance *model.TemplateInstance
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                instance, err = instantiateTemplate(tx, t, uint(uid), values, start)
+                return err
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return instance, nil
+}
+
+// RestoreTodo is the resolver for the restoreTodo field.
+func (r *mutationResolver) RestoreTodo(ctx context.Context, id string, userID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB.Unscoped().Where("deleted_at IS NOT NULL"), uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        before := todoSnapshot(todo)
+
+        // The todo goes back into its group only if the group was not deleted in
+        // the meantime.
+        if todo.GroupID != nil {
+                var group models.Group
+                if err := r.DB.First(&group, *todo.GroupID).Error; err != nil {
+                        todo.GroupID = nil
+                }
+        }
+        if err := syncTodoStatus(r.DB, todo); err != nil {
+                return nil, err
+        }
+
+        todo.DeletedAt = gorm.DeletedAt{}
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Unscoped().Save(todo).Error; err != nil {
+                        return fmt.Errorf("failed to restore todo: %w", err)
+                }
+                if err := recordRevision(tx, entityTodo, todo.ID, uint(uid), models.RevisionRestore, diffSnapshots(before, todoSnapshot(todo))); err != nil {
+                        return err
+                }
+                return scheduler.ScheduleTodoReminders(tx, todo)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// RestoreGroup is the resolver for the restoreGroup field.
+func (r *mutationResolver) RestoreGroup(ctx context.Context, id string, userID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB.Unscoped().Where("deleted_at IS NOT NULL"), uint(groupID), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := checkGroupName(tx, group, group.UserID, groupNameTakenMessage); err != nil {
+                        return err
+                }
+                if err := groupWriteError(tx.Unscoped().Model(group).Update("deleted_at", nil).Error, "restore group"); err != nil {
+                        return err
+                }
+                if err := tx.Unscoped().Model(&models.Status{}).Where("group_id = ?", groupID).Update("deleted_at", nil).Error; err != nil {
+                        return fmt.Errorf("failed to restore group statuses: %w", err)
+                }
+                changes, err := restoreGroupParent(tx, group)
+                if err != nil {
+                        return err
+                }
+                return recordRevision(tx, entityGroup, group.ID, uint(uid), models.RevisionRestore, changes)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        group.DeletedAt = gorm.DeletedAt{}
+        return group, nil
+}
+
+// PurgeTodo is the resolver for the purgeTodo field.
+func (r *mutationResolver) PurgeTodo(ctx context.Context, id string, userID string) (bool, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var purged int64
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                purged, err = trash.PurgeTodos(tx, uint(uid), []uint{uint(todoID)})
+                return err
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return purged > 0, nil
+}
+
+// PurgeGroup is the resolver for the purgeGroup field.
+func (r *mutationResolver) PurgeGroup(ctx context.Context, id string, userID string) (bool, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var purged int64
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                purged, err = trash.PurgeGroups(tx, uint(uid), []uint{uint(groupID)})
+                return err
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return purged > 0, nil
+}
+
+// EmptyTrash is the resolver for the emptyTrash field.
+func (r *mutationResolver) EmptyTrash(ctx context.Context, userID string) (int, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return 0, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var todoIDs, groupIDs []uint
+        if err := r.DB.Unscoped().Model(&models.Todo{}).Where("deleted_at IS NOT NULL").Where(models.TodoAccess(uint(uid), models.RoleEditor)).Pluck("id", &todoIDs).Error; err != nil {
+                return 0, fmt.Errorf("failed to fetch trashed todos: %w", err)
+        }
+        if err := r.DB.Unscoped().Model(&models.Group{}).Where("user_id = ? AND deleted_at IS NOT NULL", uid).Pluck("id", &groupIDs).Error; err != nil {
+                return 0, fmt.Errorf("failed to fetch trashed groups: %w", err)
+        }
+
+        var todos, groups int64
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if todos, err = trash.PurgeTodos(tx, uint(uid), todoIDs); err != nil {
+                        return err
+                }
+                groups, err = trash.PurgeGroups(tx, uint(uid), groupIDs)
+                return err
+        })
+        if err != nil {
+                return 0, err
+        }
+
+        return int(todos + groups), nil
+}
+
+// CreateReminder is the resolver for the createReminder field.
+func (r *mutationResolver) CreateReminder(ctx context.Context, userID string, input model.CreateReminderInput) (*models.Reminder, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todoID, err := strconv.ParseUint(input.TodoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        if (input.RemindAt == nil) == (input.OffsetMinutes == nil) {
+                return nil, fmt.Errorf("%w: exactly one of remindAt or offsetMinutes is required", ErrInvalidInput)
+        }
+        if input.OffsetMinutes != nil && todo.DueDate == nil {
+                return nil, fmt.Errorf("%w: relative reminders require a due date", ErrInvalidInput)
+        }
+
+        channel := notifier.ChannelInApp
+        if input.Channel != nil && *input.Channel != "" {
+                channel = *input.Channel
+        }
+        if !notifier.IsChannel(channel) {
+                return nil, fmt.Errorf("%w: unknown channel %q", ErrInvalidInput, channel)
+        }
+
+        reminder := &models.Reminder{
+                TodoID:        todo.ID,
+                UserID:        uint(uid),
+                WorkspaceID:   todo.WorkspaceID,
+                RemindAt:      input.RemindAt,
+                OffsetMinutes: input.OffsetMinutes,
+                Channel:       channel,
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                if err := tx.Create(reminder).Error; err != nil {
+                        return fmt.Errorf("failed to create reminder: %w", err)
+                }
+                return scheduler.ScheduleReminder(tx, reminder, todo)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return reminder, nil
+}
+
+// DeleteReminder is the resolver for the deleteReminder field.
+func (r *mutationResolver) DeleteReminder(ctx context.Context, id string, userID string) (bool, error) {
+        reminderID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid reminder ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var reminders []models.Reminder
+        if err := r.DB.Where("id = ? AND user_id = ?", reminderID, uid).Limit(1).Find(&reminders).Error; err != nil {
+                return false, fmt.Errorf("failed to fetch reminder: %w", err)
+        }
+        if len(reminders) == 0 {
+                return false, nil
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityReminder, reminders[0].ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// MarkNotificationRead is the resolver for the markNotificationRead field.
+func (r *mutationResolver) MarkNotificationRead(ctx context.Context, id string, userID string) (*models.Notification, error) {
+        notificationID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid notification ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var notification models.Notification
+        if err := r.DB.Where("id = ? AND user_id = ?", notificationID, uid).First(&notification).Error; err != nil {
+                return nil, fmt.Errorf("notification not found: %w", err)
+        }
+
+        if notification.ReadAt == nil {
+                now := time.Now()
+                notification.ReadAt = &now
+                if err := r.DB.Save(&notification).Error; err != nil {
+                        return nil, fmt.Errorf("failed to update notification: %w", err)
+                }
+        }
+
+        return &notification, nil
+}
+
+// RevertTodo is the resolver for the revertTodo field.
+func (r *mutationResolver) RevertTodo(ctx context.Context, id string, userID string, revisionID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        rid, err := strconv.ParseUint(revisionID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid revision ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        before := todoSnapshot(todo)
+        wasCompleted := todo.Completed
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                state, err := stateAt(tx, entityTodo, todo.ID, uint(rid), before)
+                if err != nil {
+                        return err
+                }
+                if err := applyTodoState(todo, state); err != nil {
+                        return err
+                }
+
+                // The group may have been deleted since; the todo then stays ungrouped.
+                if err := detachMissingGroup(tx, todo); err != nil {
+                        return err
+                }
+                if err := detachInvalidAssignee(tx, todo); err != nil {
+                        return err
+                }
+                if err := syncTodoStatus(tx, todo); err != nil {
+                        return err
+                }
+                markCompletion(todo, wasCompleted, uint(uid))
+
+                if err := tx.Save(todo).Error; err != nil {
+                        return fmt.Errorf("failed to revert todo: %w", err)
+                }
+                changes := diffSnapshots(before, todoSnapshot(todo))
+                if len(changes) == 0 {
+                        return nil
+                }
+                if err := recordRevision(tx, entityTodo, todo.ID, uint(uid), models.RevisionRevert, changes); err != nil {
+                        return err
+                }
+                if _, ok := changes["due_date"]; ok {
+                        if err := scheduler.ScheduleTodoReminders(tx, todo); err != nil {
+                                return err
+                        }
+                }
+                if !wasCompleted && todo.Completed {
+                        if _, err := createNextOccurrence(tx, todo); err != nil {
+                                return err
+                        }
+                }
+                return nil
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// RevertGroup is the resolver for the revertGroup field.
+func (r *mutationResolver) RevertGroup(ctx context.Context, id string, userID string, revisionID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        rid, err := strconv.ParseUint(revisionID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid revision ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(groupID), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+        before := groupSnapshot(group)
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                state, err := stateAt(tx, entityGroup, group.ID, uint(rid), before)
+                if err != nil {
+                        return err
+                }
+                if err := applyGroupState(group, state); err != nil {
+                        return err
+                }
+                if err := detachInvalidParent(tx, group); err != nil {
+                        return err
+                }
+                if err := validateGroup(tx, group); err != nil {
+                        return err
+                }
+
+                if err := groupWriteError(tx.Save(group).Error, "revert group"); err != nil {
+                        return err
+                }
+                changes := diffSnapshots(before, groupSnapshot(group))
+                if len(changes) == 0 {
+                        return nil
+                }
+                return recordRevision(tx, entityGroup, group.ID, uint(uid), models.RevisionRevert, changes)
+        })
+        if err != nil {
+                return nil, err
+        }
+
+        return group, nil
+}
+
+// Undo is the resolver for the undo field.
+func (r *mutationResolver) Undo(ctx context.Context, token string, userID string) (bool, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                var undo models.UndoToken
+                if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token = ? AND user_id = ?", token, uid).First(&undo).Error; err != nil {
+                        return fmt.Errorf("undo token not found: %w", err)
+                }
+                if undo.UndoneAt != nil || time.Since(undo.CreatedAt) > r.UndoWindow {
+                        return ErrUndoExpired
+                }
+
+                if err := undoOperationRevisions(tx, undo.Token, uint(uid)); err != nil {
+                        return err
+                }
+
+                now := time.Now()
+                if err := tx.Model(&undo).Update("undone_at", now).Error; err != nil {
+                        return fmt.Errorf("failed to mark undo token used: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// AddComment is the resolver for the addComment field.
+func (r *mutationResolver) AddComment(ctx context.Context, todoID string, userID string, body string) (*models.Comment, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        body = strings.TrimSpace(body)
+        if body == "" {
+                return nil, fmt.Errorf("%w: comment body is required", ErrInvalidInput)
+        }
+        if utf8.RuneCountInString(body) > maxCommentLength {
+                return nil, fmt.Errorf("%w: comment body must be at most %d characters", ErrInvalidInput, maxCommentLength)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+
+        comment := &models.Comment{
+                TodoID:      todo.ID,
+                WorkspaceID: todo.WorkspaceID,
+                AuthorID:    uint(uid),
+                Kind:        models.CommentKindComment,
+                Body:        body,
+        }
+        if err := r.DB.Create(comment).Error; err != nil {
+                return nil, fmt.Errorf("failed to create comment: %w", err)
+        }
+
+        return comment, nil
+}
+
+// UpdateComment is the resolver for the updateComment field.
+func (r *mutationResolver) UpdateComment(ctx context.Context, id string, userID string, body string) (*models.Comment, error) {
+        commentID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid comment ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        body = strings.TrimSpace(body)
+        if body == "" {
+                return nil, fmt.Errorf("%w: comment body is required", ErrInvalidInput)
+        }
+        if utf8.RuneCountInString(body) > maxCommentLength {
+                return nil, fmt.Errorf("%w: comment body must be at most %d characters", ErrInvalidInput, maxCommentLength)
+        }
+
+        var comment models.Comment
+        if err := r.DB.Where("id = ? AND author_id = ? AND kind = ?", commentID, uid, models.CommentKindComment).First(&comment).Error; err != nil {
+                return nil, fmt.Errorf("comment not found: %w", err)
+        }
+
+        comment.Body = body
+        if err := r.DB.Save(&comment).Error; err != nil {
+                return nil, fmt.Errorf("failed to update comment: %w", err)
+        }
+
+        return &comment, nil
+}
+
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id string, userID string) (bool, error) {
+        commentID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid comment ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var comments []models.Comment
+        if err := r.DB.Where("id = ? AND author_id = ? AND kind = ?", commentID, uid, models.CommentKindComment).Limit(1).Find(&comments).Error; err != nil {
+                return false, fmt.Errorf("failed to fetch comment: %w", err)
+        }
+        if len(comments) == 0 {
+                return false, nil
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityComment, comments[0].ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// UploadAttachment is the resolver for the uploadAttachment field.
+func (r *mutationResolver) UploadAttachment(ctx context.Context, todoID string, userID string, file graphql.Upload) (*models.Attachment, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        if r.Blobs == nil {
+                return nil, errBlobsNotConfigured
+        }
+        if file.Size > r.MaxAttachmentSize {
+                return nil, fmt.Errorf("%w: files may be at most %d bytes", ErrAttachmentTooLarge, r.MaxAttachmentSize)
+        }
+        if file.Size == 0 {
+                return nil, fmt.Errorf("%w: file is empty", ErrInvalidInput)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleEditor)
+        if err != nil {
+                return nil, err
+        }
+
+        contentType, err := sniffContentType(file.File)
+        if err != nil {
+                return nil, err
+        }
+
+        attachment := &models.Attachment{
+                TodoID:      todo.ID,
+                UserID:      uint(uid),
+                WorkspaceID: todo.WorkspaceID,
+                Filename:    sanitizeFilename(file.Filename),
+                ContentType: contentType,
+                Size:        file.Size,
+        }
+        if attachment.StorageKey, err = newStorageKey(attachment); err != nil {
+                return nil, err
+        }
+
+        // The blob is stored first so that no row ever points at missing
+        // contents; it is removed again if the row cannot be added.
+        if err := r.Blobs.Put(ctx, attachment.StorageKey, file.File, file.Size, contentType); err != nil {
+                return nil, err
+        }
+
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                // Serialize this user's uploads so that concurrent ones cannot both
+                // pass the quota check.
+                if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, uid).Error; err != nil {
+                        return fmt.Errorf("user not found: %w", err)
+                }
+
+                var used int64
+                // The quota is per user, across all workspaces.
+                if err := models.AllWorkspaces(tx).Model(&models.Attachment{}).Where("user_id = ?", uid).Select("COALESCE(SUM(size), 0)").Scan(&used).Error; err != nil {
+                        return fmt.Errorf("failed to check quota: %w", err)
+                }
+                if used+attachment.Size > r.AttachmentQuota {
+                        return fmt.Errorf("%w: %d of %d bytes used", ErrQuotaExceeded, used, r.AttachmentQuota)
+                }
+
+                if err := tx.Create(attachment).Error; err != nil {
+                        return fmt.Errorf("failed to create attachment: %w", err)
+                }
+                return nil
+        })
+        if err != nil {
+                if deleteErr := r.Blobs.Delete(context.Background(), attachment.StorageKey); deleteErr != nil {
+                        log.Printf("Failed to remove orphaned blob %s: %v", attachment.StorageKey, deleteErr)
+                }
+                return nil, err
+        }
+
+        return attachment, nil
+}
+
+// DeleteAttachment is the resolver for the deleteAttachment field.
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, id string, userID string) (bool, error) {
+        attachmentID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid attachment ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var attachment models.Attachment
+        if err := r.DB.First(&attachment, attachmentID).Error; err != nil {
+                return false, fmt.Errorf("attachment not found: %w", err)
+        }
+        if _, err := todoAccess(r.DB, attachment.TodoID, uint(uid), models.RoleEditor); err != nil {
+                return false, err
+        }
+
+        // The contents stay until CleanupAttachmentsHandler removes them
+        // once the deletion can no longer be undone.
+        err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+                return deleteRecord(tx, entityAttachment, attachment.ID, uint(uid))
+        })
+        if err != nil {
+                return false, err
+        }
+
+        return true, nil
+}
+
+// ID is the resolver for the id field.
+func (r *notificationResolver) ID(ctx context.Context, obj *models.Notification) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// TodoID is the resolver for the todoId field.
+func (r *notificationResolver) TodoID(ctx context.Context, obj *models.Notification) (*string, error) {
+        if obj.TodoID == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.TodoID), 10)
+        return &id, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*models.User, error) {
+        userID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var user models.User
+        if err := r.DB.Preload("Todos").First(&user, userID).Error; err != nil {
+                return nil, fmt.Errorf("user not found: %w", err)
+        }
+
+        return &user, nil
+}
+
+// UserByEmail is the resolver for the userByEmail field.
+func (r *queryResolver) UserByEmail(ctx context.Context, email string) (*models.User, error) {
+        var user models.User
+        if err := r.DB.Where("email = ?", email).First(&user).Error; err != nil {
+                return nil, fmt.Errorf("user not found: %w", err)
+        }
+
+        return &user, nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, userID string) ([]*models.User, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        if err := checkUserAdmin(r.DB, uint(uid)); err != nil {
+                return nil, err
+        }
+
+        var users []*models.User
+        if err := managedUsers(r.DB).Find(&users).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch users: %w", err)
+        }
+
+        return users, nil
+}
+
+// ManagedUser is the resolver for the managedUser field.
+func (r *queryResolver) ManagedUser(ctx context.Context, id string, userID string) (*models.User, error) {
+        targetID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        user, err := managedUser(r.DB, uint(targetID), uint(uid))
+        if err != nil {
+                return nil, err
+        }
+        if err := r.DB.Where("user_id = ?", user.ID).Find(&user.Todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch user's todos: %w", err)
+        }
+
+        return user, nil
+}
+
+// UserCount is the resolver for the userCount field.
+func (r *queryResolver) UserCount(ctx context.Context) (int, error) {
+        var count int64
+        if err := r.DB.Model(&models.User{}).Count(&count).Error; err != nil {
+                return 0, fmt.Errorf("failed to count users: %w", err)
+        }
+
+        return int(count), nil
+}
+
+// Todo is the resolver for the todo field.
+func (r *queryResolver) Todo(ctx context.Context, id string, userID string) (*models.Todo, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(todoID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        return todo, nil
+}
+
+// Todos is the resolver for the todos field.
+func (r *queryResolver) Todos(ctx context.Context, userID string, filter *model.TodoFilter, sort *model.TodoSort) ([]*models.Todo, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        query, err := applyTodoFilter(visibleTodos(r.DB, uint(uid)), filter, uint(uid))
+        if err != nil {
+                return nil, err
+        }
+        query = applyTodoSort(query.Order(models.PinnedFirst(uint(uid), entityTodo, "todos")), sort)
+
+        var todos []*models.Todo
+        if err := query.Find(&todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+
+        return todos, nil
+}
+
+// TodosByUser is the resolver for the todosByUser field.
+func (r *queryResolver) TodosByUser(ctx context.Context, userID string) ([]*models.Todo, error) {
+        return r.Todos(ctx, userID, nil, nil)
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, userID string, query string, limit *int) ([]*model.SearchResult, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        n := 20
+        if limit != nil {
+                n = *limit
+        }
+        if n < 1 || n > 100 {
+                return nil, fmt.Errorf("%w: limit must be between 1 and 100", ErrInvalidInput)
+        }
+
+        hits, err := search.Todos(r.DB, uint(uid), query, n, nil)
+        if err != nil {
+                return nil, err
+        }
+
+        results := make([]*model.SearchResult, len(hits))
+        for i := range hits {
+                results[i] = &model.SearchResult{
+                        Todo:    &hits[i].Todo,
+                        Rank:    hits[i].Rank,
+                        Title:   hits[i].Title,
+                        Snippet: hits[i].Snippet,
+                }
+        }
+        return results, nil
+}
+
+// Workspaces is the resolver for the workspaces field.
+func (r *queryResolver) Workspaces(ctx context.Context, userID string) ([]*models.Workspace, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var workspaces []*models.Workspace
+        err = r.DB.Where("id IN (?)", r.DB.Model(&models.WorkspaceMember{}).Where("user_id = ?", uid).Select("workspace_id")).
+                Order("name, id").
+                Find(&workspaces).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch workspaces: %w", err)
+        }
+
+        return workspaces, nil
+}
+
+// Workspace is the resolver for the workspace field.
+func (r *queryResolver) Workspace(ctx context.Context, id string, userID string) (*models.Workspace, error) {
+        wid, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return workspaceAccess(r.DB, uint(wid), uint(uid), false)
+}
+
+// WorkspaceMembers is the resolver for the workspaceMembers field.
+func (r *queryResolver) WorkspaceMembers(ctx context.Context, workspaceID string, userID string) ([]*models.WorkspaceMember, error) {
+        wid, err := strconv.ParseUint(workspaceID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid workspace ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        workspace, err := workspaceAccess(r.DB, uint(wid), uint(uid), false)
+        if err != nil {
+                return nil, err
+        }
+
+        return r.Resolver.Workspace().Members(ctx, workspace)
+}
+
+// Group is the resolver for the group field.
+func (r *queryResolver) Group(ctx context.Context, id string, userID string) (*models.Group, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return groupAccess(r.DB, uint(groupID), uint(uid), models.RoleViewer)
+}
+
+// Groups is the resolver for the groups field.
+func (r *queryResolver) Groups(ctx context.Context, userID string, view *model.GroupView) ([]*models.Group, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        groupView := model.GroupViewActive
+        if view != nil {
+                groupView = *view
+        }
+
+        var groups []*models.Group
+        query := applyGroupView(visibleGroups(r.DB, uint(uid)), groupView).
+                Order(models.PinnedFirst(uint(uid), entityGroup, "groups")).
+                Order("groups.id")
+        if err := query.Find(&groups).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch groups: %w", err)
+        }
+
+        return groups, nil
+}
+
+// SmartLists is the resolver for the smartLists field.
+func (r *queryResolver) SmartLists(ctx context.Context, userID string) ([]*models.SmartList, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var lists []*models.SmartList
+        if err := r.DB.Where("user_id = ?", uid).Order("LOWER(name)").Order("id").Find(&lists).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch smart lists: %w", err)
+        }
+
+        return lists, nil
+}
+
+// Pins is the resolver for the pins field.
+func (r *queryResolver) Pins(ctx context.Context, userID string) (*model.Pins, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        pins := &model.Pins{}
+        err = visibleGroups(r.DB, uint(uid)).
+                Where("groups.id IN (SELECT entity_id FROM pins WHERE user_id = ? AND entity_type = ?)", uid, entityGroup).
+                Order(models.PinnedFirst(uint(uid), entityGroup, "groups")).
+                Find(&pins.Groups).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch pinned groups: %w", err)
+        }
+        err = visibleTodos(r.DB, uint(uid)).
+                Where("todos.id IN (SELECT entity_id FROM pins WHERE user_id = ? AND entity_type = ?)", uid, entityTodo).
+                Order(models.PinnedFirst(uint(uid), entityTodo, "todos")).
+                Find(&pins.Todos).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch pinned todos: %w", err)
+        }
+
+        return pins, nil
+}
+
+// SmartList is the resolver for the smartList field.
+func (r *queryResolver) SmartList(ctx context.Context, id string, userID string) (*models.SmartList, error) {
+        listID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid smart list ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return ownSmartList(r.DB, uint(listID), uint(uid))
+}
+
+// GroupMembers is the resolver for the groupMembers field.
+func (r *queryResolver) GroupMembers(ctx context.Context, groupID string, userID string) ([]*models.GroupMember, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        return r.Resolver.Group().Members(ctx, group)
+}
+
+// GroupInvitations is the resolver for the groupInvitations field.
+func (r *queryResolver) GroupInvitations(ctx context.Context, groupID string, userID string) ([]*models.GroupInvitation, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleOwner)
+        if err != nil {
+                return nil, err
+        }
+
+        var invitations []*models.GroupInvitation
+        if err := r.DB.Where("group_id = ? AND status = ?", group.ID, models.InvitationPending).Order("created_at DESC").Find(&invitations).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch invitations: %w", err)
+        }
+
+        return invitations, nil
+}
+
+// Invitations is the resolver for the invitations field.
+func (r *queryResolver) Invitations(ctx context.Context, userID string) ([]*models.GroupInvitation, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var user models.User
+        if err := r.DB.First(&user, uid).Error; err != nil {
+                return nil, fmt.Errorf("user not found: %w", err)
+        }
+
+        var invitations []*models.GroupInvitation
+        err = r.DB.Where("email = ? AND status = ? AND expires_at > ?", normalizeEmail(user.Email), models.InvitationPending, time.Now()).
+                Where("group_id IN (?)", models.AllWorkspaces(r.DB).Model(&models.Group{}).Select("id")).
+                Order("created_at DESC").
+                Find(&invitations).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch invitations: %w", err)
+        }
+
+        return invitations, nil
+}
+
+// DependencyGraph is the resolver for the dependencyGraph field.
+func (r *queryResolver) DependencyGraph(ctx context.Context, groupID string, userID string) (*model.DependencyGraph, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        var ids []uint
+        if err := r.DB.Model(&models.Todo{}).Where("group_id = ?", group.ID).Pluck("id", &ids).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+
+        result := &model.DependencyGraph{Nodes: []*models.Todo{}, Edges: []*models.Dependency{}}
+        if len(ids) == 0 {
+                return result, nil
+        }
+
+        // Edges that cross the group boundary are included together with the
+        // todos outside the group they lead to.
+        if err := r.DB.Where("todo_id IN ? OR blocker_id IN ?", ids, ids).Order("id").Find(&result.Edges).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch dependencies: %w", err)
+        }
+        nodeIDs := append([]uint{}, ids...)
+        for _, edge := range result.Edges {
+                nodeIDs = append(nodeIDs, edge.TodoID, edge.BlockerID)
+        }
+        if err := visibleTodos(r.DB, uint(uid)).Where("id IN ?", nodeIDs).Order("id").Find(&result.Nodes).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+
+        return result, nil
+}
+
+// TimeEntries is the resolver for the timeEntries field.
+func (r *queryResolver) TimeEntries(ctx context.Context, userID string, todoID *string, from *time.Time, to *time.Time) ([]*models.TimeEntry, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        query := r.DB.Where("user_id = ? AND todo_id IN (?)", uid, r.DB.Unscoped().Model(&models.Todo{}).Select("id"))
+        if todoID != nil && *todoID != "" {
+                tid, err := strconv.ParseUint(*todoID, 10, 64)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid todo ID: %w", err)
+                }
+                query = query.Where("todo_id = ?", tid)
+        }
+        if from != nil {
+                query = query.Where("started_at >= ?", *from)
+        }
+        if to != nil {
+                query = query.Where("started_at < ?", *to)
+        }
+
+        var entries []*models.TimeEntry
+        if err := query.Order("started_at, id").Find(&entries).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch time entries: %w", err)
+        }
+
+        return entries, nil
+}
+
+// RunningTimer is the resolver for the runningTimer field.
+func (r *queryResolver) RunningTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var entries []*models.TimeEntry
+        if err := r.DB.Where("user_id = ? AND ended_at IS NULL", uid).Limit(1).Find(&entries).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch running timer: %w", err)
+        }
+        if len(entries) == 0 {
+                return nil, nil
+        }
+
+        return entries[0], nil
+}
+
+// Templates is the resolver for the templates field.
+func (r *queryResolver) Templates(ctx context.Context, userID string) ([]*models.Template, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var templates []*models.Template
+        err = r.DB.Preload("Todos", orderedTemplateTodos).
+                Where("user_id = ? OR shared = ?", uid, true).
+                Order("name, id").
+                Find(&templates).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch templates: %w", err)
+        }
+
+        return templates, nil
+}
+
+// ParseQuickAdd is the resolver for the parseQuickAdd field.
+func (r *queryResolver) ParseQuickAdd(ctx context.Context, userID string, text string, timeZone *string) (*model.QuickAddParse, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return parseQuickAdd(r.DB, uint(uid), text, timeZone)
+}
+
+// Template is the resolver for the template field.
+func (r *queryResolver) Template(ctx context.Context, id string, userID string) (*models.Template, error) {
+        templateID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid template ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return visibleTemplate(r.DB, uint(templateID), uint(uid))
+}
+
+// GroupTime is the resolver for the groupTime field.
+func (r *queryResolver) GroupTime(ctx context.Context, groupID string, userID string) (*model.GroupTime, error) {
+        gid, err := strconv.ParseUint(groupID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        var todos []*models.Todo
+        if err := r.DB.Where("group_id = ?", group.ID).Order("id").Find(&todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+        }
+
+        ids := make([]uint, len(todos))
+        for i, todo := range todos {
+                ids[i] = todo.ID
+        }
+        totals, err := trackedSeconds(r.DB, ids, time.Now())
+        if err != nil {
+                return nil, err
+        }
+
+        result := &model.GroupTime{GroupID: groupID, Todos: make([]*model.TodoTime, len(todos))}
+        for i, todo := range todos {
+                result.Todos[i] = &model.TodoTime{
+                        Todo:            todo,
+                        TrackedSeconds:  totals[todo.ID],
+                        EstimateMinutes: todo.EstimateMinutes,
+                }
+                result.TrackedSeconds += totals[todo.ID]
+                if todo.EstimateMinutes != nil {
+                        result.EstimateMinutes += *todo.EstimateMinutes
+                }
+        }
+
+        return result, nil
+}
+
+// Statuses is the resolver for the statuses field.
+func (r *queryResolver) Statuses(ctx context.Context, userID string, groupID *string) ([]*models.Status, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        query := r.DB.Where("user_id = ? AND group_id IS NULL", uid)
+        if groupID != nil && *groupID != "" {
+                gid, err := strconv.ParseUint(*groupID, 10, 64)
+                if err != nil {
+                        return nil, fmt.Errorf("invalid group ID: %w", err)
+                }
+                if _, err := groupAccess(r.DB, uint(gid), uint(uid), models.RoleViewer); err != nil {
+                        return nil, err
+                }
+                query = r.DB.Where("group_id = ?", gid)
+        }
+
+        var statuses []*models.Status
+        if err := query.Order("position, id").Find(&statuses).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch statuses: %w", err)
+        }
+
+        return statuses, nil
+}
+
+// Trash is the resolver for the trash field.
+func (r *queryResolver) Trash(ctx context.Context, userID string) (*model.Trash, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        result := &model.Trash{}
+        if err := r.DB.Unscoped().Where("deleted_at IS NOT NULL").Where(models.TodoAccess(uint(uid), models.RoleEditor)).Order("deleted_at DESC").Find(&result.Todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch trashed todos: %w", err)
+        }
+        if err := r.DB.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", uid).Order("deleted_at DESC").Find(&result.Groups).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch trashed groups: %w", err)
+        }
+
+        return result, nil
+}
+
+// Reminders is the resolver for the reminders field.
+func (r *queryResolver) Reminders(ctx context.Context, todoID string, userID string) ([]*models.Reminder, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        var reminders []*models.Reminder
+        if err := r.DB.Where("todo_id = ? AND user_id = ?", tid, uid).Order("created_at").Find(&reminders).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch reminders: %w", err)
+        }
+
+        return reminders, nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *queryResolver) Attachments(ctx context.Context, todoID string, userID string) ([]*models.Attachment, error) {
+        tid, err := strconv.ParseUint(todoID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB, uint(tid), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        var attachments []*models.Attachment
+        if err := r.DB.Where("todo_id = ?", todo.ID).Order("created_at, id").Find(&attachments).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch attachments: %w", err)
+        }
+
+        return attachments, nil
+}
+
+// Notifications is the resolver for the notifications field.
+func (r *queryResolver) Notifications(ctx context.Context, userID string, unreadOnly *bool) ([]*models.Notification, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        query := r.DB.Where("user_id = ?", uid)
+        if unreadOnly != nil && *unreadOnly {
+                query = query.Where("read_at IS NULL")
+        }
+
+        var notifications []*models.Notification
+        if err := query.Order("created_at DESC").Find(&notifications).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+        }
+
+        return notifications, nil
+}
+
+// TodoHistory is the resolver for the todoHistory field.
+func (r *queryResolver) TodoHistory(ctx context.Context, id string, userID string) ([]*models.Revision, error) {
+        todoID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid todo ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        todo, err := todoAccess(r.DB.Unscoped(), uint(todoID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        var revisions []*models.Revision
+        if err := r.DB.Where("entity_type = ? AND entity_id = ?", entityTodo, todo.ID).Order("id DESC").Find(&revisions).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch history: %w", err)
+        }
+
+        return revisions, nil
+}
+
+// GroupHistory is the resolver for the groupHistory field.
+func (r *queryResolver) GroupHistory(ctx context.Context, id string, userID string) ([]*models.Revision, error) {
+        groupID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid group ID: %w", err)
+        }
+
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        group, err := groupAccess(r.DB.Unscoped(), uint(groupID), uint(uid), models.RoleViewer)
+        if err != nil {
+                return nil, err
+        }
+
+        var revisions []*models.Revision
+        if err := r.DB.Where("entity_type = ? AND entity_id = ?", entityGroup, group.ID).Order("id DESC").Find(&revisions).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch history: %w", err)
+        }
+
+        return revisions, nil
+}
+
+// ID is the resolver for the id field.
+func (r *reminderResolver) ID(ctx context.Context, obj *models.Reminder) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// TodoID is the resolver for the todoId field.
+func (r *reminderResolver) TodoID(ctx context.Context, obj *models.Reminder) (string, error) {
+        return strconv.FormatUint(uint64(obj.TodoID), 10), nil
+}
+
+// FireAt is the resolver for the fireAt field.
+func (r *reminderResolver) FireAt(ctx context.Context, obj *models.Reminder) (*time.Time, error) {
+        var todo models.Todo
+        if err := r.DB.First(&todo, obj.TodoID).Error; err != nil {
+                return nil, nil
+        }
+        return obj.FireAt(&todo), nil
+}
+
+// ID is the resolver for the id field.
+func (r *revisionResolver) ID(ctx context.Context, obj *models.Revision) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// EntityID is the resolver for the entityId field.
+func (r *revisionResolver) EntityID(ctx context.Context, obj *models.Revision) (string, error) {
+        return strconv.FormatUint(uint64(obj.EntityID), 10), nil
+}
 
-        return result.RowsAffected > 0, nil
+// ActorID is the resolver for the actorId field.
+func (r *revisionResolver) ActorID(ctx context.Context, obj *models.Revision) (string, error) {
+        return strconv.FormatUint(uint64(obj.ActorID), 10), nil
 }
 
-// User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*models.User, error) {
-        userID, err := strconv.ParseUint(id, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
+// Changes is the resolver for the changes field.
+func (r *revisionResolver) Changes(ctx context.Context, obj *models.Revision) ([]*model.RevisionChange, error) {
+        fields := make([]string, 0, len(obj.Changes))
+        for field := range obj.Changes {
+                fields = append(fields, field)
         }
+        sort.Strings(fields)
 
-        var user models.User
-        if err := r.DB.Preload("Todos").First(&user, userID).Error; err != nil {
-                return nil, fmt.Errorf("user not found: %w", err)
+        changes := make([]*model.RevisionChange, len(fields))
+        for i, field := range fields {
+                change := obj.Changes[field]
+                changes[i] = &model.RevisionChange{Field: field, Before: change.Before, After: change.After}
         }
+        return changes, nil
+}
 
-        return &user, nil
+// ID is the resolver for the id field.
+func (r *smartListResolver) ID(ctx context.Context, obj *models.SmartList) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
 }
 
-// UserByEmail is the resolver for the userByEmail field.
-func (r *queryResolver) UserByEmail(ctx context.Context, email string) (*models.User, error) {
-        var user models.User
-        if err := r.DB.Where("email = ?", email).First(&user).Error; err != nil {
-                return nil, fmt.Errorf("user not found: %w", err)
-        }
+// UserID is the resolver for the userId field.
+func (r *smartListResolver) UserID(ctx context.Context, obj *models.SmartList) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
 
-        return &user, nil
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *smartListResolver) WorkspaceID(ctx context.Context, obj *models.SmartList) (string, error) {
+        return strconv.FormatUint(uint64(obj.WorkspaceID), 10), nil
 }
 
-// Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context) ([]*models.User, error) {
-        var users []*models.User
-        if err := r.DB.Find(&users).Error; err != nil {
-                return nil, fmt.Errorf("failed to fetch users: %w", err)
+// Todos is the resolver for the todos field.
+func (r *smartListResolver) Todos(ctx context.Context, obj *models.SmartList, sort *model.TodoSort) ([]*models.Todo, error) {
+        query := smartListTodos(r.DB.Model(&models.Todo{}), obj, time.Now()).
+                Order(models.PinnedFirst(obj.UserID, entityTodo, "todos"))
+        query = applyTodoSort(query, sort)
+
+        var todos []*models.Todo
+        if err := query.Find(&todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todos: %w", err)
         }
 
-        return users, nil
+        return todos, nil
 }
 
-// UserCount is the resolver for the userCount field.
-func (r *queryResolver) UserCount(ctx context.Context) (int, error) {
+// Count is the resolver for the count field.
+func (r *smartListResolver) Count(ctx context.Context, obj *models.SmartList) (int, error) {
         var count int64
-        if err := r.DB.Model(&models.User{}).Count(&count).Error; err != nil {
-                return 0, fmt.Errorf("failed to count users: %w", err)
+        if err := smartListTodos(r.DB.Model(&models.Todo{}), obj, time.Now()).Count(&count).Error; err != nil {
+                return 0, fmt.Errorf("failed to count todos: %w", err)
         }
 
         return int(count), nil
 }
 
-// Todo is the resolver for the todo field.
-func (r *queryResolver) Todo(ctx context.Context, id string, userID string) (*models.Todo, error) {
-        todoID, err := strconv.ParseUint(id, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid todo ID: %w", err)
+// MinPriority is the resolver for the minPriority field.
+func (r *smartListFilterResolver) MinPriority(ctx context.Context, obj *models.SmartListFilter) (*model.Priority, error) {
+        if obj.MinPriority == nil {
+                return nil, nil
         }
+        priority := priorityFromLevel(*obj.MinPriority)
+        return &priority, nil
+}
 
-        uid, err := strconv.ParseUint(userID, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
+// Due is the resolver for the due field.
+func (r *smartListFilterResolver) Due(ctx context.Context, obj *models.SmartListFilter) (*model.SmartListDue, error) {
+        if obj.Due == "" {
+                return nil, nil
         }
+        due := model.SmartListDue(strings.ToUpper(obj.Due))
+        return &due, nil
+}
 
-        var todo models.Todo
-        if err := r.DB.Where("id = ? AND user_id = ?", todoID, uid).First(&todo).Error; err != nil {
-                return nil, fmt.Errorf("todo not found: %w", err)
+// GroupIds is the resolver for the groupIds field.
+func (r *smartListFilterResolver) GroupIds(ctx context.Context, obj *models.SmartListFilter) ([]string, error) {
+        ids := make([]string, len(obj.GroupIDs))
+        for i, id := range obj.GroupIDs {
+                ids[i] = strconv.FormatUint(uint64(id), 10)
         }
+        return ids, nil
+}
 
-        return &todo, nil
+// ID is the resolver for the id field.
+func (r *statusResolver) ID(ctx context.Context, obj *models.Status) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
 }
 
-// Todos is the resolver for the todos field.
-func (r *queryResolver) Todos(ctx context.Context, userID string) ([]*models.Todo, error) {
-        uid, err := strconv.ParseUint(userID, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
-        }
+// UserID is the resolver for the userId field.
+func (r *statusResolver) UserID(ctx context.Context, obj *models.Status) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
 
-        var todos []*models.Todo
-        if err := r.DB.Where("user_id = ?", uid).Find(&todos).Error; err != nil {
-                return nil, fmt.Errorf("failed to fetch todos: %w", err)
+// GroupID is the resolver for the groupId field.
+func (r *statusResolver) GroupID(ctx context.Context, obj *models.Status) (*string, error) {
+        if obj.GroupID == nil {
+                return nil, nil
         }
+        id := strconv.FormatUint(uint64(*obj.GroupID), 10)
+        return &id, nil
+}
 
-        return todos, nil
+// ID is the resolver for the id field.
+func (r *templateResolver) ID(ctx context.Context, obj *models.Template) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
 }
 
-// TodosByUser is the resolver for the todosByUser field.
-func (r *queryResolver) TodosByUser(ctx context.Context, userID string) ([]*models.Todo, error) {
-        return r.Todos(ctx, userID)
+// UserID is the resolver for the userId field.
+func (r *templateResolver) UserID(ctx context.Context, obj *models.Template) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
 }
 
-// Group is the resolver for the group field.
-func (r *queryResolver) Group(ctx context.Context, id string, userID string) (*models.Group, error) {
-        groupID, err := strconv.ParseUint(id, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid group ID: %w", err)
-        }
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *templateResolver) WorkspaceID(ctx context.Context, obj *models.Template) (string, error) {
+        return strconv.FormatUint(uint64(obj.WorkspaceID), 10), nil
+}
 
-        uid, err := strconv.ParseUint(userID, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
-        }
+// Variables is the resolver for the variables field.
+func (r *templateResolver) Variables(ctx context.Context, obj *models.Template) ([]string, error) {
+        return templateVariables(obj), nil
+}
 
-        var group models.Group
-        if err := r.DB.Where("id = ? AND user_id = ?", groupID, uid).First(&group).Error; err != nil {
-                return nil, fmt.Errorf("group not found: %w", err)
+// ID is the resolver for the id field.
+func (r *templateTodoResolver) ID(ctx context.Context, obj *models.TemplateTodo) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// Checklist is the resolver for the checklist field.
+func (r *templateTodoResolver) Checklist(ctx context.Context, obj *models.TemplateTodo) ([]string, error) {
+        if obj.Checklist == nil {
+                return []string{}, nil
         }
+        return obj.Checklist, nil
+}
 
-        return &group, nil
+// ID is the resolver for the id field.
+func (r *timeEntryResolver) ID(ctx context.Context, obj *models.TimeEntry) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
 }
 
-// Groups is the resolver for the groups field.
-func (r *queryResolver) Groups(ctx context.Context, userID string) ([]*models.Group, error) {
-        uid, err := strconv.ParseUint(userID, 10, 64)
-        if err != nil {
-                return nil, fmt.Errorf("invalid user ID: %w", err)
-        }
+// TodoID is the resolver for the todoId field.
+func (r *timeEntryResolver) TodoID(ctx context.Context, obj *models.TimeEntry) (string, error) {
+        return strconv.FormatUint(uint64(obj.TodoID), 10), nil
+}
 
-        var groups []*models.Group
-        if err := r.DB.Where("user_id = ?", uid).Find(&groups).Error; err != nil {
-                return nil, fmt.Errorf("failed to fetch groups: %w", err)
-        }
+// UserID is the resolver for the userId field.
+func (r *timeEntryResolver) UserID(ctx context.Context, obj *models.TimeEntry) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
 
-        return groups, nil
+// Running is the resolver for the running field.
+func (r *timeEntryResolver) Running(ctx context.Context, obj *models.TimeEntry) (bool, error) {
+        return obj.EndedAt == nil, nil
+}
+
+// Seconds is the resolver for the seconds field.
+func (r *timeEntryResolver) Seconds(ctx context.Context, obj *models.TimeEntry) (int, error) {
+        return entrySeconds(obj, time.Now()), nil
+}
+
+// Todo is the resolver for the todo field.
+func (r *timeEntryResolver) Todo(ctx context.Context, obj *models.TimeEntry) (*models.Todo, error) {
+        var todos []*models.Todo
+        if err := r.DB.Unscoped().Where("id = ?", obj.TodoID).Limit(1).Find(&todos).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch todo: %w", err)
+        }
+        if len(todos) == 0 {
+                return nil, nil
+        }
+        return todos[0], nil
 }
 
 // ID is the resolver for the id field.
@@ -407,11 +4085,46 @@ func (r *todoResolver) ID(ctx context.Context, obj *models.Todo) (string, error)
         return strconv.FormatUint(uint64(obj.ID), 10), nil
 }
 
+// CompletedBy is the resolver for the completedBy field.
+func (r *todoResolver) CompletedBy(ctx context.Context, obj *models.Todo) (*string, error) {
+        if obj.CompletedBy == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.CompletedBy), 10)
+        return &id, nil
+}
+
+// StatusID is the resolver for the statusId field.
+func (r *todoResolver) StatusID(ctx context.Context, obj *models.Todo) (*string, error) {
+        if obj.StatusID == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.StatusID), 10)
+        return &id, nil
+}
+
+// Status is the resolver for the status field.
+func (r *todoResolver) Status(ctx context.Context, obj *models.Todo) (*models.Status, error) {
+        if obj.StatusID == nil {
+                return nil, nil
+        }
+        var status models.Status
+        if err := r.DB.First(&status, *obj.StatusID).Error; err != nil {
+                return nil, nil
+        }
+        return &status, nil
+}
+
 // UserID is the resolver for the userId field.
 func (r *todoResolver) UserID(ctx context.Context, obj *models.Todo) (string, error) {
         return strconv.FormatUint(uint64(obj.UserID), 10), nil
 }
 
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *todoResolver) WorkspaceID(ctx context.Context, obj *models.Todo) (string, error) {
+        return strconv.FormatUint(uint64(obj.WorkspaceID), 10), nil
+}
+
 // GroupID is the resolver for the groupId field.
 func (r *todoResolver) GroupID(ctx context.Context, obj *models.Todo) (*string, error) {
         if obj.GroupID == nil {
@@ -421,6 +4134,54 @@ func (r *todoResolver) GroupID(ctx context.Context, obj *models.Todo) (*string,
         return &id, nil
 }
 
+// AssigneeID is the resolver for the assigneeId field.
+func (r *todoResolver) AssigneeID(ctx context.Context, obj *models.Todo) (*string, error) {
+        if obj.AssigneeID == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.AssigneeID), 10)
+        return &id, nil
+}
+
+// Assignee is the resolver for the assignee field.
+func (r *todoResolver) Assignee(ctx context.Context, obj *models.Todo) (*models.User, error) {
+        if obj.AssigneeID == nil {
+                return nil, nil
+        }
+        var user models.User
+        if err := r.DB.First(&user, *obj.AssigneeID).Error; err != nil {
+                return nil, nil
+        }
+        return &user, nil
+}
+
+// Priority is the resolver for the priority field.
+func (r *todoResolver) Priority(ctx context.Context, obj *models.Todo) (model.Priority, error) {
+        return priorityFromLevel(obj.Priority), nil
+}
+
+// Snoozed is the resolver for the snoozed field.
+func (r *todoResolver) Snoozed(ctx context.Context, obj *models.Todo) (bool, error) {
+        return obj.SnoozedUntil != nil && obj.SnoozedUntil.After(time.Now()), nil
+}
+
+// SeriesID is the resolver for the seriesId field.
+func (r *todoResolver) SeriesID(ctx context.Context, obj *models.Todo) (*string, error) {
+        if obj.SeriesID == nil {
+                return nil, nil
+        }
+        id := strconv.FormatUint(uint64(*obj.SeriesID), 10)
+        return &id, nil
+}
+
+// DeletedAt is the resolver for the deletedAt field.
+func (r *todoResolver) DeletedAt(ctx context.Context, obj *models.Todo) (*time.Time, error) {
+        if !obj.DeletedAt.Valid {
+                return nil, nil
+        }
+        return &obj.DeletedAt.Time, nil
+}
+
 // Group is the resolver for the group field.
 func (r *todoResolver) Group(ctx context.Context, obj *models.Todo) (*models.Group, error) {
         if obj.GroupID == nil {
@@ -433,6 +4194,101 @@ func (r *todoResolver) Group(ctx context.Context, obj *models.Todo) (*models.Gro
         return &group, nil
 }
 
+// Reminders is the resolver for the reminders field.
+func (r *todoResolver) Reminders(ctx context.Context, obj *models.Todo) ([]*models.Reminder, error) {
+        var reminders []*models.Reminder
+        if err := r.DB.Where("todo_id = ?", obj.ID).Order("created_at").Find(&reminders).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch reminders: %w", err)
+        }
+        return reminders, nil
+}
+
+// Comments is the resolver for the comments field.
+func (r *todoResolver) Comments(ctx context.Context, obj *models.Todo, includeActivity *bool) ([]*models.Comment, error) {
+        query := r.DB.Where("todo_id = ?", obj.ID)
+        if includeActivity != nil && !*includeActivity {
+                query = query.Where("kind = ?", models.CommentKindComment)
+        }
+
+        var comments []*models.Comment
+        if err := query.Order("created_at, id").Find(&comments).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch comments: %w", err)
+        }
+        return comments, nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *todoResolver) Attachments(ctx context.Context, obj *models.Todo) ([]*models.Attachment, error) {
+        var attachments []*models.Attachment
+        if err := r.DB.Where("todo_id = ?", obj.ID).Order("created_at, id").Find(&attachments).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch attachments: %w", err)
+        }
+        return attachments, nil
+}
+
+// Blocked is the resolver for the blocked field.
+func (r *todoResolver) Blocked(ctx context.Context, obj *models.Todo) (bool, error) {
+        blockers, err := openBlockers(r.DB, obj.ID)
+        if err != nil {
+                return false, err
+        }
+        return len(blockers) > 0, nil
+}
+
+// BlockedBy is the resolver for the blockedBy field.
+func (r *todoResolver) BlockedBy(ctx context.Context, obj *models.Todo) ([]*models.Todo, error) {
+        var todos []*models.Todo
+        err := r.DB.Joins("JOIN dependencies ON dependencies.blocker_id = todos.id").
+                Where("dependencies.todo_id = ?", obj.ID).
+                Order("todos.id").
+                Find(&todos).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch blockers: %w", err)
+        }
+        return todos, nil
+}
+
+// Blocking is the resolver for the blocking field.
+func (r *todoResolver) Blocking(ctx context.Context, obj *models.Todo) ([]*models.Todo, error) {
+        var todos []*models.Todo
+        err := r.DB.Joins("JOIN dependencies ON dependencies.todo_id = todos.id").
+                Where("dependencies.blocker_id = ?", obj.ID).
+                Order("todos.id").
+                Find(&todos).Error
+        if err != nil {
+                return nil, fmt.Errorf("failed to fetch blocked todos: %w", err)
+        }
+        return todos, nil
+}
+
+// TrackedSeconds is the resolver for the trackedSeconds field.
+func (r *todoResolver) TrackedSeconds(ctx context.Context, obj *models.Todo) (int, error) {
+        totals, err := trackedSeconds(r.DB, []uint{obj.ID}, time.Now())
+        if err != nil {
+                return 0, err
+        }
+        return totals[obj.ID], nil
+}
+
+// TimeEntries is the resolver for the timeEntries field.
+func (r *todoResolver) TimeEntries(ctx context.Context, obj *models.Todo) ([]*models.TimeEntry, error) {
+        var entries []*models.TimeEntry
+        if err := r.DB.Where("todo_id = ?", obj.ID).Order("started_at, id").Find(&entries).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch time entries: %w", err)
+        }
+        return entries, nil
+}
+
+// Pinned is the resolver for the pinned field.
+func (r *todoResolver) Pinned(ctx context.Context, obj *models.Todo, userID string) (bool, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return false, fmt.Errorf("invalid user ID: %w", err)
+        }
+
+        return isPinned(r.DB, uint(uid), entityTodo, obj.ID)
+}
+
 // ID is the resolver for the id field.
 func (r *userResolver) ID(ctx context.Context, obj *models.User) (string, error) {
         return strconv.FormatUint(uint64(obj.ID), 10), nil
@@ -441,45 +4297,153 @@ func (r *userResolver) ID(ctx context.Context, obj *models.User) (string, error)
 // Groups is the resolver for the groups field.
 func (r *userResolver) Groups(ctx context.Context, obj *models.User) ([]*models.Group, error) {
         var groups []*models.Group
-        if err := r.DB.Where("user_id = ?", obj.ID).Find(&groups).Error; err != nil {
+        if err := visibleGroups(r.DB, obj.ID).Find(&groups).Error; err != nil {
                 return nil, fmt.Errorf("failed to fetch groups: %w", err)
         }
         return groups, nil
 }
 
+// ID is the resolver for the id field.
+func (r *workspaceResolver) ID(ctx context.Context, obj *models.Workspace) (string, error) {
+        return strconv.FormatUint(uint64(obj.ID), 10), nil
+}
+
+// AllowedEmailDomains is the resolver for the allowedEmailDomains field.
+func (r *workspaceResolver) AllowedEmailDomains(ctx context.Context, obj *models.Workspace) ([]string, error) {
+        if obj.AllowedEmailDomains == nil {
+                return []string{}, nil
+        }
+        return obj.AllowedEmailDomains, nil
+}
+
+// Role is the resolver for the role field.
+func (r *workspaceResolver) Role(ctx context.Context, obj *models.Workspace, userID string) (*model.WorkspaceRole, error) {
+        uid, err := strconv.ParseUint(userID, 10, 64)
+        if err != nil {
+                return nil, fmt.Errorf("invalid user ID: %w", err)
+        }
+        role, err := workspaceRole(r.DB, obj.ID, uint(uid))
+        if err != nil || role == "" {
+                return nil, err
+        }
+        result := workspaceRoleToModel(role)
+        return &result, nil
+}
+
+// Members is the resolver for the members field.
+func (r *workspaceResolver) Members(ctx context.Context, obj *models.Workspace) ([]*models.WorkspaceMember, error) {
+        var members []*models.WorkspaceMember
+        if err := r.DB.Where("workspace_id = ?", obj.ID).Order("created_at, id").Find(&members).Error; err != nil {
+                return nil, fmt.Errorf("failed to fetch workspace members: %w", err)
+        }
+        return members, nil
+}
+
+// WorkspaceID is the resolver for the workspaceId field.
+func (r *workspaceMemberResolver) WorkspaceID(ctx context.Context, obj *models.WorkspaceMember) (string, error) {
+        return strconv.FormatUint(uint64(obj.WorkspaceID), 10), nil
+}
+
+// UserID is the resolver for the userId field.
+func (r *workspaceMemberResolver) UserID(ctx context.Context, obj *models.WorkspaceMember) (string, error) {
+        return strconv.FormatUint(uint64(obj.UserID), 10), nil
+}
+
+// Email is the resolver for the email field.
+func (r *workspaceMemberResolver) Email(ctx context.Context, obj *models.WorkspaceMember) (string, error) {
+        var user models.User
+        if err := r.DB.Unscoped().Select("email").First(&user, obj.UserID).Error; err != nil {
+                return "", fmt.Errorf("failed to fetch member: %w", err)
+        }
+        return user.Email, nil
+}
+
+// Role is the resolver for the role field.
+func (r *workspaceMemberResolver) Role(ctx context.Context, obj *models.WorkspaceMember) (model.WorkspaceRole, error) {
+        return workspaceRoleToModel(obj.Role), nil
+}
+
+// Attachment returns AttachmentResolver implementation.
+func (r *Resolver) Attachment() AttachmentResolver { return &attachmentResolver{r} }
+
+// Comment returns CommentResolver implementation.
+func (r *Resolver) Comment() CommentResolver { return &commentResolver{r} }
+
+// Dependency returns DependencyResolver implementation.
+func (r *Resolver) Dependency() DependencyResolver { return &dependencyResolver{r} }
+
 // Group returns GroupResolver implementation.
 func (r *Resolver) Group() GroupResolver { return &groupResolver{r} }
 
+// GroupInvitation returns GroupInvitationResolver implementation.
+func (r *Resolver) GroupInvitation() GroupInvitationResolver { return &groupInvitationResolver{r} }
+
+// GroupMember returns GroupMemberResolver implementation.
+func (r *Resolver) GroupMember() GroupMemberResolver { return &groupMemberResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Notification returns NotificationResolver implementation.
+func (r *Resolver) Notification() NotificationResolver { return &notificationResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Reminder returns ReminderResolver implementation.
+func (r *Resolver) Reminder() ReminderResolver { return &reminderResolver{r} }
+
+// Revision returns RevisionResolver implementation.
+func (r *Resolver) Revision() RevisionResolver { return &revisionResolver{r} }
+
+// SmartList returns SmartListResolver implementation.
+func (r *Resolver) SmartList() SmartListResolver { return &smartListResolver{r} }
+
+// SmartListFilter returns SmartListFilterResolver implementation.
+func (r *Resolver) SmartListFilter() SmartListFilterResolver { return &smartListFilterResolver{r} }
+
+// Status returns StatusResolver implementation.
+func (r *Resolver) Status() StatusResolver { return &statusResolver{r} }
+
+// Template returns TemplateResolver implementation.
+func (r *Resolver) Template() TemplateResolver { return &templateResolver{r} }
+
+// TemplateTodo returns TemplateTodoResolver implementation.
+func (r *Resolver) TemplateTodo() TemplateTodoResolver { return &templateTodoResolver{r} }
+
+// TimeEntry returns TimeEntryResolver implementation.
+func (r *Resolver) TimeEntry() TimeEntryResolver { return &timeEntryResolver{r} }
+
 // Todo returns TodoResolver implementation.
 func (r *Resolver) Todo() TodoResolver { return &todoResolver{r} }
 
 // User returns UserResolver implementation.
 func (r *Resolver) User() UserResolver { return &userResolver{r} }
 
+// Workspace returns WorkspaceResolver implementation.
+func (r *Resolver) Workspace() WorkspaceResolver { return &workspaceResolver{r} }
+
+// WorkspaceMember returns WorkspaceMemberResolver implementation.
+func (r *Resolver) WorkspaceMember() WorkspaceMemberResolver { return &workspaceMemberResolver{r} }
+
+type attachmentResolver struct{ *Resolver }
+type commentResolver struct{ *Resolver }
+type dependencyResolver struct{ *Resolver }
 type groupResolver struct{ *Resolver }
+type groupInvitationResolver struct{ *Resolver }
+type groupMemberResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type notificationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type reminderResolver struct{ *Resolver }
+type revisionResolver struct{ *Resolver }
+type smartListResolver struct{ *Resolver }
+type smartListFilterResolver struct{ *Resolver }
+type statusResolver struct{ *Resolver }
+type templateResolver struct{ *Resolver }
+type templateTodoResolver struct{ *Resolver }
+type timeEntryResolver struct{ *Resolver }
 type todoResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
-
-// !!! WARNING !!!
-// The code below was going to be deleted when updating resolvers. It has been copied here so you have
-// one last chance to move it out of harms way if you want. There are two reasons this happens:
-//  - When renaming or deleting a resolver the old code will be put in here. You can safely delete
-//    it when you're done.
-//  - You have helper methods in this file. Move them out to keep these resolver files clean.
-/*
-        func (r *groupResolver) Todos(ctx context.Context, obj *models.Group) ([]*models.Todo, error) {
-        var todos []*models.Todo
-        if err := r.DB.Where("group_id = ?", obj.ID).Find(&todos).Error; err != nil {
-                return nil, fmt.Errorf("failed to fetch todos: %w", err)
-        }
-        return todos, nil
-}
-*/
+type workspaceResolver struct{ *Resolver }
+type workspaceMemberResolver struct{ *Resolver }
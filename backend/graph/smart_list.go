@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const maxSmartListNameLength = 100
+
+// ownSmartList loads smart list id if it belongs to userID.
+func ownSmartList(tx *gorm.DB, id, userID uint) (*models.SmartList, error) {
+	var list models.SmartList
+	if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&list).Error; err != nil {
+		return nil, fmt.Errorf("smart list not found: %w", err)
+	}
+	return &list, nil
+}
+
+// smartListFilterFromInput turns input into the stored form of a filter. The
+// groups it names must be ones userID can see.
+func smartListFilterFromInput(tx *gorm.DB, input *model.SmartListFilterInput, userID uint, v *ValidationError) (models.SmartListFilter, error) {
+	filter := models.SmartListFilter{
+		Completed:    input.Completed,
+		AssignedToMe: input.AssignedToMe,
+	}
+	if input.MinPriority != nil {
+		level := priorityLevel(*input.MinPriority)
+		filter.MinPriority = &level
+	}
+	if input.Due != nil {
+		filter.Due = strings.ToLower(string(*input.Due))
+	}
+	if input.IncludeSubgroups != nil {
+		filter.IncludeSubgroups = *input.IncludeSubgroups
+	}
+
+	seen := map[uint]bool{}
+	for _, id := range input.GroupIds {
+		groupID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			v.add("filter.groupIds", "contains an invalid group ID %q", id)
+			return filter, nil
+		}
+		if !seen[uint(groupID)] {
+			seen[uint(groupID)] = true
+			filter.GroupIDs = append(filter.GroupIDs, uint(groupID))
+		}
+	}
+	if len(filter.GroupIDs) > 0 {
+		var count int64
+		if err := visibleGroups(tx.Model(&models.Group{}), userID).Where("id IN ?", filter.GroupIDs).Count(&count).Error; err != nil {
+			return filter, fmt.Errorf("failed to check groups: %w", err)
+		}
+		if int(count) != len(filter.GroupIDs) {
+			v.add("filter.groupIds", "contains a group that does not exist")
+		}
+	}
+	return filter, nil
+}
+
+// validateSmartList normalizes list's name and checks it and its time zone.
+func validateSmartList(list *models.SmartList, v *ValidationError) {
+	list.Name = strings.TrimSpace(list.Name)
+	switch n := utf8.RuneCountInString(list.Name); {
+	case n == 0:
+		v.add("name", "is required")
+	case n > maxSmartListNameLength:
+		v.add("name", "must be at most %d characters", maxSmartListNameLength)
+	}
+	if list.TimeZone == "" {
+		list.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(list.TimeZone); err != nil {
+		v.add("timeZone", "is not a known time zone")
+	}
+}
+
+// smartListTodos narrows query to the todos list matches at now, among those
+// its owner can see. Like the default todo list it leaves out snoozed and
+// someday todos, and those in archived groups unless the list names groups.
+func smartListTodos(query *gorm.DB, list *models.SmartList, now time.Time) *gorm.DB {
+	f := list.Filter
+	query = applyTodoView(visibleTodos(query, list.UserID), model.TodoViewActive, now)
+
+	if len(f.GroupIDs) == 0 {
+		query = query.Where("(todos.group_id IS NULL OR todos.group_id NOT IN (" + archivedGroupsSQL + "))")
+	} else if f.IncludeSubgroups {
+		query = query.Where("todos.group_id IN (?)", gorm.Expr(groupSubtreeSQL, f.GroupIDs))
+	} else {
+		query = query.Where("todos.group_id IN ?", f.GroupIDs)
+	}
+	if f.Completed != nil {
+		query = query.Where("todos.completed = ?", *f.Completed)
+	}
+	if f.AssignedToMe != nil {
+		if *f.AssignedToMe {
+			query = query.Where("todos.assignee_id = ?", list.UserID)
+		} else {
+			query = query.Where("(todos.assignee_id IS NULL OR todos.assignee_id <> ?)", list.UserID)
+		}
+	}
+	if f.MinPriority != nil {
+		query = query.Where("todos.priority >= ?", *f.MinPriority)
+	}
+
+	loc, err := time.LoadLocation(list.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	switch f.Due {
+	case models.SmartListDueOverdue:
+		query = query.Where("todos.completed = ? AND todos.due_date < ?", false, now)
+	case models.SmartListDueToday:
+		query = query.Where("todos.due_date >= ? AND todos.due_date < ?", start, start.AddDate(0, 0, 1))
+	case models.SmartListDueWeek:
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		query = query.Where("todos.due_date >= ? AND todos.due_date < ?", start, start.AddDate(0, 0, 7))
+	case models.SmartListDueMonth:
+		start = start.AddDate(0, 0, 1-start.Day())
+		query = query.Where("todos.due_date >= ? AND todos.due_date < ?", start, start.AddDate(0, 1, 0))
+	case models.SmartListDueNone:
+		query = query.Where("todos.due_date IS NULL")
+	}
+	return query
+}
+
+// smartListCounts counts the todos each of lists matches at now in a single
+// query, keyed by smart list ID.
+func smartListCounts(tx *gorm.DB, lists []*models.SmartList, now time.Time) (map[uint]int, error) {
+	counts := make(map[uint]int, len(lists))
+	if len(lists) == 0 {
+		return counts, nil
+	}
+	parts := make([]string, len(lists))
+	vars := make([]interface{}, len(lists))
+	for i, list := range lists {
+		parts[i] = "?"
+		vars[i] = smartListTodos(tx.Model(&models.Todo{}), list, now).
+			Select(fmt.Sprintf("%d AS smart_list_id, COUNT(*) AS count", list.ID))
+	}
+
+	var rows []struct {
+		SmartListID uint
+		Count       int
+	}
+	if err := tx.Raw(strings.Join(parts, " UNION ALL "), vars...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.SmartListID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"testing"
+	"time"
+	"todo-app/models"
+)
+
+func TestSmartListCountsMatchCount(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	done, open := true, false
+	create(t, db,
+		&models.Todo{Title: "A", UserID: user.ID, Completed: true},
+		&models.Todo{Title: "B", UserID: user.ID},
+		&models.Todo{Title: "C", UserID: user.ID},
+	)
+	lists := []*models.SmartList{
+		{Name: "Done", UserID: user.ID, TimeZone: "UTC", Filter: models.SmartListFilter{Completed: &done}},
+		{Name: "Open", UserID: user.ID, TimeZone: "UTC", Filter: models.SmartListFilter{Completed: &open}},
+		{Name: "Undated", UserID: user.ID, TimeZone: "UTC", Filter: models.SmartListFilter{Due: models.SmartListDueNone}},
+	}
+	for _, list := range lists {
+		create(t, db, list)
+	}
+
+	counts, err := smartListCounts(db, lists, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver := &smartListResolver{NewResolver(db)}
+	for _, list := range lists {
+		want, err := resolver.Count(db.Statement.Context, list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts[list.ID] != want {
+			t.Errorf("%s: count = %d, want %d", list.Name, counts[list.ID], want)
+		}
+	}
+	if counts[lists[1].ID] != 2 {
+		t.Errorf("Open: count = %d, want 2", counts[lists[1].ID])
+	}
+}
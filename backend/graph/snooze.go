@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// laterTodayDelay is how long LATER_TODAY snoozes for.
+	laterTodayDelay = 3 * time.Hour
+	// snoozeMorningHour is the local hour TOMORROW and NEXT_WEEK wake up at.
+	snoozeMorningHour = 9
+)
+
+// snoozePresetTime resolves preset against now, whose location is the
+// user's time zone.
+func snoozePresetTime(preset model.SnoozePreset, now time.Time) time.Time {
+	y, m, d := now.Date()
+	morning := func(days int) time.Time {
+		return time.Date(y, m, d+days, snoozeMorningHour, 0, 0, 0, now.Location())
+	}
+	switch preset {
+	case model.SnoozePresetTomorrow:
+		return morning(1)
+	case model.SnoozePresetNextWeek:
+		return morning(7 - (int(now.Weekday())+6)%7)
+	default:
+		return now.Add(laterTodayDelay)
+	}
+}
+
+// deferTodo applies change to todo and saves it with a history entry.
+func deferTodo(tx *gorm.DB, todo *models.Todo, actorID uint, change func(*models.Todo)) error {
+	before := todoSnapshot(todo)
+	change(todo)
+	if err := tx.Save(todo).Error; err != nil {
+		return fmt.Errorf("failed to update todo: %w", err)
+	}
+	return recordRevision(tx, entityTodo, todo.ID, actorID, models.RevisionUpdate, diffSnapshots(before, todoSnapshot(todo)))
+}
@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+type groupStatsRow struct {
+	GroupID        uint
+	Total          int
+	Completed      int
+	Overdue        int
+	LastActivityAt *time.Time
+}
+
+// groupStats computes the todo counts of each of groups with a single
+// aggregate query. Only todos directly in a group count towards it, and its
+// last activity is the later of its own and its todos' latest update.
+func groupStats(tx *gorm.DB, groups []*models.Group) (map[uint]*model.GroupStats, error) {
+	stats := make(map[uint]*model.GroupStats, len(groups))
+	if len(groups) == 0 {
+		return stats, nil
+	}
+	ids := make([]uint, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+
+	var rows []groupStatsRow
+	err := tx.Model(&models.Todo{}).
+		Select(`group_id,
+			COUNT(*) AS total,
+			SUM(CASE WHEN completed THEN 1 ELSE 0 END) AS completed,
+			SUM(CASE WHEN NOT completed AND due_date < ? THEN 1 ELSE 0 END) AS overdue,
+			MAX(updated_at) AS last_activity_at`, time.Now()).
+		Where("group_id IN ?", ids).
+		Group("group_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute group stats: %w", err)
+	}
+	byGroup := make(map[uint]groupStatsRow, len(rows))
+	for _, row := range rows {
+		byGroup[row.GroupID] = row
+	}
+
+	for _, group := range groups {
+		row := byGroup[group.ID]
+		s := &model.GroupStats{
+			Total:          row.Total,
+			Open:           row.Total - row.Completed,
+			Completed:      row.Completed,
+			Overdue:        row.Overdue,
+			LastActivityAt: group.UpdatedAt,
+		}
+		if row.Total > 0 {
+			s.PercentComplete = float64(row.Completed) * 100 / float64(row.Total)
+		}
+		if row.LastActivityAt != nil && row.LastActivityAt.After(s.LastActivityAt) {
+			s.LastActivityAt = *row.LastActivityAt
+		}
+		stats[group.ID] = s
+	}
+	return stats, nil
+}
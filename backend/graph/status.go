@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// statusesFor returns the workflow columns that apply to a todo of userID in
+// groupID, ordered by position: the group's own columns if it defines any,
+// otherwise the user's personal columns. An empty result means the todo only
+// has the plain completed flag.
+func statusesFor(tx *gorm.DB, userID uint, groupID *uint) ([]models.Status, error) {
+	var statuses []models.Status
+	if groupID != nil {
+		if err := tx.Where("group_id = ?", *groupID).Order("position, id").Find(&statuses).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch statuses: %w", err)
+		}
+		if len(statuses) > 0 {
+			return statuses, nil
+		}
+	}
+	if err := tx.Where("user_id = ? AND group_id IS NULL", userID).Order("position, id").Find(&statuses).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+// setTodoStatus moves todo to the status with the given ID, which must be one
+// of the columns that apply to it, and derives Completed from the column.
+func setTodoStatus(tx *gorm.DB, todo *models.Todo, statusID uint) error {
+	statuses, err := statusesFor(tx, todo.UserID, todo.GroupID)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.ID == statusID {
+			id := status.ID
+			todo.StatusID = &id
+			todo.Completed = status.IsDone
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: status does not apply to this todo", ErrInvalidInput)
+}
+
+// syncTodoStatus keeps todo's status consistent after Completed or GroupID
+// changed through a path that did not pick a status explicitly. A todo whose
+// status still applies and agrees with Completed is left alone; otherwise it
+// moves to the first column whose "counts as done" flag matches.
+func syncTodoStatus(tx *gorm.DB, todo *models.Todo) error {
+	statuses, err := statusesFor(tx, todo.UserID, todo.GroupID)
+	if err != nil {
+		return err
+	}
+
+	if todo.StatusID != nil {
+		for _, status := range statuses {
+			if status.ID == *todo.StatusID && status.IsDone == todo.Completed {
+				return nil
+			}
+		}
+	}
+
+	todo.StatusID = nil
+	for _, status := range statuses {
+		if status.IsDone == todo.Completed {
+			id := status.ID
+			todo.StatusID = &id
+			break
+		}
+	}
+	return nil
+}
+
+// resyncStatusTodos applies resync to every todo in the column statusID after
+// the column changed or went away, recording each change the way an edit of
+// the todo would: with its completion stamp, a revision, and the next
+// occurrence of a series when the todo became completed.
+func resyncStatusTodos(tx *gorm.DB, statusID, actorID uint, resync func(*models.Todo) error) error {
+	var todos []models.Todo
+	if err := tx.Where("status_id = ?", statusID).Find(&todos).Error; err != nil {
+		return fmt.Errorf("failed to fetch todos: %w", err)
+	}
+	for i := range todos {
+		todo := &todos[i]
+		before := todoSnapshot(todo)
+		wasCompleted := todo.Completed
+		if err := resync(todo); err != nil {
+			return err
+		}
+		markCompletion(todo, wasCompleted, actorID)
+
+		changes := diffSnapshots(before, todoSnapshot(todo))
+		if len(changes) == 0 {
+			continue
+		}
+		if err := tx.Save(todo).Error; err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		if err := recordRevision(tx, entityTodo, todo.ID, actorID, models.RevisionUpdate, changes); err != nil {
+			return err
+		}
+		if !wasCompleted && todo.Completed {
+			if _, err := createNextOccurrence(tx, todo); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// statusAccess loads statusID for userID to change: a column of a group they
+// can edit, or one of their personal columns.
+func statusAccess(tx *gorm.DB, statusID, userID uint) (*models.Status, error) {
+	var status models.Status
+	if err := tx.First(&status, statusID).Error; err != nil {
+		return nil, fmt.Errorf("status not found: %w", err)
+	}
+	if status.GroupID != nil {
+		if _, err := groupAccess(tx, *status.GroupID, userID, models.RoleEditor); err != nil {
+			return nil, err
+		}
+	} else if status.UserID != userID {
+		return nil, fmt.Errorf("status not found: %w", gorm.ErrRecordNotFound)
+	}
+	return &status, nil
+}
@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+	"todo-app/recurrence"
+
+	"gorm.io/gorm"
+)
+
+// templateVariablePattern matches placeholders such as {{name}} or
+// {{ start_date }} in template text.
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// templateTexts returns every string of t that variables are substituted in.
+func templateTexts(t *models.Template) []string {
+	texts := []string{t.GroupName, t.GroupDescription}
+	for _, todo := range t.Todos {
+		texts = append(texts, todo.Title, todo.Description)
+		texts = append(texts, todo.Checklist...)
+	}
+	return texts
+}
+
+// templateVariables returns the sorted names of the variables used in t.
+func templateVariables(t *models.Template) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, text := range templateTexts(t) {
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkTemplateVariables fails with ErrInvalidInput unless values has a value
+// for every variable t uses.
+func checkTemplateVariables(t *models.Template, values map[string]string) error {
+	var missing []string
+	for _, name := range templateVariables(t) {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing template variables: %s", ErrInvalidInput, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func substituteVariables(text string, values map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return values[templateVariablePattern.FindStringSubmatch(placeholder)[1]]
+	})
+}
+
+// renderChecklist appends items to description as a Markdown task list.
+func renderChecklist(description string, items []string) string {
+	if len(items) == 0 {
+		return description
+	}
+	var b strings.Builder
+	b.WriteString(description)
+	if description != "" {
+		b.WriteString("\n\n")
+	}
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- [ ] ")
+		b.WriteString(item)
+	}
+	return b.String()
+}
+
+// buildTemplateTodos validates todo inputs and turns them into template
+// todos positioned in input order.
+func buildTemplateTodos(inputs []*model.TemplateTodoInput) ([]models.TemplateTodo, error) {
+	todos := make([]models.TemplateTodo, len(inputs))
+	for i, input := range inputs {
+		if strings.TrimSpace(input.Title) == "" {
+			return nil, fmt.Errorf("%w: todo %d has no title", ErrInvalidInput, i+1)
+		}
+		todo := models.TemplateTodo{
+			Position:      i,
+			Title:         input.Title,
+			DueOffsetDays: input.DueOffsetDays,
+			Checklist:     models.StringList(input.Checklist),
+		}
+		if input.Description != nil {
+			todo.Description = *input.Description
+		}
+		if input.RecurrenceRule != nil && *input.RecurrenceRule != "" {
+			rule, err := recurrence.Normalize(*input.RecurrenceRule)
+			if err != nil {
+				return nil, err
+			}
+			todo.RecurrenceRule = rule
+		}
+		todos[i] = todo
+	}
+	return todos, nil
+}
+
+func orderedTemplateTodos(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// visibleTemplate loads a template userID owns or that is shared.
+func visibleTemplate(tx *gorm.DB, id, userID uint) (*models.Template, error) {
+	var t models.Template
+	err := tx.Preload("Todos", orderedTemplateTodos).
+		Where("id = ? AND (user_id = ? OR shared = ?)", id, userID, true).
+		First(&t).Error
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+	return &t, nil
+}
+
+func isAdmin(tx *gorm.DB, userID uint) (bool, error) {
+	var user models.User
+	if err := tx.Select("id", "is_admin").First(&user, userID).Error; err != nil {
+		return false, fmt.Errorf("user not found: %w", err)
+	}
+	return user.IsAdmin, nil
+}
+
+// checkTemplateEditable allows the owner to change a template, and admins of
+// its workspace to change shared ones.
+func checkTemplateEditable(tx *gorm.DB, t *models.Template, userID uint) error {
+	if t.UserID == userID {
+		return nil
+	}
+	admin, err := workspaceAdmin(tx, t.WorkspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !admin || !t.Shared {
+		return fmt.Errorf("%w: only the owner can change this template", ErrForbidden)
+	}
+	return nil
+}
+
+// instantiateTemplate creates t's group, if it has one, and its todos for
+// userID. Due dates are offsets from start.
+func instantiateTemplate(tx *gorm.DB, t *models.Template, userID uint, values map[string]string, start time.Time) (*model.TemplateInstance, error) {
+	instance := &model.TemplateInstance{Todos: []*models.Todo{}}
+
+	var groupID *uint
+	if t.GroupName != "" {
+		group := &models.Group{
+			Name:        substituteVariables(t.GroupName, values),
+			Description: substituteVariables(t.GroupDescription, values),
+			Color:       t.GroupColor,
+			UserID:      userID,
+		}
+		if err := createGroup(tx, group); err != nil {
+			return nil, err
+		}
+		instance.Group = group
+		groupID = &group.ID
+	}
+
+	for _, item := range t.Todos {
+		checklist := make([]string, len(item.Checklist))
+		for i, entry := range item.Checklist {
+			checklist[i] = substituteVariables(entry, values)
+		}
+		todo := &models.Todo{
+			Title:          substituteVariables(item.Title, values),
+			Description:    renderChecklist(substituteVariables(item.Description, values), checklist),
+			UserID:         userID,
+			GroupID:        groupID,
+			RecurrenceRule: item.RecurrenceRule,
+		}
+		if item.DueOffsetDays != nil {
+			due := start.AddDate(0, 0, *item.DueOffsetDays)
+			todo.DueDate = &due
+		}
+		if err := syncTodoStatus(tx, todo); err != nil {
+			return nil, err
+		}
+		if err := tx.Create(todo).Error; err != nil {
+			return nil, fmt.Errorf("failed to create todo: %w", err)
+		}
+		if todo.RecurrenceRule != "" {
+			startSeries(todo)
+			if err := tx.Save(todo).Error; err != nil {
+				return nil, fmt.Errorf("failed to create todo: %w", err)
+			}
+		}
+		if err := recordRevision(tx, entityTodo, todo.ID, userID, models.RevisionCreate, creationChanges(todoSnapshot(todo))); err != nil {
+			return nil, err
+		}
+		instance.Todos = append(instance.Todos, todo)
+	}
+
+	return instance, nil
+}
+
+// templateFromGroup captures group and its todos. Due dates become offsets in
+// days from the earliest due date among the todos.
+func templateFromGroup(group *models.Group, todos []models.Todo) *models.Template {
+	t := &models.Template{
+		GroupName:        group.Name,
+		GroupDescription: group.Description,
+		GroupColor:       group.Color,
+	}
+
+	var earliest *time.Time
+	for i := range todos {
+		if due := todos[i].DueDate; due != nil && (earliest == nil || due.Before(*earliest)) {
+			earliest = due
+		}
+	}
+	startDay := func(t time.Time) time.Time {
+		y, m, d := t.UTC().Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	for i, todo := range todos {
+		item := models.TemplateTodo{
+			Position:       i,
+			Title:          todo.Title,
+			Description:    todo.Description,
+			RecurrenceRule: todo.RecurrenceRule,
+		}
+		if todo.DueDate != nil {
+			offset := int(startDay(*todo.DueDate).Sub(startDay(*earliest)).Hours() / 24)
+			item.DueOffsetDays = &offset
+		}
+		t.Todos = append(t.Todos, item)
+	}
+	return t
+}
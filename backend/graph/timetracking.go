@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// entrySeconds is the length of entry in whole seconds. A running entry is
+// measured up to now.
+func entrySeconds(entry *models.TimeEntry, now time.Time) int {
+	end := now
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	if end.Before(entry.StartedAt) {
+		return 0
+	}
+	return int(end.Sub(entry.StartedAt) / time.Second)
+}
+
+// trackedSeconds sums the time entries of each of todoIDs, counting running
+// entries up to now. Todos without entries are missing from the result.
+func trackedSeconds(tx *gorm.DB, todoIDs []uint, now time.Time) (map[uint]int, error) {
+	totals := make(map[uint]int, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return totals, nil
+	}
+	var entries []models.TimeEntry
+	err := tx.Select("todo_id", "started_at", "ended_at").
+		Where("todo_id IN ?", todoIDs).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch time entries: %w", err)
+	}
+	for i := range entries {
+		totals[entries[i].TodoID] += entrySeconds(&entries[i], now)
+	}
+	return totals, nil
+}
+
+// stopRunningTimer ends the running entry of userID at now, if there is one,
+// and returns it. A user has one running timer across all workspaces.
+func stopRunningTimer(tx *gorm.DB, userID uint, now time.Time) (*models.TimeEntry, error) {
+	tx = models.AllWorkspaces(tx)
+	var entry models.TimeEntry
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND ended_at IS NULL", userID).
+		Limit(1).
+		Find(&entry).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch running timer: %w", err)
+	}
+	if entry.ID == 0 {
+		return nil, nil
+	}
+	entry.EndedAt = &now
+	if err := tx.Save(&entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	return &entry, nil
+}
+
+// validateEntryRange checks the bounds of a finished time entry.
+func validateEntryRange(startedAt, endedAt time.Time) error {
+	if !endedAt.After(startedAt) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidInput)
+	}
+	if startedAt.After(time.Now()) {
+		return fmt.Errorf("%w: time entries cannot start in the future", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateEstimate rejects negative estimates. Zero clears the estimate.
+func validateEstimate(minutes *int) (*int, error) {
+	if minutes == nil || *minutes == 0 {
+		return nil, nil
+	}
+	if *minutes < 0 {
+		return nil, fmt.Errorf("%w: estimate must not be negative", ErrInvalidInput)
+	}
+	return minutes, nil
+}
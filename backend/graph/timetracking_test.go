@@ -0,0 +1,35 @@
+package graph
+
+import (
+	"testing"
+	"time"
+	"todo-app/models"
+)
+
+func TestTrackedSeconds(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	ann := &models.User{Email: "ann@example.com", Password: "x"}
+	bob := &models.User{Email: "bob@example.com", Password: "x"}
+	create(t, db, ann, bob)
+	tracked := &models.Todo{Title: "Tracked", UserID: ann.ID}
+	untracked := &models.Todo{Title: "Untracked", UserID: ann.ID}
+	create(t, db, tracked, untracked)
+
+	now := time.Now().Truncate(time.Second)
+	ended := now.Add(-time.Hour)
+	create(t, db,
+		&models.TimeEntry{TodoID: tracked.ID, UserID: ann.ID, StartedAt: ended.Add(-90 * time.Second), EndedAt: &ended},
+		&models.TimeEntry{TodoID: tracked.ID, UserID: bob.ID, StartedAt: now.Add(-10 * time.Minute)},
+	)
+
+	totals, err := trackedSeconds(db, []uint{tracked.ID, untracked.ID}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := totals[tracked.ID], 90+600; got != want {
+		t.Errorf("tracked seconds = %d, want %d", got, want)
+	}
+	if _, ok := totals[untracked.ID]; ok {
+		t.Errorf("todo without entries has a total of %d", totals[untracked.ID])
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// markCompletion records who completed todo and when, or clears both when
+// the todo was reopened. It must be called after every change that may flip
+// Completed, with the value Completed had before the change.
+func markCompletion(todo *models.Todo, wasCompleted bool, actorID uint) {
+	switch {
+	case todo.Completed && !wasCompleted:
+		now := time.Now()
+		todo.CompletedAt = &now
+		todo.CompletedBy = &actorID
+	case !todo.Completed && wasCompleted:
+		todo.CompletedAt = nil
+		todo.CompletedBy = nil
+	}
+}
+
+var todoSortColumns = map[model.TodoSortField]string{
+	model.TodoSortFieldCreatedAt:   "created_at",
+	model.TodoSortFieldUpdatedAt:   "updated_at",
+	model.TodoSortFieldDueDate:     "due_date",
+	model.TodoSortFieldCompletedAt: "completed_at",
+	model.TodoSortFieldPriority:    "priority",
+}
+
+// applyTodoView narrows query to the todos shown in view. Snoozes whose time
+// has passed count as over even before the resurfacing sweep clears them.
+func applyTodoView(query *gorm.DB, view model.TodoView, now time.Time) *gorm.DB {
+	switch view {
+	case model.TodoViewSnoozed:
+		return query.Where("snoozed_until > ?", now)
+	case model.TodoViewSomeday:
+		return query.Where("someday = ?", true)
+	case model.TodoViewAll:
+		return query
+	default:
+		return query.Where("(snoozed_until IS NULL OR snoozed_until <= ?) AND someday = ?", now, false)
+	}
+}
+
+// applyTodoFilter narrows query to the todos matching filter, as seen by
+// userID.
+func applyTodoFilter(query *gorm.DB, filter *model.TodoFilter, userID uint) (*gorm.DB, error) {
+	view := model.TodoViewActive
+	if filter != nil && filter.View != nil {
+		view = *filter.View
+	}
+	query = applyTodoView(query, view, time.Now())
+	// Archived groups' todos stay out of the way unless asked for by group.
+	if view == model.TodoViewActive && (filter == nil || filter.GroupID == nil) {
+		query = query.Where("(todos.group_id IS NULL OR todos.group_id NOT IN (" + archivedGroupsSQL + "))")
+	}
+	if filter == nil {
+		return query, nil
+	}
+	if filter.Completed != nil {
+		query = query.Where("completed = ?", *filter.Completed)
+	}
+	if filter.CompletedAfter != nil {
+		query = query.Where("completed_at >= ?", *filter.CompletedAfter)
+	}
+	if filter.CompletedBefore != nil {
+		query = query.Where("completed_at < ?", *filter.CompletedBefore)
+	}
+	if filter.AssignedToMe != nil {
+		if *filter.AssignedToMe {
+			query = query.Where("assignee_id = ?", userID)
+		} else {
+			query = query.Where("(assignee_id IS NULL OR assignee_id <> ?)", userID)
+		}
+	}
+	if filter.GroupID != nil {
+		gid, err := strconv.ParseUint(*filter.GroupID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group ID: %w", err)
+		}
+		if filter.IncludeSubgroups != nil && *filter.IncludeSubgroups {
+			query = query.Where("todos.group_id IN (?)", gorm.Expr(groupSubtreeSQL, []uint{uint(gid)}))
+		} else {
+			query = query.Where("todos.group_id = ?", gid)
+		}
+	}
+	return query, nil
+}
+
+func applyTodoSort(query *gorm.DB, sort *model.TodoSort) *gorm.DB {
+	if sort == nil {
+		return query
+	}
+	column, ok := todoSortColumns[sort.Field]
+	if !ok {
+		return query
+	}
+	// Todos without the date (not due, not completed) sort last either way.
+	if sort.Direction != nil && *sort.Direction == model.SortDirectionDesc {
+		return query.Order(column + " DESC NULLS LAST").Order("id DESC")
+	}
+	return query.Order(column + " ASC NULLS LAST").Order("id")
+}
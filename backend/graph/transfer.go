@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// transferGroup makes newOwnerID the owner of group and of the todos its
+// previous owner created in it, including those in the trash, as actorID.
+// Todos other members created keep their creator. The previous owner stays
+// on as an editor when keepPrevious is set and leaves the group otherwise.
+// Every change is recorded, so that undo hands the group back.
+func transferGroup(tx *gorm.DB, group *models.Group, newOwnerID uint, keepPrevious bool, actorID uint) error {
+	previousID := group.UserID
+	if previousID == newOwnerID {
+		return nil
+	}
+
+	// Trashed groups are checked when they are restored.
+	if !group.DeletedAt.Valid {
+		if err := checkGroupName(tx, group, newOwnerID, "is already used by another of the new owner's groups"); err != nil {
+			return err
+		}
+	}
+	err := updateRecord(tx, entityGroupOwner, group.ID, actorID, func() error {
+		return groupWriteError(tx.Unscoped().Model(&models.Group{}).Where("id = ?", group.ID).Update("user_id", newOwnerID).Error, "transfer group")
+	})
+	if err != nil {
+		return err
+	}
+	group.UserID = newOwnerID
+
+	var todoIDs []uint
+	if err := tx.Unscoped().Model(&models.Todo{}).Where("group_id = ? AND user_id = ?", group.ID, previousID).Pluck("id", &todoIDs).Error; err != nil {
+		return fmt.Errorf("failed to fetch group todos: %w", err)
+	}
+	if len(todoIDs) > 0 {
+		if err := tx.Unscoped().Model(&models.Todo{}).Where("id IN ?", todoIDs).Update("user_id", newOwnerID).Error; err != nil {
+			return fmt.Errorf("failed to transfer group todos: %w", err)
+		}
+	}
+	for _, todoID := range todoIDs {
+		changes := models.FieldChanges{"user_id": {Before: previousID, After: newOwnerID}}
+		if err := recordRevision(tx, entityTodoOwner, todoID, actorID, models.RevisionUpdate, changes); err != nil {
+			return err
+		}
+	}
+
+	if err := setGroupMember(tx, group.ID, newOwnerID, models.RoleOwner, actorID); err != nil {
+		return err
+	}
+	if keepPrevious {
+		return setGroupMember(tx, group.ID, previousID, models.RoleEditor, actorID)
+	}
+	var previous []models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", group.ID, previousID).Find(&previous).Error; err != nil {
+		return fmt.Errorf("failed to fetch previous owner: %w", err)
+	}
+	for _, member := range previous {
+		if err := deleteRecord(tx, entityGroupMember, member.ID, actorID); err != nil {
+			return err
+		}
+	}
+	return unassignMember(tx, group.ID, previousID, actorID)
+}
+
+// setGroupMember makes userID a member of groupID with role as actorID,
+// recording the change.
+func setGroupMember(tx *gorm.DB, groupID, userID uint, role string, actorID uint) error {
+	var members []models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Limit(1).Find(&members).Error; err != nil {
+		return fmt.Errorf("failed to check membership: %w", err)
+	}
+	if len(members) > 0 {
+		return updateRecord(tx, entityGroupMember, members[0].ID, actorID, func() error {
+			_, err := addGroupMember(tx, groupID, userID, role)
+			return err
+		})
+	}
+	member, err := addGroupMember(tx, groupID, userID, role)
+	if err != nil {
+		return err
+	}
+	return recordCreated(tx, entityGroupMember, member.ID, actorID)
+}
+
+// canTransferGroup allows the owner of group and admins of its workspace to
+// hand it over.
+func canTransferGroup(tx *gorm.DB, group *models.Group, userID uint) error {
+	role, err := groupRole(tx, group.ID, userID)
+	if err != nil {
+		return err
+	}
+	if role == models.RoleOwner {
+		return nil
+	}
+	admin, err := workspaceAdmin(tx, group.WorkspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if admin {
+		return nil
+	}
+	return checkRole(role, models.RoleOwner, "group")
+}
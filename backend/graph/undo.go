@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+	"todo-app/models"
+	"todo-app/scheduler"
+	"todo-app/trash"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// An undoOperation collects the revisions recorded while serving one request
+// under a single undo token.
+type undoOperation struct {
+	token    string
+	recorded bool
+}
+
+type undoKey struct{}
+
+// WithUndo returns a context under which the revisions recorded by a mutation
+// are grouped into one undoable operation. Resolvers must run their
+// transactions with this context for the revisions to be grouped.
+func WithUndo(ctx context.Context) context.Context {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, undoKey{}, &undoOperation{token: hex.EncodeToString(buf)})
+}
+
+// UndoToken returns the token of the operation started with WithUndo, or ""
+// when the mutation recorded nothing that can be undone.
+func UndoToken(ctx context.Context) string {
+	if op, ok := ctx.Value(undoKey{}).(*undoOperation); ok && op.recorded {
+		return op.token
+	}
+	return ""
+}
+
+// joinUndoOperation attaches revision to the operation of tx's context, if
+// any, issuing the operation's token on its first revision.
+func joinUndoOperation(tx *gorm.DB, revision *models.Revision) error {
+	op, ok := tx.Statement.Context.Value(undoKey{}).(*undoOperation)
+	if !ok {
+		return nil
+	}
+	token := &models.UndoToken{Token: op.token, UserID: revision.ActorID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to issue undo token: %w", err)
+	}
+	op.recorded = true
+	revision.Operation = op.token
+	return nil
+}
+
+type entityKey struct {
+	entityType string
+	entityID   uint
+}
+
+// undoOperationRevisions reverses the revisions of an operation, newest
+// first. It refuses when any entity the operation touched has been changed
+// by a later revision.
+func undoOperationRevisions(tx *gorm.DB, token string, userID uint) error {
+	var revisions []models.Revision
+	if err := tx.Where("operation = ?", token).Order("id DESC").Find(&revisions).Error; err != nil {
+		return fmt.Errorf("failed to fetch operation: %w", err)
+	}
+
+	latest := make(map[entityKey]uint)
+	for _, revision := range revisions {
+		key := entityKey{revision.EntityType, revision.EntityID}
+		if revision.ID > latest[key] {
+			latest[key] = revision.ID
+		}
+	}
+	for key, id := range latest {
+		var later int64
+		if err := tx.Model(&models.Revision{}).Where("entity_type = ? AND entity_id = ? AND id > ?", key.entityType, key.entityID, id).Count(&later).Error; err != nil {
+			return fmt.Errorf("failed to check history: %w", err)
+		}
+		if later > 0 {
+			return fmt.Errorf("%w: %s %d", ErrUndoConflict, key.entityType, key.entityID)
+		}
+	}
+
+	// Deleted groups come back first, then other deleted records, so that
+	// the todos they ungrouped or unassigned can rejoin them and be checked
+	// against their membership.
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return restoreOrder(&revisions[i]) < restoreOrder(&revisions[j])
+	})
+	for i := range revisions {
+		var err error
+		switch revisions[i].EntityType {
+		case entityTodo:
+			err = undoTodoRevision(tx, &revisions[i], userID)
+		case entityGroup:
+			err = undoGroupRevision(tx, &revisions[i], userID)
+		default:
+			err = undoRecordRevision(tx, &revisions[i], userID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreOrder ranks revisions by when undo has to reverse them: group
+// deletions first, then deletions of other records, then the rest.
+func restoreOrder(revision *models.Revision) int {
+	switch {
+	case revision.Action != models.RevisionDelete:
+		return 2
+	case revision.EntityType == entityGroup:
+		return 0
+	case revision.EntityType == entityTodo:
+		return 2
+	}
+	return 1
+}
+
+// undoTodoRevision puts the todo of revision back in its state before it.
+// userID must be able to edit the todo in that state, e.g. in the group that
+// deleting the group took it out of, which must have been restored first.
+func undoTodoRevision(tx *gorm.DB, revision *models.Revision, userID uint) error {
+	if revision.Action == models.RevisionCreate {
+		todo, err := todoAccess(tx.Unscoped(), revision.EntityID, userID, models.RoleEditor)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(todo).Error; err != nil {
+			return fmt.Errorf("failed to delete todo: %w", err)
+		}
+		_, err = trash.PurgeTodos(tx, userID, []uint{todo.ID})
+		return err
+	}
+
+	var todo models.Todo
+	if err := tx.Unscoped().First(&todo, revision.EntityID).Error; err != nil {
+		return fmt.Errorf("todo not found: %w", err)
+	}
+	before := todoSnapshot(&todo)
+	state := previousState(before, revision.Changes)
+	if err := applyTodoState(&todo, state); err != nil {
+		return err
+	}
+	if err := detachMissingGroup(tx, &todo); err != nil {
+		return err
+	}
+	role, err := todoRole(tx, &todo, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkRole(role, models.RoleEditor, "todo"); err != nil {
+		return err
+	}
+	if err := detachInvalidAssignee(tx, &todo); err != nil {
+		return err
+	}
+	if err := syncTodoStatus(tx, &todo); err != nil {
+		return err
+	}
+
+	action := models.RevisionRevert
+	switch revision.Action {
+	case models.RevisionDelete:
+		todo.DeletedAt = gorm.DeletedAt{}
+		action = models.RevisionRestore
+	case models.RevisionRestore:
+		todo.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		action = models.RevisionDelete
+	}
+
+	if err := tx.Unscoped().Save(&todo).Error; err != nil {
+		return fmt.Errorf("failed to update todo: %w", err)
+	}
+	if err := recordRevision(tx, entityTodo, todo.ID, userID, action, diffSnapshots(before, todoSnapshot(&todo))); err != nil {
+		return err
+	}
+	return scheduler.ScheduleTodoReminders(tx, &todo)
+}
+
+func undoGroupRevision(tx *gorm.DB, revision *models.Revision, userID uint) error {
+	group, err := groupAccess(tx.Unscoped(), revision.EntityID, userID, models.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	switch revision.Action {
+	case models.RevisionCreate:
+		if err := tx.Delete(group).Error; err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		_, err := trash.PurgeGroups(tx, userID, []uint{group.ID})
+		return err
+	case models.RevisionDelete:
+		if err := checkGroupName(tx, group, group.UserID, groupNameTakenMessage); err != nil {
+			return err
+		}
+		if err := groupWriteError(tx.Unscoped().Model(group).Update("deleted_at", nil).Error, "restore group"); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Status{}).Where("group_id = ?", group.ID).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore group statuses: %w", err)
+		}
+		changes, err := restoreGroupParent(tx, group)
+		if err != nil {
+			return err
+		}
+		return recordRevision(tx, entityGroup, group.ID, userID, models.RevisionRestore, changes)
+	case models.RevisionRestore:
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.Status{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group statuses: %w", err)
+		}
+		if err := tx.Delete(group).Error; err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		return recordRevision(tx, entityGroup, group.ID, userID, models.RevisionDelete, nil)
+	}
+
+	before := groupSnapshot(group)
+	if err := applyGroupState(group, previousState(before, revision.Changes)); err != nil {
+		return err
+	}
+	if err := detachInvalidParent(tx, group); err != nil {
+		return err
+	}
+	if err := validateGroup(tx, group); err != nil {
+		return err
+	}
+	if err := groupWriteError(tx.Unscoped().Save(group).Error, "update group"); err != nil {
+		return err
+	}
+	return recordRevision(tx, entityGroup, group.ID, userID, models.RevisionRevert, diffSnapshots(before, groupSnapshot(group)))
+}
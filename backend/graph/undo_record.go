@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"todo-app/models"
+	"todo-app/scheduler"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Entity types of the records other than todos and groups whose changes can
+// be undone. Their revisions hold every column of the record, so that undo
+// can recreate one that was deleted; they do not show up in any history.
+const (
+	entityComment     = "comment"
+	entityAttachment  = "attachment"
+	entityStatus      = "status"
+	entityReminder    = "reminder"
+	entityDependency  = "dependency"
+	entityTimeEntry   = "time_entry"
+	entityPin         = "pin"
+	entitySmartList   = "smart_list"
+	entityGroupMember = "group_member"
+	entityInvitation  = "invitation"
+	// The owner columns of groups and todos, which transferring a group
+	// changes, are kept apart from their history.
+	entityGroupOwner = "group_owner"
+	entityTodoOwner  = "todo_owner"
+)
+
+// An undoableRecord describes the records of one entity type.
+type undoableRecord struct {
+	// model returns a new, empty record.
+	model func() interface{}
+	// restored, if set, runs after undo brought a record back.
+	restored func(tx *gorm.DB, record interface{}) error
+}
+
+var undoableRecords = map[string]undoableRecord{
+	entityComment:     {model: func() interface{} { return &models.Comment{} }},
+	entityAttachment:  {model: func() interface{} { return &models.Attachment{} }},
+	entityStatus:      {model: func() interface{} { return &models.Status{} }},
+	entityReminder:    {model: func() interface{} { return &models.Reminder{} }, restored: rescheduleReminder},
+	entityDependency:  {model: func() interface{} { return &models.Dependency{} }},
+	entityTimeEntry:   {model: func() interface{} { return &models.TimeEntry{} }},
+	entityPin:         {model: func() interface{} { return &models.Pin{} }, restored: repositionPin},
+	entitySmartList:   {model: func() interface{} { return &models.SmartList{} }},
+	entityGroupMember: {model: func() interface{} { return &models.GroupMember{} }},
+	entityInvitation:  {model: func() interface{} { return &models.GroupInvitation{} }},
+	entityGroupOwner:  {model: func() interface{} { return &models.Group{} }},
+	entityTodoOwner:   {model: func() interface{} { return &models.Todo{} }},
+}
+
+// rescheduleReminder schedules a restored reminder again.
+func rescheduleReminder(tx *gorm.DB, record interface{}) error {
+	reminder := record.(*models.Reminder)
+	var todo models.Todo
+	if err := tx.First(&todo, reminder.TodoID).Error; err != nil {
+		return fmt.Errorf("todo not found: %w", err)
+	}
+	return scheduler.ScheduleReminder(tx, reminder, &todo)
+}
+
+// repositionPin moves a restored pin back to its position among the user's
+// pins, which closed the gap it left.
+func repositionPin(tx *gorm.DB, record interface{}) error {
+	pin := record.(*models.Pin)
+	return pinEntity(tx, pin.UserID, pin.EntityType, pin.EntityID, &pin.Position)
+}
+
+// recordSchema returns the schema of the records of entityType.
+func recordSchema(tx *gorm.DB, entityType string) (*schema.Schema, error) {
+	entity, ok := undoableRecords[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown record type %q", entityType)
+	}
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(entity.model()); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// loadRecord loads the record of entityType with the given ID, including a
+// soft-deleted one, together with a snapshot of all of its columns. The
+// snapshot holds JSON values so that it compares with the snapshots read
+// back from revisions.
+func loadRecord(tx *gorm.DB, entityType string, id uint) (interface{}, map[string]interface{}, error) {
+	s, err := recordSchema(tx, entityType)
+	if err != nil {
+		return nil, nil, err
+	}
+	record := undoableRecords[entityType].model()
+	if err := tx.Unscoped().First(record, id).Error; err != nil {
+		return nil, nil, fmt.Errorf("%s not found: %w", entityType, err)
+	}
+	snapshot := map[string]interface{}{}
+	rv := reflect.ValueOf(record).Elem()
+	for _, column := range s.DBNames {
+		value, _ := s.FieldsByDBName[column].ValueOf(tx.Statement.Context, rv)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to snapshot %s: %w", entityType, err)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, nil, fmt.Errorf("failed to snapshot %s: %w", entityType, err)
+		}
+		snapshot[column] = decoded
+	}
+	return record, snapshot, nil
+}
+
+// setRecordColumns writes snapshot values onto record.
+func setRecordColumns(tx *gorm.DB, entityType string, record interface{}, values map[string]interface{}) error {
+	s, err := recordSchema(tx, entityType)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(record).Elem()
+	for column, value := range values {
+		field := s.FieldsByDBName[column]
+		if field == nil {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unexpected %s value %v in revision", column, value)
+		}
+		typed := reflect.New(field.FieldType)
+		if err := json.Unmarshal(data, typed.Interface()); err != nil {
+			return fmt.Errorf("unexpected %s value %v in revision", column, value)
+		}
+		if err := field.Set(tx.Statement.Context, rv, typed.Elem().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordCreated records that actorID created the record of entityType with
+// the given ID, so that undo deletes it again.
+func recordCreated(tx *gorm.DB, entityType string, id, actorID uint) error {
+	_, snapshot, err := loadRecord(tx, entityType, id)
+	if err != nil {
+		return err
+	}
+	return recordRevision(tx, entityType, id, actorID, models.RevisionCreate, creationChanges(snapshot))
+}
+
+// deleteRecord deletes the record of entityType with the given ID as
+// actorID, recording all its columns so that undo can bring it back.
+func deleteRecord(tx *gorm.DB, entityType string, id, actorID uint) error {
+	record, snapshot, err := loadRecord(tx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Delete(record).Error; err != nil {
+		return fmt.Errorf("failed to delete %s: %w", entityType, err)
+	}
+	return recordRevision(tx, entityType, id, actorID, models.RevisionDelete, deletionChanges(snapshot))
+}
+
+// updateRecord applies update to the record of entityType with the given ID
+// as actorID, recording the columns it changed so that undo can set them
+// back.
+func updateRecord(tx *gorm.DB, entityType string, id, actorID uint, update func() error) error {
+	_, before, err := loadRecord(tx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	_, after, err := loadRecord(tx, entityType, id)
+	if err != nil {
+		return err
+	}
+	changes := diffSnapshots(before, after)
+	delete(changes, "updated_at")
+	return recordRevision(tx, entityType, id, actorID, models.RevisionUpdate, changes)
+}
+
+// deletionChanges describes a deleted entity as changes to nothing.
+func deletionChanges(snapshot map[string]interface{}) models.FieldChanges {
+	changes := models.FieldChanges{}
+	for field, value := range snapshot {
+		changes[field] = models.FieldChange{Before: value}
+	}
+	return changes
+}
+
+// undoRecordRevision reverses a revision of a record other than a todo or
+// group, recording the reversal in turn. Bringing back a record fails with
+// ErrUndoConflict when it clashes with one created since, e.g. the same pin
+// made again.
+func undoRecordRevision(tx *gorm.DB, revision *models.Revision, userID uint) error {
+	entityType := revision.EntityType
+	if revision.Action == models.RevisionCreate {
+		return deleteRecord(tx, entityType, revision.EntityID, userID)
+	}
+
+	var record interface{}
+	var before map[string]interface{}
+	action := models.RevisionRevert
+	if revision.Action == models.RevisionDelete {
+		record = undoableRecords[entityType].model()
+		before = map[string]interface{}{}
+		action = models.RevisionRestore
+	} else {
+		var err error
+		if record, before, err = loadRecord(tx, entityType, revision.EntityID); err != nil {
+			return err
+		}
+	}
+	state := map[string]interface{}{}
+	for column, change := range revision.Changes {
+		state[column] = change.Before
+	}
+	if err := setRecordColumns(tx, entityType, record, state); err != nil {
+		return err
+	}
+	// Save recreates a hard-deleted record and clears deleted_at of a
+	// soft-deleted one.
+	if err := tx.Unscoped().Save(record).Error; err != nil {
+		return fmt.Errorf("%w: %s %d: %v", ErrUndoConflict, entityType, revision.EntityID, err)
+	}
+	if restored := undoableRecords[entityType].restored; restored != nil && action == models.RevisionRestore {
+		if err := restored(tx, record); err != nil {
+			return err
+		}
+	}
+	_, after, err := loadRecord(tx, entityType, revision.EntityID)
+	if err != nil {
+		return err
+	}
+	changes := diffSnapshots(before, after)
+	if action == models.RevisionRestore {
+		changes = creationChanges(after)
+	}
+	return recordRevision(tx, entityType, revision.EntityID, userID, action, changes)
+}
@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"todo-app/models"
+)
+
+func id(n uint) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+// TestUndoDeleteSharedGroup deletes a shared group as its owner and undoes
+// it: every member's todo has to go back into the group, although only the
+// creator could touch it while it was ungrouped.
+func TestUndoDeleteSharedGroup(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	owner := &models.User{Email: "owner@example.com", Password: "x"}
+	editor := &models.User{Email: "editor@example.com", Password: "x"}
+	create(t, db, owner, editor)
+
+	group := &models.Group{Name: "Launch", UserID: owner.ID}
+	if err := createGroup(db, group); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addGroupMember(db, group.ID, editor.ID, models.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	assigned := owner.ID
+	todos := []*models.Todo{
+		{Title: "Owner's", UserID: owner.ID, GroupID: &group.ID},
+		{Title: "Editor's", UserID: editor.ID, GroupID: &group.ID},
+		{Title: "Editor's, assigned to the owner", UserID: editor.ID, GroupID: &group.ID, AssigneeID: &assigned},
+	}
+	for _, todo := range todos {
+		create(t, db, todo)
+	}
+
+	mutation := &mutationResolver{NewResolver(db)}
+	ctx := WithUndo(db.Statement.Context)
+	deleted, err := mutation.DeleteGroup(ctx, id(group.ID), id(owner.ID), nil)
+	if err != nil || !deleted {
+		t.Fatalf("DeleteGroup = %v, %v", deleted, err)
+	}
+	token := UndoToken(ctx)
+	if token == "" {
+		t.Fatal("DeleteGroup issued no undo token")
+	}
+
+	if _, err := mutation.Undo(db.Statement.Context, token, id(owner.ID)); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+
+	var restored models.Group
+	if err := db.First(&restored, group.ID).Error; err != nil {
+		t.Fatalf("group was not restored: %v", err)
+	}
+	for _, todo := range todos {
+		var got models.Todo
+		if err := db.First(&got, todo.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got.GroupID == nil || *got.GroupID != group.ID {
+			t.Errorf("%s: group_id = %v, want %d", todo.Title, got.GroupID, group.ID)
+		}
+		if (got.AssigneeID == nil) != (todo.AssigneeID == nil) {
+			t.Errorf("%s: assignee_id = %v, want %v", todo.Title, got.AssigneeID, todo.AssigneeID)
+		}
+	}
+}
+
+// TestUndoRecordDeletions deletes a comment, unpins a todo and removes a
+// group member in turn, and undoes each.
+func TestUndoRecordDeletions(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	owner := &models.User{Email: "owner@example.com", Password: "x"}
+	editor := &models.User{Email: "editor@example.com", Password: "x"}
+	create(t, db, owner, editor)
+
+	group := &models.Group{Name: "Launch", UserID: owner.ID}
+	if err := createGroup(db, group); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addGroupMember(db, group.ID, editor.ID, models.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	todo := &models.Todo{Title: "Ship", UserID: owner.ID, GroupID: &group.ID}
+	create(t, db, todo)
+	comment := &models.Comment{TodoID: todo.ID, AuthorID: owner.ID, Body: "Soon"}
+	create(t, db, comment)
+
+	mutation := &mutationResolver{NewResolver(db)}
+	if _, err := mutation.PinTodo(db.Statement.Context, id(todo.ID), id(owner.ID), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	undo := func(name string, mutate func(ctx context.Context) error) {
+		t.Helper()
+		ctx := WithUndo(db.Statement.Context)
+		if err := mutate(ctx); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		token := UndoToken(ctx)
+		if token == "" {
+			t.Fatalf("%s issued no undo token", name)
+		}
+		if _, err := mutation.Undo(db.Statement.Context, token, id(owner.ID)); err != nil {
+			t.Fatalf("undo %s: %v", name, err)
+		}
+	}
+
+	undo("DeleteComment", func(ctx context.Context) error {
+		_, err := mutation.DeleteComment(ctx, id(comment.ID), id(owner.ID))
+		return err
+	})
+	var restored models.Comment
+	if err := db.First(&restored, comment.ID).Error; err != nil || restored.Body != comment.Body {
+		t.Errorf("comment was not restored: %+v, %v", restored, err)
+	}
+
+	undo("UnpinTodo", func(ctx context.Context) error {
+		_, err := mutation.UnpinTodo(ctx, id(todo.ID), id(owner.ID))
+		return err
+	})
+	var pins int64
+	if err := db.Model(&models.Pin{}).Where("user_id = ? AND entity_id = ?", owner.ID, todo.ID).Count(&pins).Error; err != nil || pins != 1 {
+		t.Errorf("pins = %d, %v; want the pin back", pins, err)
+	}
+
+	undo("RemoveGroupMember", func(ctx context.Context) error {
+		_, err := mutation.RemoveGroupMember(ctx, id(group.ID), id(editor.ID), id(owner.ID))
+		return err
+	})
+	if role, err := groupRole(db, group.ID, editor.ID); err != nil || role != models.RoleEditor {
+		t.Errorf("editor's role = %q, %v; want %q", role, err, models.RoleEditor)
+	}
+}
+
+// TestUndoTransferGroup hands a group over and undoes it: the group, the
+// todos its owner created and the owner role go back to the previous owner.
+func TestUndoTransferGroup(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	owner := &models.User{Email: "owner@example.com", Password: "x"}
+	heir := &models.User{Email: "heir@example.com", Password: "x"}
+	create(t, db, owner, heir)
+	workspaceID, _ := models.WorkspaceFromContext(db.Statement.Context)
+	create(t, db,
+		&models.WorkspaceMember{WorkspaceID: workspaceID, UserID: owner.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: workspaceID, UserID: heir.ID, Role: models.WorkspaceRoleMember},
+	)
+
+	group := &models.Group{Name: "Launch", UserID: owner.ID}
+	if err := createGroup(db, group); err != nil {
+		t.Fatal(err)
+	}
+	todo := &models.Todo{Title: "Ship", UserID: owner.ID, GroupID: &group.ID}
+	create(t, db, todo)
+
+	mutation := &mutationResolver{NewResolver(db)}
+	ctx := WithUndo(db.Statement.Context)
+	if _, err := mutation.TransferGroup(ctx, id(group.ID), id(owner.ID), id(heir.ID)); err != nil {
+		t.Fatalf("TransferGroup: %v", err)
+	}
+	token := UndoToken(ctx)
+	if token == "" {
+		t.Fatal("TransferGroup issued no undo token")
+	}
+	if _, err := mutation.Undo(db.Statement.Context, token, id(owner.ID)); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+
+	var restored models.Group
+	if err := db.First(&restored, group.ID).Error; err != nil || restored.UserID != owner.ID {
+		t.Errorf("group owner = %d, %v; want %d", restored.UserID, err, owner.ID)
+	}
+	var got models.Todo
+	if err := db.First(&got, todo.ID).Error; err != nil || got.UserID != owner.ID {
+		t.Errorf("todo owner = %d, %v; want %d", got.UserID, err, owner.ID)
+	}
+	for user, want := range map[uint]string{owner.ID: models.RoleOwner, heir.ID: ""} {
+		if role, err := groupRole(db, group.ID, user); err != nil || role != want {
+			t.Errorf("user %d's role = %q, %v; want %q", user, role, err, want)
+		}
+	}
+}
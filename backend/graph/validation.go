@@ -0,0 +1,234 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"todo-app/models"
+	"unicode/utf8"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+)
+
+// Length limits of group fields, in characters.
+const (
+	maxGroupNameLength        = 100
+	maxGroupDescriptionLength = 1000
+)
+
+// DefaultGroupColor is the color of groups created without one.
+const DefaultGroupColor = "#3B82F6"
+
+// groupPalette maps the color names the UI offers to their hex values.
+var groupPalette = map[string]string{
+	"red":    "#EF4444",
+	"orange": "#F97316",
+	"amber":  "#F59E0B",
+	"yellow": "#EAB308",
+	"green":  "#22C55E",
+	"teal":   "#14B8A6",
+	"blue":   "#3B82F6",
+	"indigo": "#6366F1",
+	"purple": "#A855F7",
+	"pink":   "#EC4899",
+	"gray":   "#6B7280",
+}
+
+var hexColor = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// FieldError describes what is wrong with one input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of an input. It wraps
+// ErrInvalidInput, so callers that only care about the status keep working.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + " " + f.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidInput, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns e if any field is invalid and nil otherwise.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ErrorPresenter presents resolver errors to GraphQL clients, adding the
+// field errors of a ValidationError under extensions.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		if presented.Extensions == nil {
+			presented.Extensions = map[string]interface{}{}
+		}
+		presented.Extensions["code"] = "INVALID_INPUT"
+		presented.Extensions["fields"] = validation.Fields
+	}
+	return presented
+}
+
+// normalizeColor turns a palette name or a hex color with or without its
+// "#", in three or six digits, into #RRGGBB.
+func normalizeColor(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if hex, ok := groupPalette[color]; ok {
+		return hex, true
+	}
+	m := hexColor.FindStringSubmatch(color)
+	if m == nil {
+		return "", false
+	}
+	digits := m[1]
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	return "#" + strings.ToUpper(digits), true
+}
+
+// validateGroup normalizes group's name and color and checks its fields,
+// including that its owner has no other group of the same name in its
+// workspace, ignoring case. Deleted groups don't count; archived ones do.
+func validateGroup(tx *gorm.DB, group *models.Group) error {
+	v := &ValidationError{}
+
+	group.Name = strings.TrimSpace(group.Name)
+	switch n := utf8.RuneCountInString(group.Name); {
+	case n == 0:
+		v.add("name", "is required")
+	case n > maxGroupNameLength:
+		v.add("name", "must be at most %d characters", maxGroupNameLength)
+	}
+	if utf8.RuneCountInString(group.Description) > maxGroupDescriptionLength {
+		v.add("description", "must be at most %d characters", maxGroupDescriptionLength)
+	}
+
+	if strings.TrimSpace(group.Color) == "" {
+		group.Color = DefaultGroupColor
+	} else if color, ok := normalizeColor(group.Color); ok {
+		group.Color = color
+	} else {
+		v.add("color", "must be a hex color like #3B82F6 or one of the palette colors")
+	}
+
+	if group.Name != "" {
+		taken, err := groupNameTaken(tx, group, group.UserID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			v.add("name", groupNameTakenMessage)
+		}
+	}
+
+	return v.err()
+}
+
+// groupNameIndex keeps the names of each user's live groups in a workspace
+// unique, ignoring case, where validateGroup cannot see concurrent writes.
+const groupNameIndex = "idx_groups_workspace_user_name"
+
+// legacyGroupNameIndex kept names unique across workspaces.
+const legacyGroupNameIndex = "idx_groups_user_name"
+
+const groupNameTakenMessage = "is already used by another of your groups"
+
+// MigrateGroupNames renames the live groups whose owner has an older one of
+// the same name in their workspace, ignoring case, by appending their ID,
+// shortening the name to stay within maxGroupNameLength, and then adds
+// groupNameIndex in place of legacyGroupNameIndex. It must run after the
+// groups table has been migrated.
+func MigrateGroupNames(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE groups
+			SET name = RTRIM(SUBSTR(name, 1, ? - LENGTH(' (' || id || ')'))) || ' (' || id || ')'
+			WHERE deleted_at IS NULL AND EXISTS (
+				SELECT 1 FROM groups older
+				WHERE older.workspace_id = groups.workspace_id AND older.user_id = groups.user_id
+				AND LOWER(older.name) = LOWER(groups.name)
+				AND older.deleted_at IS NULL AND older.id < groups.id
+			)`, maxGroupNameLength).Error
+		if err != nil {
+			return fmt.Errorf("failed to rename duplicate groups: %w", err)
+		}
+		if err := tx.Exec("DROP INDEX IF EXISTS " + legacyGroupNameIndex).Error; err != nil {
+			return fmt.Errorf("failed to drop group name index: %w", err)
+		}
+		err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + groupNameIndex + " ON groups (workspace_id, user_id, LOWER(name)) WHERE deleted_at IS NULL").Error
+		if err != nil {
+			return fmt.Errorf("failed to add group name index: %w", err)
+		}
+		return nil
+	})
+}
+
+// groupNameTaken reports whether ownerID has a live group other than group
+// named like it, ignoring case, in group's workspace, as groupNameIndex does.
+// A group not yet created is in the workspace tx is scoped to.
+func groupNameTaken(tx *gorm.DB, group *models.Group, ownerID uint) (bool, error) {
+	query := tx.Model(&models.Group{})
+	if group.WorkspaceID != 0 {
+		query = models.AllWorkspaces(tx).Model(&models.Group{}).Where("workspace_id = ?", group.WorkspaceID)
+	}
+	var count int64
+	err := query.Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", ownerID, group.Name, group.ID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check group name: %w", err)
+	}
+	return count > 0, nil
+}
+
+// checkGroupName checks that group, about to become live again or to change
+// hands to ownerID, does not clash with another live group of ownerID,
+// reporting a clash with message.
+func checkGroupName(tx *gorm.DB, group *models.Group, ownerID uint, message string) error {
+	taken, err := groupNameTaken(tx, group, ownerID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		v := &ValidationError{}
+		v.add("name", "%s", message)
+		return v
+	}
+	return nil
+}
+
+// groupWriteError reports a write to a group that failed on groupNameIndex
+// as the field error validateGroup gives, and otherwise wraps err as failing
+// to do what.
+func groupWriteError(err error, what string) error {
+	if err == nil {
+		return nil
+	}
+	if strings.Contains(err.Error(), groupNameIndex) {
+		v := &ValidationError{}
+		v.add("name", groupNameTakenMessage)
+		return v
+	}
+	return fmt.Errorf("failed to %s: %w", what, err)
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"todo-app/graph/model"
+	"todo-app/models"
+)
+
+// nameError reports whether err is a validation error of the name field.
+func nameError(err error) bool {
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		return false
+	}
+	for _, f := range validation.Fields {
+		if f.Field == "name" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGroupNameIndex(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	create(t, db, &models.Group{Name: "Home", UserID: user.ID})
+
+	// A write validateGroup did not see, e.g. a concurrent one, still fails
+	// on the name.
+	err := groupWriteError(db.Create(&models.Group{Name: "HOME", UserID: user.ID}).Error, "create group")
+	if !nameError(err) {
+		t.Errorf("duplicate insert error = %v, want a name field error", err)
+	}
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("duplicate insert error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestMigrateGroupNamesRenamesDuplicates(t *testing.T) {
+	db := inTestWorkspace(t, newTestDB(t))
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	home := &models.Group{Name: "Home", UserID: user.ID}
+	create(t, db, home)
+	if err := db.Exec("DROP INDEX " + groupNameIndex).Error; err != nil {
+		t.Fatal(err)
+	}
+	duplicate := &models.Group{Name: "home", UserID: user.ID}
+	long := strings.Repeat("x", maxGroupNameLength)
+	create(t, db, duplicate, &models.Group{Name: long, UserID: user.ID})
+	longDuplicate := &models.Group{Name: long, UserID: user.ID}
+	create(t, db, longDuplicate)
+
+	if err := MigrateGroupNames(db); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	if err := db.Model(&models.Group{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	suffix := " (" + id(longDuplicate.ID) + ")"
+	want := []string{"Home", "home (" + id(duplicate.ID) + ")", long, long[:maxGroupNameLength-len(suffix)] + suffix}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+}
+
+func TestGroupNamesPerWorkspace(t *testing.T) {
+	db := newTestDB(t)
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	first := &models.Workspace{Name: "First"}
+	second := &models.Workspace{Name: "Second"}
+	create(t, db, first, second)
+
+	for _, workspace := range []*models.Workspace{first, second} {
+		scoped := db.WithContext(models.WithWorkspace(context.Background(), workspace.ID))
+		if err := createGroup(scoped, &models.Group{Name: "Home", UserID: user.ID}); err != nil {
+			t.Errorf("creating Home in %s: %v", workspace.Name, err)
+		}
+	}
+}
+
+func TestErrorPresenterListsFields(t *testing.T) {
+	v := &ValidationError{}
+	v.add("name", "is required")
+	presented := ErrorPresenter(context.Background(), v)
+	fields, ok := presented.Extensions["fields"].([]FieldError)
+	if !ok || len(fields) != 1 || fields[0].Field != "name" {
+		t.Errorf("extensions = %v, want the name field error", presented.Extensions)
+	}
+	if code := presented.Extensions["code"]; code != "INVALID_INPUT" {
+		t.Errorf("code = %v, want INVALID_INPUT", code)
+	}
+	if presented := ErrorPresenter(context.Background(), errors.New("boom")); presented.Extensions != nil {
+		t.Errorf("extensions of a plain error = %v, want none", presented.Extensions)
+	}
+}
+
+func TestGroupNameChecks(t *testing.T) {
+	db := newTestDB(t)
+	ann := &models.User{Email: "ann@example.com", Password: "x"}
+	bob := &models.User{Email: "bob@example.com", Password: "x"}
+	create(t, db, ann, bob)
+	workspace := &models.Workspace{Name: "Default"}
+	create(t, db, workspace)
+	create(t, db,
+		&models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: ann.ID, Role: models.WorkspaceRoleMember},
+		&models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: bob.ID, Role: models.WorkspaceRoleMember},
+	)
+	ctx := models.WithWorkspace(context.Background(), workspace.ID)
+	mutation := &mutationResolver{NewResolver(db).within(ctx)}
+
+	trashed, err := mutation.CreateGroup(ctx, id(ann.ID), model.CreateGroupInput{Name: "Home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.DeleteGroup(ctx, id(trashed.ID), id(ann.ID), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.CreateGroup(ctx, id(ann.ID), model.CreateGroupInput{Name: "home"}); err != nil {
+		t.Fatalf("reusing the name of a trashed group: %v", err)
+	}
+	if _, err := mutation.RestoreGroup(ctx, id(trashed.ID), id(ann.ID)); !nameError(err) {
+		t.Errorf("RestoreGroup onto a taken name: error = %v, want a name field error", err)
+	}
+
+	work, err := mutation.CreateGroup(ctx, id(bob.ID), model.CreateGroupInput{Name: "Home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.TransferGroup(ctx, id(work.ID), id(bob.ID), id(ann.ID)); !nameError(err) {
+		t.Errorf("TransferGroup onto a taken name: error = %v, want a name field error", err)
+	}
+
+	renamed, err := mutation.CreateGroup(ctx, id(ann.ID), model.CreateGroupInput{Name: "Garden"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revision models.Revision
+	if err := db.Where("entity_type = ? AND entity_id = ?", entityGroup, renamed.ID).First(&revision).Error; err != nil {
+		t.Fatal(err)
+	}
+	name := "Chores"
+	if _, err := mutation.UpdateGroup(ctx, id(renamed.ID), id(ann.ID), model.UpdateGroupInput{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.CreateGroup(ctx, id(ann.ID), model.CreateGroupInput{Name: "garden"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.RevertGroup(ctx, id(renamed.ID), id(ann.ID), id(revision.ID)); !nameError(err) {
+		t.Errorf("RevertGroup onto a taken name: error = %v, want a name field error", err)
+	}
+}
@@ -0,0 +1,275 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// BackfillWorkspaces puts everything created before workspaces existed into
+// a default workspace, which every user joins and which global admins become
+// admins of.
+// It does nothing once any workspace exists.
+func BackfillWorkspaces(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Workspace{}).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check workspaces: %w", err)
+		}
+		if count > 0 {
+			return nil
+		}
+
+		workspace := models.Workspace{Name: "Default"}
+		if err := tx.Create(&workspace).Error; err != nil {
+			return fmt.Errorf("failed to create default workspace: %w", err)
+		}
+		err := tx.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at)
+			SELECT ?, id, CASE WHEN is_admin THEN ? ELSE ? END, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM users
+			WHERE deleted_at IS NULL`, workspace.ID, models.WorkspaceRoleAdmin, models.WorkspaceRoleMember).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill workspace members: %w", err)
+		}
+		for _, table := range []string{"groups", "todos", "templates"} {
+			if err := tx.Exec("UPDATE "+table+" SET workspace_id = ? WHERE workspace_id IS NULL OR workspace_id = 0", workspace.ID).Error; err != nil {
+				return fmt.Errorf("failed to backfill %s workspace: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// BackfillWorkspaceRecords puts the records that hang off todos, groups and
+// users into the workspace of what they belong to: comments, attachments,
+// time entries, reminders and dependencies that of their todo, statuses that
+// of their group or, for personal ones, the user's first workspace, and pins
+// that of the pinned entity. Records already in a workspace are left alone,
+// so it is safe to run on every start.
+func BackfillWorkspaceRecords(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		backfills := []struct{ table, workspace string }{
+			{"comments", "(SELECT workspace_id FROM todos WHERE todos.id = comments.todo_id)"},
+			{"attachments", "(SELECT workspace_id FROM todos WHERE todos.id = attachments.todo_id)"},
+			{"time_entries", "(SELECT workspace_id FROM todos WHERE todos.id = time_entries.todo_id)"},
+			{"reminders", "(SELECT workspace_id FROM todos WHERE todos.id = reminders.todo_id)"},
+			{"dependencies", "(SELECT workspace_id FROM todos WHERE todos.id = dependencies.todo_id)"},
+			{"statuses", `CASE WHEN group_id IS NOT NULL
+				THEN (SELECT workspace_id FROM groups WHERE groups.id = statuses.group_id)
+				ELSE (SELECT MIN(workspace_id) FROM workspace_members WHERE workspace_members.user_id = statuses.user_id) END`},
+			{"pins", `CASE entity_type
+				WHEN 'group' THEN (SELECT workspace_id FROM groups WHERE groups.id = pins.entity_id)
+				ELSE (SELECT workspace_id FROM todos WHERE todos.id = pins.entity_id) END`},
+		}
+		for _, backfill := range backfills {
+			err := tx.Exec("UPDATE " + backfill.table + " SET workspace_id = COALESCE(" + backfill.workspace + ", 0) WHERE workspace_id IS NULL OR workspace_id = 0").Error
+			if err != nil {
+				return fmt.Errorf("failed to backfill %s workspace: %w", backfill.table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// currentWorkspace returns the workspace tx is scoped to, if any.
+func currentWorkspace(tx *gorm.DB) (uint, bool) {
+	return models.WorkspaceFromContext(tx.Statement.Context)
+}
+
+// workspaceRole returns userID's role in workspaceID, or "" if they are not
+// a member. workspaceID need not be the workspace tx is scoped to.
+func workspaceRole(tx *gorm.DB, workspaceID, userID uint) (string, error) {
+	var members []models.WorkspaceMember
+	if err := models.AllWorkspaces(tx).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Limit(1).Find(&members).Error; err != nil {
+		return "", fmt.Errorf("failed to check workspace membership: %w", err)
+	}
+	if len(members) == 0 {
+		return "", nil
+	}
+	return members[0].Role, nil
+}
+
+// workspaceAdmin reports whether userID is an admin of workspaceID. Being a
+// global admin does not make anyone one.
+func workspaceAdmin(tx *gorm.DB, workspaceID, userID uint) (bool, error) {
+	role, err := workspaceRole(tx, workspaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role == models.WorkspaceRoleAdmin, nil
+}
+
+// isWorkspaceAdmin reports whether userID administers the workspace tx is
+// scoped to. Outside a workspace nobody does.
+func isWorkspaceAdmin(tx *gorm.DB, userID uint) (bool, error) {
+	workspaceID, ok := currentWorkspace(tx)
+	if !ok {
+		return false, nil
+	}
+	return workspaceAdmin(tx, workspaceID, userID)
+}
+
+// workspaceAccess loads workspaceID for userID, who must be a member of it,
+// and an admin when admin is set.
+func workspaceAccess(tx *gorm.DB, workspaceID, userID uint, admin bool) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := tx.First(&workspace, workspaceID).Error; err != nil {
+		return nil, fmt.Errorf("workspace not found: %w", err)
+	}
+	role, err := workspaceRole(tx, workspace.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == models.WorkspaceRoleAdmin || (role != "" && !admin) {
+		return &workspace, nil
+	}
+	if role == "" {
+		return nil, fmt.Errorf("workspace not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil, fmt.Errorf("%w: requires workspace admin", ErrForbidden)
+}
+
+// defaultWorkspace returns the workspace userID joined first, or 0 if they
+// belong to none.
+func defaultWorkspace(tx *gorm.DB, userID uint) (uint, error) {
+	var members []models.WorkspaceMember
+	if err := models.AllWorkspaces(tx).Where("user_id = ?", userID).Order("id").Limit(1).Find(&members).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch workspaces: %w", err)
+	}
+	if len(members) == 0 {
+		return 0, nil
+	}
+	return members[0].WorkspaceID, nil
+}
+
+func workspaceRoleFromModel(role model.WorkspaceRole) string {
+	return strings.ToLower(string(role))
+}
+
+func workspaceRoleToModel(role string) model.WorkspaceRole {
+	return model.WorkspaceRole(strings.ToUpper(role))
+}
+
+// normalizeDomains lower-cases domains, drops a leading "@" and removes
+// blanks and duplicates.
+func normalizeDomains(domains []string) (models.StringList, error) {
+	normalized := models.StringList{}
+	seen := map[string]bool{}
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if domain == "" || seen[domain] {
+			continue
+		}
+		if strings.ContainsAny(domain, "@ ") || !strings.Contains(domain, ".") {
+			return nil, fmt.Errorf("%w: invalid email domain %q", ErrInvalidInput, domain)
+		}
+		seen[domain] = true
+		normalized = append(normalized, domain)
+	}
+	return normalized, nil
+}
+
+// addWorkspaceMember makes user a member of workspace with role, or changes
+// the role of an existing member. The workspace's allowed email domains
+// apply to new members. workspace need not be the one tx is scoped to.
+func addWorkspaceMember(tx *gorm.DB, workspace *models.Workspace, user *models.User, role string) (*models.WorkspaceMember, error) {
+	tx = models.AllWorkspaces(tx)
+	var member models.WorkspaceMember
+	err := tx.Where("workspace_id = ? AND user_id = ?", workspace.ID, user.ID).First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check workspace membership: %w", err)
+	}
+	if member.ID == 0 && !workspace.AllowsEmail(user.Email) {
+		return nil, fmt.Errorf("%w: %s is not allowed to join this workspace", ErrInvalidInput, user.Email)
+	}
+	member.WorkspaceID = workspace.ID
+	member.UserID = user.ID
+	member.Role = role
+	if err := tx.Save(&member).Error; err != nil {
+		return nil, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+	return &member, nil
+}
+
+// joinWorkspace makes userID a member of workspaceID unless they already
+// are one, subject to its allowed email domains.
+func joinWorkspace(tx *gorm.DB, workspaceID, userID uint) error {
+	role, err := workspaceRole(tx, workspaceID, userID)
+	if err != nil || role != "" {
+		return err
+	}
+	var workspace models.Workspace
+	if err := tx.First(&workspace, workspaceID).Error; err != nil {
+		return fmt.Errorf("workspace not found: %w", err)
+	}
+	var user models.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	_, err = addWorkspaceMember(tx, &workspace, &user, models.WorkspaceRoleMember)
+	return err
+}
+
+// setupWorkspaces gives a new user a workspace of their own. Emails are not
+// verified, so matching an allowed email domain never lets anyone into
+// another workspace by itself; its admins have to add or invite them.
+func setupWorkspaces(tx *gorm.DB, user *models.User) error {
+	workspace := models.Workspace{Name: user.Email}
+	if err := tx.Create(&workspace).Error; err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+	_, err := addWorkspaceMember(tx, &workspace, user, models.WorkspaceRoleAdmin)
+	return err
+}
+
+// removeWorkspaceMember removes userID from workspaceID together with their
+// memberships of its groups, unassigning them there as actorID. Members who
+// still own groups in the workspace have to hand them over first.
+func removeWorkspaceMember(tx *gorm.DB, workspaceID, userID, actorID uint) (bool, error) {
+	tx = models.AllWorkspaces(tx)
+	var owned int64
+	if err := tx.Model(&models.Group{}).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Count(&owned).Error; err != nil {
+		return false, fmt.Errorf("failed to check owned groups: %w", err)
+	}
+	if owned > 0 {
+		return false, fmt.Errorf("%w: the member still owns groups in this workspace", ErrInvalidInput)
+	}
+
+	var groupIDs []uint
+	err := tx.Model(&models.GroupMember{}).
+		Where("user_id = ? AND group_id IN (?)", userID, tx.Unscoped().Model(&models.Group{}).Where("workspace_id = ?", workspaceID).Select("id")).
+		Pluck("group_id", &groupIDs).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch group memberships: %w", err)
+	}
+	for _, groupID := range groupIDs {
+		if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error; err != nil {
+			return false, fmt.Errorf("failed to remove group membership: %w", err)
+		}
+		if err := unassignMember(tx, groupID, userID, actorID); err != nil {
+			return false, err
+		}
+	}
+
+	result := tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&models.WorkspaceMember{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove workspace member: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// lastWorkspaceAdmin reports whether userID is the only admin of
+// workspaceID.
+func lastWorkspaceAdmin(tx *gorm.DB, workspaceID, userID uint) (bool, error) {
+	var others int64
+	err := models.AllWorkspaces(tx).Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ? AND user_id <> ?", workspaceID, models.WorkspaceRoleAdmin, userID).
+		Count(&others).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check workspace admins: %w", err)
+	}
+	return others == 0, nil
+}
@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+)
+
+func TestWorkspaceRecordsDoNotLeak(t *testing.T) {
+	db := newTestDB(t)
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	first := &models.Workspace{Name: "First"}
+	second := &models.Workspace{Name: "Second"}
+	create(t, db, first, second)
+	a := &models.Todo{Title: "A", UserID: user.ID, WorkspaceID: first.ID}
+	b := &models.Todo{Title: "B", UserID: user.ID, WorkspaceID: second.ID}
+	create(t, db, a, b)
+
+	ctx := models.WithWorkspace(context.Background(), second.ID)
+	r := NewResolver(db).within(ctx)
+	mutation := &mutationResolver{r}
+	if _, err := mutation.StartTimer(ctx, id(b.ID), id(user.ID), nil); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now().Add(-2 * time.Hour)
+	create(t, db, &models.TimeEntry{TodoID: a.ID, UserID: user.ID, WorkspaceID: first.ID, StartedAt: started, EndedAt: &started})
+	create(t, db, &models.Status{Name: "Elsewhere", UserID: user.ID, WorkspaceID: first.ID})
+
+	query := &queryResolver{r}
+	entries, err := query.TimeEntries(ctx, id(user.ID), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].TodoID != b.ID || entries[0].WorkspaceID != second.ID {
+		t.Errorf("TimeEntries = %+v, want only the entry on B", entries)
+	}
+	statuses, err := query.Statuses(ctx, id(user.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("Statuses = %+v, want none from the other workspace", statuses)
+	}
+
+	// Starting a timer in the first workspace stops the one in the second.
+	ctx = models.WithWorkspace(context.Background(), first.ID)
+	mutation = &mutationResolver{NewResolver(db).within(ctx)}
+	if _, err := mutation.StartTimer(ctx, id(a.ID), id(user.ID), nil); err != nil {
+		t.Fatal(err)
+	}
+	var running int64
+	if err := models.AllWorkspaces(db).Model(&models.TimeEntry{}).Where("ended_at IS NULL").Count(&running).Error; err != nil {
+		t.Fatal(err)
+	}
+	if running != 1 {
+		t.Errorf("%d running timers, want 1", running)
+	}
+}
+
+func TestWorkspaceScopeFailsClosed(t *testing.T) {
+	db := newTestDB(t)
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	scoped := inTestWorkspace(t, db)
+	create(t, scoped, &models.Todo{Title: "A", UserID: user.ID})
+
+	var todos []models.Todo
+	if err := db.Find(&todos).Error; !errors.Is(err, models.ErrNoWorkspace) {
+		t.Errorf("query without a workspace: error = %v, want ErrNoWorkspace", err)
+	}
+	if err := db.Model(&models.Todo{}).Where("id > 0").Update("title", "B").Error; !errors.Is(err, models.ErrNoWorkspace) {
+		t.Errorf("update without a workspace: error = %v, want ErrNoWorkspace", err)
+	}
+	if err := models.AllWorkspaces(db).Create(&models.Todo{Title: "C", UserID: user.ID}).Error; !errors.Is(err, models.ErrNoWorkspace) {
+		t.Errorf("create without a workspace: error = %v, want ErrNoWorkspace", err)
+	}
+	if err := models.AllWorkspaces(db).Find(&todos).Error; err != nil || len(todos) != 1 {
+		t.Errorf("query across workspaces = %d todos, %v; want 1", len(todos), err)
+	}
+}
+
+func TestBackfillWorkspaceRecords(t *testing.T) {
+	db := newTestDB(t)
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	create(t, db, user)
+	first := &models.Workspace{Name: "First"}
+	second := &models.Workspace{Name: "Second"}
+	create(t, db, first, second)
+	create(t, db, &models.WorkspaceMember{WorkspaceID: second.ID, UserID: user.ID, Role: models.WorkspaceRoleMember})
+	group := &models.Group{Name: "Home", UserID: user.ID, WorkspaceID: first.ID}
+	create(t, db, group)
+	todo := &models.Todo{Title: "A", UserID: user.ID, WorkspaceID: second.ID}
+	create(t, db, todo)
+
+	comment := &models.Comment{TodoID: todo.ID, AuthorID: user.ID, Kind: models.CommentKindComment, Body: "hi"}
+	groupStatus := &models.Status{Name: "Doing", UserID: user.ID, GroupID: &group.ID}
+	personalStatus := &models.Status{Name: "Waiting", UserID: user.ID}
+	pin := &models.Pin{UserID: user.ID, EntityType: entityGroup, EntityID: group.ID}
+	create(t, db.WithContext(models.WithWorkspace(context.Background(), first.ID)), comment, groupStatus, personalStatus, pin)
+	// They predate workspaces.
+	for _, table := range []string{"comments", "statuses", "pins"} {
+		if err := db.Exec("UPDATE " + table + " SET workspace_id = 0").Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := BackfillWorkspaceRecords(db); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		record interface{}
+		id     uint
+		want   uint
+	}{
+		{"comment", &models.Comment{}, comment.ID, second.ID},
+		{"group status", &models.Status{}, groupStatus.ID, first.ID},
+		{"personal status", &models.Status{}, personalStatus.ID, second.ID},
+		{"pin", &models.Pin{}, pin.ID, first.ID},
+	}
+	for _, tt := range tests {
+		var got uint
+		if err := models.AllWorkspaces(db).Model(tt.record).Where("id = ?", tt.id).Select("workspace_id").Scan(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s workspace = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCreateUserDoesNotJoinByDomain(t *testing.T) {
+	db := newTestDB(t)
+	corp := &models.Workspace{Name: "Corp", AllowedEmailDomains: models.StringList{"corp.example"}}
+	create(t, db, corp)
+
+	mutation := &mutationResolver{NewResolver(db)}
+	user, err := mutation.CreateUser(context.Background(), model.CreateUserInput{Email: "eve@corp.example", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var workspaceIDs []uint
+	if err := models.AllWorkspaces(db).Model(&models.WorkspaceMember{}).Where("user_id = ?", user.ID).Pluck("workspace_id", &workspaceIDs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaceIDs) != 1 || workspaceIDs[0] == corp.ID {
+		t.Errorf("new user joined workspaces %v, want only a workspace of their own", workspaceIDs)
+	}
+}
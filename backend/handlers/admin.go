@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
 
@@ -9,9 +8,13 @@ import (
 )
 
 func GetAllUsers(c *gin.Context) {
-	ctx := context.Background()
+	currentUserID, _ := c.Get("user_id")
+	ctx := requestContext(c)
 
-	users, err := GQLClient.GetUsers(ctx)
+	users, err := GQLClient.GetUsers(ctx, strconv.FormatUint(uint64(currentUserID.(uint)), 10))
+	if forbidden(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
 		return
@@ -22,11 +25,11 @@ func GetAllUsers(c *gin.Context) {
 
 func GetUser(c *gin.Context) {
 	userID := c.Param("id")
-	ctx := context.Background()
+	currentUserID, _ := c.Get("user_id")
+	ctx := requestContext(c)
 
-	user, err := GQLClient.GetUserByID(ctx, userID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := GQLClient.GetManagedUser(ctx, userID, strconv.FormatUint(uint64(currentUserID.(uint)), 10))
+	if memberError(c, err, "User not found") {
 		return
 	}
 
@@ -35,7 +38,7 @@ func GetUser(c *gin.Context) {
 
 func DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
-	ctx := context.Background()
+	ctx := requestContext(c)
 
 	currentUserID, _ := c.Get("user_id")
 	targetID, _ := strconv.ParseUint(userID, 10, 64)
@@ -44,8 +47,18 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	deleted, err := GQLClient.DeleteUser(ctx, userID)
-	if err != nil || !deleted {
+	// With ?successor_id= the user's groups are handed over instead of
+	// going down with them.
+	var successorID *string
+	if v := c.Query("successor_id"); v != "" {
+		successorID = &v
+	}
+
+	deleted, err := GQLClient.DeleteUser(ctx, userID, strconv.FormatUint(uint64(currentUserID.(uint)), 10), successorID)
+	if memberError(c, err, "User not found") {
+		return
+	}
+	if !deleted {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
@@ -55,7 +68,8 @@ func DeleteUser(c *gin.Context) {
 
 func UpdateUserAdmin(c *gin.Context) {
 	userID := c.Param("id")
-	ctx := context.Background()
+	currentUserID, _ := c.Get("user_id")
+	ctx := requestContext(c)
 
 	var input struct {
 		IsAdmin bool `json:"is_admin"`
@@ -65,9 +79,8 @@ func UpdateUserAdmin(c *gin.Context) {
 		return
 	}
 
-	user, err := GQLClient.UpdateUserAdmin(ctx, userID, input.IsAdmin)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := GQLClient.UpdateUserAdmin(ctx, userID, strconv.FormatUint(uint64(currentUserID.(uint)), 10), input.IsAdmin)
+	if memberError(c, err, "User not found") {
 		return
 	}
 
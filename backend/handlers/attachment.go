@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"todo-app/graph"
+	"todo-app/models"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+)
+
+// downloadURLTTL is how long a download URL handed out with an attachment
+// stays valid.
+const downloadURLTTL = 15 * time.Minute
+
+// Content types that browsers may display inline; everything else is served
+// as a download so that uploaded HTML or SVG never runs in our origin.
+var inlineContentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type attachmentWithURL struct {
+	*models.Attachment
+	DownloadURL string `json:"download_url"`
+}
+
+// downloadSignature authenticates a download URL for attachmentID in
+// workspaceID on behalf of userID until expires, so that the URL works
+// without the JWT (e.g. as an <img> source) but only for whoever was allowed
+// to list it, and only in the workspace it was listed in.
+func downloadSignature(attachmentID, workspaceID, userID uint, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SESSION_SECRET")))
+	fmt.Fprintf(mac, "attachment:%d:%d:%d:%d", attachmentID, workspaceID, userID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func withDownloadURL(attachment *models.Attachment, userID uint) attachmentWithURL {
+	expires := time.Now().Add(downloadURLTTL).Unix()
+	url := fmt.Sprintf("/api/attachments/%d/download?workspace=%d&user=%d&expires=%d&signature=%s",
+		attachment.ID, attachment.WorkspaceID, userID, expires,
+		downloadSignature(attachment.ID, attachment.WorkspaceID, userID, expires))
+	return attachmentWithURL{Attachment: attachment, DownloadURL: url}
+}
+
+func GetAttachments(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	attachments, err := GQLClient.GetAttachments(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	result := make([]attachmentWithURL, len(attachments))
+	for i, attachment := range attachments {
+		result[i] = withDownloadURL(attachment, userID.(uint))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": result})
+}
+
+func UploadAttachment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	// Leave room for the multipart framing around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, GQLClient.MaxAttachmentSize()+1<<20)
+
+	header, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Files may be at most %d bytes", GQLClient.MaxAttachmentSize())})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" form field"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	attachment, err := GQLClient.UploadAttachment(ctx, todoID, userIDStr, graphql.Upload{
+		File:        file,
+		Filename:    header.Filename,
+		Size:        header.Size,
+		ContentType: header.Header.Get("Content-Type"),
+	})
+	switch {
+	case errors.Is(err, graph.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, graph.ErrAttachmentTooLarge), errors.Is(err, graph.ErrQuotaExceeded):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, graph.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"attachment": withDownloadURL(attachment, userID.(uint))})
+}
+
+func DeleteAttachment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	attachmentID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteAttachment(ctx, attachmentID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Attachment deleted successfully"}))
+}
+
+// DownloadAttachment serves attachment contents to holders of a signed URL
+// from GetAttachments or UploadAttachment. The route is outside
+// WorkspaceMiddleware, so the workspace comes from the signed URL. Access is
+// checked again so that URLs stop working once the todo is deleted or the
+// user leaves the workspace.
+func DownloadAttachment(c *gin.Context) {
+	attachmentID, err1 := strconv.ParseUint(c.Param("id"), 10, 64)
+	workspaceID, err2 := strconv.ParseUint(c.Query("workspace"), 10, 64)
+	userID, err3 := strconv.ParseUint(c.Query("user"), 10, 64)
+	expires, err4 := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || workspaceID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid download URL"})
+		return
+	}
+
+	expected := downloadSignature(uint(attachmentID), uint(workspaceID), uint(userID), expires)
+	if !hmac.Equal([]byte(expected), []byte(c.Query("signature"))) || time.Now().Unix() > expires {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Download URL is invalid or has expired"})
+		return
+	}
+
+	ctx := models.WithWorkspace(c.Request.Context(), uint(workspaceID))
+	attachment, body, err := GQLClient.OpenAttachment(ctx, uint(attachmentID), uint(userID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if inlineContentTypes[attachment.ContentType] {
+		disposition = "inline"
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=900",
+	})
+}
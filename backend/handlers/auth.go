@@ -1,11 +1,13 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
 	"todo-app/models"
 
 	"github.com/gin-gonic/gin"
@@ -29,7 +31,7 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := accountContext(c)
 
 	existingUser, _ := GQLClient.GetUserByEmail(ctx, input.Email)
 	if existingUser != nil {
@@ -43,7 +45,13 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	token, err := generateToken(*user)
+	workspaceID, err := GQLClient.DefaultWorkspace(ctx, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	token, err := generateToken(*user, workspaceID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
@@ -57,6 +65,7 @@ func Register(c *gin.Context) {
 			"email":    user.Email,
 			"is_admin": user.IsAdmin,
 		},
+		"workspace_id": workspaceID,
 	})
 }
 
@@ -67,7 +76,7 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := accountContext(c)
 
 	user, err := GQLClient.GetUserByEmail(ctx, input.Email)
 	if err != nil {
@@ -80,7 +89,13 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, err := generateToken(*user)
+	workspaceID, err := GQLClient.DefaultWorkspace(ctx, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	token, err := generateToken(*user, workspaceID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
@@ -94,12 +109,13 @@ func Login(c *gin.Context) {
 			"email":    user.Email,
 			"is_admin": user.IsAdmin,
 		},
+		"workspace_id": workspaceID,
 	})
 }
 
 func GetMe(c *gin.Context) {
 	userID, _ := c.Get("user_id")
-	ctx := context.Background()
+	ctx := accountContext(c)
 
 	user, err := GQLClient.GetUserByID(ctx, strconv.FormatUint(uint64(userID.(uint)), 10))
 	if err != nil {
@@ -109,24 +125,64 @@ func GetMe(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
-			"id":       user.ID,
-			"email":    user.Email,
-			"is_admin": user.IsAdmin,
+			"id":        user.ID,
+			"email":     user.Email,
+			"is_admin":  user.IsAdmin,
+			"time_zone": user.TimeZone,
+		},
+	})
+}
+
+type UpdateMeInput struct {
+	TimeZone *string `json:"time_zone"`
+}
+
+func UpdateMe(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := accountContext(c)
+
+	var input UpdateMeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := GQLClient.UpdateUserSettings(ctx, strconv.FormatUint(uint64(userID.(uint)), 10), model.UpdateUserSettingsInput{
+		TimeZone: input.TimeZone,
+	})
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": gin.H{
+			"id":        user.ID,
+			"email":     user.Email,
+			"is_admin":  user.IsAdmin,
+			"time_zone": user.TimeZone,
 		},
 	})
 }
 
-func generateToken(user models.User) (string, error) {
+// generateToken signs a token for user working in workspaceID, which requests
+// use unless they name another in the X-Workspace-ID header.
+func generateToken(user models.User, workspaceID uint) (string, error) {
 	secret := os.Getenv("SESSION_SECRET")
 	if secret == "" {
 		return "", jwt.ErrTokenSignatureInvalid
 	}
 
 	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"email":    user.Email,
-		"is_admin": user.IsAdmin,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"user_id":      user.ID,
+		"email":        user.Email,
+		"is_admin":     user.IsAdmin,
+		"workspace_id": workspaceID,
+		"exp":          time.Now().Add(time.Hour * 24 * 7).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+func GetComments(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	includeActivity := c.Query("activity") != "false"
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	comments, err := GQLClient.GetComments(ctx, todoID, userIDStr, includeActivity)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+func AddComment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	comment, err := GQLClient.AddComment(ctx, todoID, userIDStr, input.Body)
+	if forbidden(c, err) {
+		return
+	}
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"comment": comment})
+}
+
+func UpdateComment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	commentID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	comment, err := GQLClient.UpdateComment(ctx, commentID, userIDStr, input.Body)
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comment": comment})
+}
+
+func DeleteComment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	commentID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteComment(ctx, commentID, userIDStr)
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Comment deleted successfully"}))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddDependencyInput struct {
+	BlockerID string `json:"blocker_id" binding:"required"`
+}
+
+func GetDependencies(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	blockedBy, blocking, blocked, err := GQLClient.GetDependencies(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocked": blocked, "blocked_by": blockedBy, "blocking": blocking})
+}
+
+func AddDependency(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input AddDependencyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	dependency, err := GQLClient.AddDependency(ctx, todoID, input.BlockerID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if errors.Is(err, graph.ErrDependencyCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Dependency would create a cycle"})
+		return
+	}
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add dependency"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"dependency": dependency})
+}
+
+func RemoveDependency(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	blockerID := c.Param("blockerId")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	removed, err := GQLClient.RemoveDependency(ctx, todoID, blockerID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil || !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dependency not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Dependency removed successfully"}))
+}
+
+func GetDependencyGraph(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	dependencies, err := GQLClient.GetDependencyGraph(ctx, groupID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"nodes": dependencies.Nodes, "edges": dependencies.Edges})
+}
@@ -2,16 +2,27 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CreateGroupInput struct {
-	Name        string  `json:"name" binding:"required"`
+	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Color       *string `json:"color"`
+	ParentID    *string `json:"parent_id"`
+}
+
+type MoveGroupInput struct {
+	ParentID *string `json:"parent_id"`
 }
 
 type UpdateGroupInput struct {
@@ -20,24 +31,91 @@ type UpdateGroupInput struct {
 	Color       *string `json:"color"`
 }
 
+// GroupResponse is a group together with its todo counts and whether the
+// user pinned it.
+type GroupResponse struct {
+	*models.Group
+	Stats  gin.H `json:"stats"`
+	Pinned bool  `json:"pinned"`
+}
+
+func groupStatsJSON(stats *model.GroupStats) gin.H {
+	return gin.H{
+		"total":            stats.Total,
+		"open":             stats.Open,
+		"completed":        stats.Completed,
+		"percent_complete": stats.PercentComplete,
+		"overdue":          stats.Overdue,
+		"last_activity_at": stats.LastActivityAt,
+	}
+}
+
+// groupResponses adds each group's stats, computed in one query for all of
+// them, and whether userID pinned it.
+func groupResponses(ctx context.Context, userID string, groups []*models.Group) ([]GroupResponse, error) {
+	stats, err := GQLClient.GetGroupStats(ctx, groups)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := GQLClient.PinnedGroupIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]GroupResponse, len(groups))
+	for i, group := range groups {
+		response[i] = GroupResponse{Group: group, Stats: groupStatsJSON(stats[group.ID]), Pinned: pinned[group.ID]}
+	}
+	return response, nil
+}
+
+// GetGroups lists the user's groups, pinned ones first and archived ones
+// only with ?view=archived or ?view=all, followed by their smart lists.
 func GetGroups(c *gin.Context) {
 	userID, _ := c.Get("user_id")
-	ctx := context.Background()
+	ctx := requestContext(c)
+
+	var view *model.GroupView
+	if v := c.Query("view"); v != "" {
+		groupView := model.GroupView(strings.ToUpper(v))
+		if !groupView.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view: " + v})
+			return
+		}
+		view = &groupView
+	}
 
 	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
-	groups, err := GQLClient.GetGroups(ctx, userIDStr)
+	groups, err := GQLClient.GetGroups(ctx, userIDStr, view)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	response, err := groupResponses(ctx, userIDStr, groups)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+
+	lists, err := GQLClient.GetSmartLists(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
+		return
+	}
+	smartLists, err := smartListResponses(ctx, lists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch groups"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"groups": groups})
+	c.JSON(http.StatusOK, gin.H{"groups": response, "smart_lists": smartLists})
 }
 
+// GetGroup returns a group with its todo counts.
 func GetGroup(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	groupID := c.Param("id")
-	ctx := context.Background()
+	ctx := requestContext(c)
 
 	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
 	group, err := GQLClient.GetGroup(ctx, groupID, userIDStr)
@@ -46,12 +124,49 @@ func GetGroup(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"group": group})
+	response, err := groupResponses(ctx, userIDStr, []*models.Group{group})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": response[0]})
+}
+
+// GetGroupTodos lists a group's todos, taking the same filters and sort
+// order as GET /todos. ?subgroups=true includes nested groups' todos.
+func GetGroupTodos(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	filter, sort, err := parseTodoQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if v := c.Query("subgroups"); v != "" {
+		subgroups, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subgroups: " + strconv.Quote(v)})
+			return
+		}
+		filter.IncludeSubgroups = &subgroups
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todos, err := GQLClient.GetGroupTodos(ctx, groupID, userIDStr, filter, sort)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"todos": todos})
 }
 
 func CreateGroup(c *gin.Context) {
 	userID, _ := c.Get("user_id")
-	ctx := context.Background()
+	ctx := graph.WithUndo(requestContext(c))
 
 	var input CreateGroupInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -66,19 +181,26 @@ func CreateGroup(c *gin.Context) {
 		description = &input.Description
 	}
 
-	group, err := GQLClient.CreateGroup(ctx, userIDStr, input.Name, description, input.Color)
+	group, err := GQLClient.CreateGroup(ctx, userIDStr, input.Name, description, input.Color, input.ParentID)
+	if forbidden(c, err) || invalidInput(c, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Parent group not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"group": group})
+	c.JSON(http.StatusCreated, undoable(ctx, gin.H{"group": group}))
 }
 
 func UpdateGroup(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	groupID := c.Param("id")
-	ctx := context.Background()
+	ctx := graph.WithUndo(requestContext(c))
 
 	var input UpdateGroupInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -89,25 +211,110 @@ func UpdateGroup(c *gin.Context) {
 	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
 
 	group, err := GQLClient.UpdateGroup(ctx, groupID, userIDStr, input.Name, input.Description, input.Color)
+	if forbidden(c, err) || invalidInput(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"group": group})
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
 }
 
 func DeleteGroup(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	groupID := c.Param("id")
-	ctx := context.Background()
+	ctx := graph.WithUndo(requestContext(c))
 
 	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
-	deleted, err := GQLClient.DeleteGroup(ctx, groupID, userIDStr)
+	// Subgroups move up to the group's parent unless ?mode=cascade deletes
+	// them along with it.
+	var mode *model.GroupDeleteMode
+	if v := c.Query("mode"); v != "" {
+		m := model.GroupDeleteMode(strings.ToUpper(v))
+		if !m.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be reparent or cascade"})
+			return
+		}
+		mode = &m
+	}
+
+	deleted, err := GQLClient.DeleteGroup(ctx, groupID, userIDStr, mode)
+	if forbidden(c, err) {
+		return
+	}
 	if err != nil || !deleted {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Group deleted successfully"}))
+}
+
+// MoveGroup moves a group with its subgroups under another group, or to the
+// top level when parent_id is null or empty.
+func MoveGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	var input MoveGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.MoveGroup(ctx, groupID, userIDStr, input.ParentID)
+	if forbidden(c, err) {
+		return
+	}
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
+}
+
+func ArchiveGroup(c *gin.Context) {
+	setGroupArchived(c, true)
+}
+
+func UnarchiveGroup(c *gin.Context) {
+	setGroupArchived(c, false)
+}
+
+// setGroupArchived archives or unarchives a group with its subgroups.
+func setGroupArchived(c *gin.Context, archived bool) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	var group *models.Group
+	var err error
+	if archived {
+		group, err = GQLClient.ArchiveGroup(ctx, groupID, userIDStr)
+	} else {
+		group, err = GQLClient.UnarchiveGroup(ctx, groupID, userIDStr)
+	}
+	if forbidden(c, err) {
+		return
+	}
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
 }
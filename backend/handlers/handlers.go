@@ -1,9 +1,62 @@
 package handlers
 
-import "todo-app/graph"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"todo-app/graph"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
 
 var GQLClient *graph.Client
 
 func InitGraphQLClient(client *graph.Client) {
 	GQLClient = client
 }
+
+// forbidden answers err with 403 Forbidden if it is refused for lack of
+// access, and reports whether it was.
+func forbidden(c *gin.Context, err error) bool {
+	if !errors.Is(err, graph.ErrForbidden) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	return true
+}
+
+// invalidInput answers err with 400 Bad Request if it was caused by the
+// request, listing the offending fields when it says which, and reports
+// whether it was.
+func invalidInput(c *gin.Context, err error) bool {
+	if !errors.Is(err, graph.ErrInvalidInput) {
+		return false
+	}
+	response := gin.H{"error": err.Error()}
+	var validation *graph.ValidationError
+	if errors.As(err, &validation) {
+		response["fields"] = validation.Fields
+	}
+	c.JSON(http.StatusBadRequest, response)
+	return true
+}
+
+// requestContext returns the context for GraphQL calls made on behalf of c,
+// scoped to the workspace WorkspaceMiddleware chose for it and ended when
+// the client goes away.
+func requestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if workspaceID, ok := c.Get("workspace_id"); ok {
+		ctx = models.WithWorkspace(ctx, workspaceID.(uint))
+	}
+	return ctx
+}
+
+// accountContext returns the context for GraphQL calls made on behalf of c
+// on routes outside WorkspaceMiddleware, such as signing in and managing
+// workspaces, whose resolvers name the workspaces they work on. It is not
+// scoped to any workspace.
+func accountContext(c *gin.Context) context.Context {
+	return models.WithWorkspace(c.Request.Context(), 0)
+}
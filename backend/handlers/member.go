@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+type MemberInput struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+type TransferGroupInput struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+type MemberResponse struct {
+	GroupID   uint      `json:"group_id"`
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// memberError answers err from a membership call and reports whether there
+// was one.
+func memberError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, graph.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case invalidInput(c, err):
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	}
+	return true
+}
+
+func memberResponse(ctx context.Context, member *models.GroupMember) (MemberResponse, error) {
+	email, err := GQLClient.MemberEmail(ctx, member)
+	if err != nil {
+		return MemberResponse{}, err
+	}
+	return MemberResponse{
+		GroupID:   member.GroupID,
+		UserID:    member.UserID,
+		Email:     email,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}, nil
+}
+
+func GetGroupMembers(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	members, err := GQLClient.GetGroupMembers(ctx, groupID, userIDStr)
+	if memberError(c, err, "Group not found") {
+		return
+	}
+
+	response := make([]MemberResponse, 0, len(members))
+	for _, member := range members {
+		m, err := memberResponse(ctx, member)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+			return
+		}
+		response = append(response, m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": response})
+}
+
+func UpdateGroupMember(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	memberID := c.Param("userId")
+	ctx := requestContext(c)
+
+	var input MemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	member, err := GQLClient.UpdateGroupMember(ctx, groupID, memberID, userIDStr, model.GroupRole(strings.ToUpper(input.Role)))
+	if memberError(c, err, "Member not found") {
+		return
+	}
+
+	response, err := memberResponse(ctx, member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"member": response})
+}
+
+func RemoveGroupMember(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	memberID := c.Param("userId")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	removed, err := GQLClient.RemoveGroupMember(ctx, groupID, memberID, userIDStr)
+	if memberError(c, err, "Member not found") {
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Member removed successfully"}))
+}
+
+// TransferGroup hands a group and its owner's todos in it to another user;
+// the previous owner stays on as an editor.
+func TransferGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	var input TransferGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.TransferGroup(ctx, groupID, userIDStr, input.UserID)
+	if memberError(c, err, "Group not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
+}
+
+func GetGroupInvitations(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	invitations, err := GQLClient.GetGroupInvitations(ctx, groupID, userIDStr)
+	if memberError(c, err, "Group not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func InviteToGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input InvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	invitation, err := GQLClient.InviteToGroup(ctx, groupID, userIDStr, input.Email, model.GroupRole(strings.ToUpper(input.Role)))
+	if memberError(c, err, "Group not found") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invitation": invitation})
+}
+
+func RevokeInvitation(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	invitationID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	revoked, err := GQLClient.RevokeInvitation(ctx, invitationID, userIDStr)
+	if memberError(c, err, "Invitation not found") {
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Invitation revoked successfully"}))
+}
+
+func GetInvitations(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	invitations, err := GQLClient.GetInvitations(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func AcceptInvitation(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	invitationID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	member, err := GQLClient.AcceptInvitation(ctx, invitationID, userIDStr)
+	if memberError(c, err, "Invitation not found") {
+		return
+	}
+
+	response, err := memberResponse(ctx, member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"member": response})
+}
+
+func DeclineInvitation(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	invitationID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	invitation, err := GQLClient.DeclineInvitation(ctx, invitationID, userIDStr)
+	if memberError(c, err, "Invitation not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitation": invitation})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetNotifications(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	unreadOnly := c.Query("unread") == "true"
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	notifications, err := GQLClient.GetNotifications(ctx, userIDStr, unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	notificationID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	notification, err := GQLClient.MarkNotificationRead(ctx, notificationID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notification": notification})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PinInput optionally places a pin among the user's others, counting from 0.
+// Without a position it goes after them.
+type PinInput struct {
+	Position *int `json:"position"`
+}
+
+// bindPinInput reads the optional body of a pin request.
+func bindPinInput(c *gin.Context) (*PinInput, bool) {
+	var input PinInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &input, true
+}
+
+// GetPins lists the groups and todos the user pinned, in pin order.
+func GetPins(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	pins, err := GQLClient.GetPins(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pins"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": pins.Groups, "todos": pins.Todos})
+}
+
+// PinGroup pins a group for the user alone; pinning it again moves it.
+func PinGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	input, ok := bindPinInput(c)
+	if !ok {
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.PinGroup(ctx, groupID, userIDStr, input.Position)
+	if invalidInput(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": group})
+}
+
+func UnpinGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.UnpinGroup(ctx, groupID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
+}
+
+// PinTodo pins a todo for the user alone; pinning it again moves it.
+func PinTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	input, ok := bindPinInput(c)
+	if !ok {
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.PinTodo(ctx, todoID, userIDStr, input.Position)
+	if invalidInput(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"todo": todo})
+}
+
+func UnpinTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.UnpinTodo(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateReminderInput struct {
+	RemindAt      *time.Time `json:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes"`
+	Channel       *string    `json:"channel"`
+}
+
+func GetReminders(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	reminders, err := GQLClient.GetReminders(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": reminders})
+}
+
+func CreateReminder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input CreateReminderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	reminder, err := GQLClient.CreateReminder(ctx, userIDStr, model.CreateReminderInput{
+		TodoID:        todoID,
+		RemindAt:      input.RemindAt,
+		OffsetMinutes: input.OffsetMinutes,
+		Channel:       input.Channel,
+	})
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"reminder": reminder})
+}
+
+func DeleteReminder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	reminderID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteReminder(ctx, reminderID, userIDStr)
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Reminder deleted successfully"}))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetTodoHistory(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	revisions, err := GQLClient.GetTodoHistory(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+func RevertTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	revisionID := c.Param("revisionId")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.RevertTodo(ctx, todoID, userIDStr, revisionID)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo or revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
+}
+
+func GetGroupHistory(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	revisions, err := GQLClient.GetGroupHistory(ctx, groupID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+func RevertGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	revisionID := c.Param("revisionId")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.RevertGroup(ctx, groupID, userIDStr, revisionID)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group or revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
+}
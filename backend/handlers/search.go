@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Search(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+
+	var limit *int
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = &n
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	results, err := GQLClient.Search(ctx, userIDStr, query, limit)
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search todos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
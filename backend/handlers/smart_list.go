@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SmartListFilterInput struct {
+	Completed        *bool   `json:"completed"`
+	AssignedToMe     *bool   `json:"assigned_to_me"`
+	MinPriority      *string `json:"min_priority" binding:"omitempty,oneof=none low medium high"`
+	Due              *string `json:"due" binding:"omitempty,oneof=overdue today week month none"`
+	GroupIDs         []uint  `json:"group_ids"`
+	IncludeSubgroups *bool   `json:"include_subgroups"`
+}
+
+type CreateSmartListInput struct {
+	Name     string               `json:"name" binding:"required"`
+	Filter   SmartListFilterInput `json:"filter"`
+	TimeZone *string              `json:"time_zone"`
+}
+
+type UpdateSmartListInput struct {
+	Name     *string               `json:"name"`
+	Filter   *SmartListFilterInput `json:"filter"`
+	TimeZone *string               `json:"time_zone"`
+}
+
+func (f *SmartListFilterInput) model() *model.SmartListFilterInput {
+	input := &model.SmartListFilterInput{
+		Completed:        f.Completed,
+		AssignedToMe:     f.AssignedToMe,
+		MinPriority:      priorityInput(f.MinPriority),
+		IncludeSubgroups: f.IncludeSubgroups,
+	}
+	if f.Due != nil {
+		due := model.SmartListDue(strings.ToUpper(*f.Due))
+		input.Due = &due
+	}
+	for _, id := range f.GroupIDs {
+		input.GroupIds = append(input.GroupIds, strconv.FormatUint(uint64(id), 10))
+	}
+	return input
+}
+
+// smartListResponse describes list in the shape it was created with, along
+// with the number of todos it matches right now.
+func smartListResponse(ctx context.Context, list *models.SmartList) (gin.H, error) {
+	count, err := GQLClient.GetSmartListCount(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	return smartListBody(list, count), nil
+}
+
+// smartListResponses describes lists like smartListResponse, counting all
+// their todos in one go.
+func smartListResponses(ctx context.Context, lists []*models.SmartList) ([]gin.H, error) {
+	counts, err := GQLClient.GetSmartListCounts(ctx, lists)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]gin.H, len(lists))
+	for i, list := range lists {
+		response[i] = smartListBody(list, counts[list.ID])
+	}
+	return response, nil
+}
+
+func smartListBody(list *models.SmartList, count int) gin.H {
+	filter := gin.H{
+		"completed":         list.Filter.Completed,
+		"assigned_to_me":    list.Filter.AssignedToMe,
+		"min_priority":      nil,
+		"due":               nil,
+		"group_ids":         list.Filter.GroupIDs,
+		"include_subgroups": list.Filter.IncludeSubgroups,
+	}
+	if list.Filter.MinPriority != nil {
+		filter["min_priority"] = strings.ToLower(string(GQLClient.PriorityFromLevel(*list.Filter.MinPriority)))
+	}
+	if list.Filter.Due != "" {
+		filter["due"] = list.Filter.Due
+	}
+	if list.Filter.GroupIDs == nil {
+		filter["group_ids"] = []uint{}
+	}
+
+	return gin.H{
+		"id":           list.ID,
+		"user_id":      list.UserID,
+		"workspace_id": list.WorkspaceID,
+		"name":         list.Name,
+		"filter":       filter,
+		"time_zone":    list.TimeZone,
+		"count":        count,
+		"created_at":   list.CreatedAt,
+		"updated_at":   list.UpdatedAt,
+	}
+}
+
+func GetSmartLists(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	lists, err := GQLClient.GetSmartLists(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch smart lists"})
+		return
+	}
+
+	response, err := smartListResponses(ctx, lists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch smart lists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"smart_lists": response})
+}
+
+func GetSmartList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	listID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	list, err := GQLClient.GetSmartList(ctx, listID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Smart list not found"})
+		return
+	}
+
+	response, err := smartListResponse(ctx, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch smart list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"smart_list": response})
+}
+
+// GetSmartListTodos lists the todos a smart list matches, taking the same
+// sort order as GET /todos.
+func GetSmartListTodos(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	listID := c.Param("id")
+	ctx := requestContext(c)
+
+	_, sort, err := parseTodoQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	list, err := GQLClient.GetSmartList(ctx, listID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Smart list not found"})
+		return
+	}
+
+	todos, err := GQLClient.GetSmartListTodos(ctx, list, sort)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch todos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"todos": todos})
+}
+
+func CreateSmartList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	var input CreateSmartListInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	list, err := GQLClient.CreateSmartList(ctx, userIDStr, model.CreateSmartListInput{
+		Name:     input.Name,
+		Filter:   input.Filter.model(),
+		TimeZone: input.TimeZone,
+	})
+	if invalidInput(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create smart list"})
+		return
+	}
+
+	response, err := smartListResponse(ctx, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch smart list"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"smart_list": response})
+}
+
+// UpdateSmartList renames a smart list or changes its time zone; a filter,
+// when given, replaces the current one as a whole.
+func UpdateSmartList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	listID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input UpdateSmartListInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := model.UpdateSmartListInput{
+		Name:     input.Name,
+		TimeZone: input.TimeZone,
+	}
+	if input.Filter != nil {
+		update.Filter = input.Filter.model()
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	list, err := GQLClient.UpdateSmartList(ctx, listID, userIDStr, update)
+	if invalidInput(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Smart list not found"})
+		return
+	}
+
+	response, err := smartListResponse(ctx, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch smart list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"smart_list": response})
+}
+
+func DeleteSmartList(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	listID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteSmartList(ctx, listID, userIDStr)
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Smart list not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Smart list deleted successfully"}))
+}
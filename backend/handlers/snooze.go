@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SnoozeTodoInput struct {
+	Until  *time.Time `json:"until"`
+	Preset string     `json:"preset" binding:"omitempty,oneof=later_today tomorrow next_week"`
+}
+
+// deferralResponse answers a snooze or someday change.
+func deferralResponse(ctx context.Context, c *gin.Context, todo *models.Todo, err error) {
+	if forbidden(c, err) {
+		return
+	}
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
+}
+
+func SnoozeTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	var input SnoozeTodoInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var preset *model.SnoozePreset
+	if input.Preset != "" {
+		p := model.SnoozePreset(strings.ToUpper(input.Preset))
+		preset = &p
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.SnoozeTodo(ctx, todoID, userIDStr, input.Until, preset)
+	deferralResponse(ctx, c, todo, err)
+}
+
+func UnsnoozeTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.UnsnoozeTodo(ctx, todoID, userIDStr)
+	deferralResponse(ctx, c, todo, err)
+}
+
+func MoveToSomeday(c *gin.Context) {
+	setSomeday(c, true)
+}
+
+func RemoveFromSomeday(c *gin.Context) {
+	setSomeday(c, false)
+}
+
+func setSomeday(c *gin.Context, someday bool) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.SetSomeday(ctx, todoID, userIDStr, someday)
+	deferralResponse(ctx, c, todo, err)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+	"todo-app/graph/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateStatusInput struct {
+	Name     string  `json:"name" binding:"required"`
+	Color    *string `json:"color"`
+	Position *int    `json:"position"`
+	IsDone   *bool   `json:"is_done"`
+	GroupID  *string `json:"group_id"`
+}
+
+type UpdateStatusInput struct {
+	Name     *string `json:"name"`
+	Color    *string `json:"color"`
+	Position *int    `json:"position"`
+	IsDone   *bool   `json:"is_done"`
+}
+
+func GetStatuses(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	var groupID *string
+	if gid := c.Query("group_id"); gid != "" {
+		groupID = &gid
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	statuses, err := GQLClient.GetStatuses(ctx, userIDStr, groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statuses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses})
+}
+
+func CreateStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	var input CreateStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	status, err := GQLClient.CreateStatus(ctx, userIDStr, model.CreateStatusInput{
+		Name:     input.Name,
+		Color:    input.Color,
+		Position: input.Position,
+		IsDone:   input.IsDone,
+		GroupID:  input.GroupID,
+	})
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create status"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": status})
+}
+
+func UpdateStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	statusID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input UpdateStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	status, err := GQLClient.UpdateStatus(ctx, statusID, userIDStr, model.UpdateStatusInput{
+		Name:     input.Name,
+		Color:    input.Color,
+		Position: input.Position,
+		IsDone:   input.IsDone,
+	})
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Status not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
+
+func DeleteStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	statusID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteStatus(ctx, statusID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Status not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Status deleted successfully"}))
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
+	"todo-app/recurrence"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateTodoInput struct {
+	Title          string   `json:"title" binding:"required"`
+	Description    *string  `json:"description"`
+	DueOffsetDays  *int     `json:"due_offset_days"`
+	RecurrenceRule *string  `json:"recurrence_rule"`
+	Checklist      []string `json:"checklist"`
+}
+
+type CreateTemplateInput struct {
+	Name             string               `json:"name" binding:"required"`
+	Description      *string              `json:"description"`
+	Shared           *bool                `json:"shared"`
+	GroupName        *string              `json:"group_name"`
+	GroupDescription *string              `json:"group_description"`
+	GroupColor       *string              `json:"group_color"`
+	Todos            []*TemplateTodoInput `json:"todos" binding:"dive"`
+}
+
+type UpdateTemplateInput struct {
+	Name             *string              `json:"name"`
+	Description      *string              `json:"description"`
+	Shared           *bool                `json:"shared"`
+	GroupName        *string              `json:"group_name"`
+	GroupDescription *string              `json:"group_description"`
+	GroupColor       *string              `json:"group_color"`
+	Todos            []*TemplateTodoInput `json:"todos" binding:"omitempty,dive"`
+}
+
+type SaveGroupAsTemplateInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type InstantiateTemplateInput struct {
+	Variables map[string]string `json:"variables"`
+	StartDate string            `json:"start_date"`
+}
+
+func templateTodoInputs(todos []*TemplateTodoInput) []*model.TemplateTodoInput {
+	if todos == nil {
+		return nil
+	}
+	inputs := make([]*model.TemplateTodoInput, len(todos))
+	for i, todo := range todos {
+		inputs[i] = &model.TemplateTodoInput{
+			Title:          todo.Title,
+			Description:    todo.Description,
+			DueOffsetDays:  todo.DueOffsetDays,
+			RecurrenceRule: todo.RecurrenceRule,
+			Checklist:      todo.Checklist,
+		}
+	}
+	return inputs
+}
+
+func templateJSON(template *models.Template) gin.H {
+	return gin.H{
+		"id":                template.ID,
+		"user_id":           template.UserID,
+		"name":              template.Name,
+		"description":       template.Description,
+		"shared":            template.Shared,
+		"group_name":        template.GroupName,
+		"group_description": template.GroupDescription,
+		"group_color":       template.GroupColor,
+		"todos":             template.Todos,
+		"variables":         GQLClient.TemplateVariables(template),
+		"created_at":        template.CreatedAt,
+		"updated_at":        template.UpdatedAt,
+	}
+}
+
+// templateError answers err from a template mutation and reports whether
+// there was one.
+func templateError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, graph.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, recurrence.ErrInvalidRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case invalidInput(c, err):
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	}
+	return true
+}
+
+func GetTemplates(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	templates, err := GQLClient.GetTemplates(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
+		return
+	}
+
+	result := make([]gin.H, len(templates))
+	for i, template := range templates {
+		result[i] = templateJSON(template)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": result})
+}
+
+func GetTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	templateID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	template, err := GQLClient.GetTemplate(ctx, templateID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": templateJSON(template)})
+}
+
+func CreateTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	var input CreateTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	template, err := GQLClient.CreateTemplate(ctx, userIDStr, model.CreateTemplateInput{
+		Name:             input.Name,
+		Description:      input.Description,
+		Shared:           input.Shared,
+		GroupName:        input.GroupName,
+		GroupDescription: input.GroupDescription,
+		GroupColor:       input.GroupColor,
+		Todos:            templateTodoInputs(input.Todos),
+	})
+	if templateError(c, err, "Failed to create template") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"template": templateJSON(template)})
+}
+
+func UpdateTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	templateID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input UpdateTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	template, err := GQLClient.UpdateTemplate(ctx, templateID, userIDStr, model.UpdateTemplateInput{
+		Name:             input.Name,
+		Description:      input.Description,
+		Shared:           input.Shared,
+		GroupName:        input.GroupName,
+		GroupDescription: input.GroupDescription,
+		GroupColor:       input.GroupColor,
+		Todos:            templateTodoInputs(input.Todos),
+	})
+	if templateError(c, err, "Template not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": templateJSON(template)})
+}
+
+func DeleteTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	templateID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteTemplate(ctx, templateID, userIDStr)
+	if templateError(c, err, "Template not found") {
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+func SaveGroupAsTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input SaveGroupAsTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	template, err := GQLClient.SaveGroupAsTemplate(ctx, groupID, userIDStr, input.Name)
+	if templateError(c, err, "Group not found") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"template": templateJSON(template)})
+}
+
+func InstantiateTemplate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	templateID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	var input InstantiateTemplateInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	instantiate := &model.InstantiateTemplateInput{}
+	for name, value := range input.Variables {
+		instantiate.Variables = append(instantiate.Variables, &model.TemplateVariable{Name: name, Value: value})
+	}
+	if input.StartDate != "" {
+		start, err := time.Parse(time.RFC3339, input.StartDate)
+		if err != nil {
+			start, err = time.Parse("2006-01-02", input.StartDate)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date: expected RFC 3339 time or YYYY-MM-DD date"})
+			return
+		}
+		instantiate.StartDate = &start
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	instance, err := GQLClient.InstantiateTemplate(ctx, templateID, userIDStr, instantiate)
+	if templateError(c, err, "Template not found") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, undoable(ctx, gin.H{"group": instance.Group, "todos": instance.Todos}))
+}
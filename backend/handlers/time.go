@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-app/graph"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StartTimerInput struct {
+	Note *string `json:"note"`
+}
+
+type AddTimeEntryInput struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Note      *string   `json:"note"`
+}
+
+type UpdateTimeEntryInput struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note"`
+}
+
+// timeEntrySeconds is the length of entry, measured up to now while it runs.
+func timeEntrySeconds(entry *models.TimeEntry) int {
+	end := time.Now()
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	return int(end.Sub(entry.StartedAt) / time.Second)
+}
+
+func timeEntryJSON(entry *models.TimeEntry) gin.H {
+	if entry == nil {
+		return nil
+	}
+	return gin.H{
+		"id":         entry.ID,
+		"todo_id":    entry.TodoID,
+		"user_id":    entry.UserID,
+		"started_at": entry.StartedAt,
+		"ended_at":   entry.EndedAt,
+		"running":    entry.EndedAt == nil,
+		"seconds":    timeEntrySeconds(entry),
+		"note":       entry.Note,
+		"created_at": entry.CreatedAt,
+		"updated_at": entry.UpdatedAt,
+	}
+}
+
+func timeEntriesJSON(entries []*models.TimeEntry) []gin.H {
+	result := make([]gin.H, len(entries))
+	for i, entry := range entries {
+		result[i] = timeEntryJSON(entry)
+	}
+	return result
+}
+
+// parseTimeRange reads the from and to query parameters. Each is an RFC 3339
+// time or a date, which is taken in the tz location; a date in to includes
+// the whole day.
+func parseTimeRange(c *gin.Context) (from, to *time.Time, err error) {
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, nil, fmt.Errorf("invalid tz: %q", tz)
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+		days int
+	}{
+		{"from", &from, 0},
+		{"to", &to, 1},
+	} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			*p.dst = &t
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: expected RFC 3339 time or YYYY-MM-DD date", p.name)
+		}
+		t = t.AddDate(0, 0, p.days)
+		*p.dst = &t
+	}
+
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, nil, fmt.Errorf("invalid range: to must be after from")
+	}
+	return from, to, nil
+}
+
+func StartTimer(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input StartTimerInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entry, err := GQLClient.StartTimer(ctx, todoID, userIDStr, input.Note)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"time_entry": timeEntryJSON(entry)})
+}
+
+func StopTimer(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entry, err := GQLClient.StopTimer(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop timer"})
+		return
+	}
+	if entry == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No timer is running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_entry": timeEntryJSON(entry)})
+}
+
+func GetRunningTimer(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entry, err := GQLClient.GetRunningTimer(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_entry": timeEntryJSON(entry)})
+}
+
+func GetTodoTimeEntries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	total, entries, err := GQLClient.GetTodoTime(ctx, todoID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_entries":     timeEntriesJSON(entries),
+		"tracked_seconds":  total.TrackedSeconds,
+		"estimate_minutes": total.EstimateMinutes,
+	})
+}
+
+func AddTimeEntry(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input AddTimeEntryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entry, err := GQLClient.AddTimeEntry(ctx, todoID, userIDStr, model.TimeEntryInput{
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+		Note:      input.Note,
+	})
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"time_entry": timeEntryJSON(entry)})
+}
+
+func UpdateTimeEntry(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	entryID := c.Param("id")
+	ctx := requestContext(c)
+
+	var input UpdateTimeEntryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entry, err := GQLClient.UpdateTimeEntry(ctx, entryID, userIDStr, model.UpdateTimeEntryInput{
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+		Note:      input.Note,
+	})
+	if errors.Is(err, graph.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_entry": timeEntryJSON(entry)})
+}
+
+func DeleteTimeEntry(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	entryID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	deleted, err := GQLClient.DeleteTimeEntry(ctx, entryID, userIDStr)
+	if err != nil || !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Time entry deleted successfully"}))
+}
+
+func GetTimeEntries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entries, err := GQLClient.GetTimeEntries(ctx, userIDStr, nil, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_entries": timeEntriesJSON(entries)})
+}
+
+// ExportTimeEntries writes the entries started in the requested range as CSV,
+// one row per entry with its todo's title and length in seconds and hours.
+func ExportTimeEntries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	entries, err := GQLClient.GetTimeEntries(ctx, userIDStr, nil, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+
+	titles := make(map[uint]string)
+	for _, entry := range entries {
+		if _, ok := titles[entry.TodoID]; ok {
+			continue
+		}
+		todo, err := GQLClient.GetTimeEntryTodo(ctx, entry)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+			return
+		}
+		if todo != nil {
+			titles[entry.TodoID] = todo.Title
+		}
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="time-entries.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "todo_id", "todo", "started_at", "ended_at", "seconds", "hours", "note"})
+	for _, entry := range entries {
+		endedAt := ""
+		if entry.EndedAt != nil {
+			endedAt = entry.EndedAt.UTC().Format(time.RFC3339)
+		}
+		seconds := timeEntrySeconds(entry)
+		w.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			strconv.FormatUint(uint64(entry.TodoID), 10),
+			titles[entry.TodoID],
+			entry.StartedAt.UTC().Format(time.RFC3339),
+			endedAt,
+			strconv.Itoa(seconds),
+			strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64),
+			entry.Note,
+		})
+	}
+	w.Flush()
+}
+
+func GetGroupTime(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	rollup, err := GQLClient.GetGroupTime(ctx, groupID, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	todos := make([]gin.H, len(rollup.Todos))
+	for i, item := range rollup.Todos {
+		todos[i] = gin.H{
+			"todo_id":          item.Todo.ID,
+			"title":            item.Todo.Title,
+			"tracked_seconds":  item.TrackedSeconds,
+			"estimate_minutes": item.EstimateMinutes,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_id":         rollup.GroupID,
+		"tracked_seconds":  rollup.TrackedSeconds,
+		"estimate_minutes": rollup.EstimateMinutes,
+		"todos":            todos,
+	})
+}
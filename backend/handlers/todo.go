@@ -1,32 +1,88 @@
 package handlers
 
 import (
-        "context"
+        "errors"
+        "fmt"
         "net/http"
         "strconv"
+        "strings"
+        "time"
+        "todo-app/graph"
+        "todo-app/graph/model"
+        "todo-app/models"
+        "todo-app/recurrence"
 
         "github.com/gin-gonic/gin"
 )
 
 type CreateTodoInput struct {
-        Title       string  `json:"title" binding:"required"`
-        Description string  `json:"description"`
-        GroupID     *string `json:"group_id"`
+        Title           string     `json:"title" binding:"required"`
+        Description     string     `json:"description"`
+        GroupID         *string    `json:"group_id"`
+        StatusID        *string    `json:"status_id"`
+        AssigneeID      *string    `json:"assignee_id"`
+        DueDate         *time.Time `json:"due_date"`
+        EstimateMinutes *int       `json:"estimate_minutes"`
+        Priority        *string    `json:"priority" binding:"omitempty,oneof=none low medium high"`
+        RecurrenceRule  *string    `json:"recurrence_rule"`
+        QuickAdd        bool       `json:"quick_add"`
+        TimeZone        *string    `json:"time_zone"`
 }
 
 type UpdateTodoInput struct {
-        Title       string  `json:"title"`
-        Description string  `json:"description"`
-        Completed   *bool   `json:"completed"`
-        GroupID     *string `json:"group_id"`
+        Title           string     `json:"title"`
+        Description     string     `json:"description"`
+        Completed       *bool      `json:"completed"`
+        StatusID        *string    `json:"status_id"`
+        GroupID         *string    `json:"group_id"`
+        AssigneeID      *string    `json:"assignee_id"`
+        DueDate         *time.Time `json:"due_date"`
+        EstimateMinutes *int       `json:"estimate_minutes"`
+        Priority        *string    `json:"priority" binding:"omitempty,oneof=none low medium high"`
+        RecurrenceRule  *string    `json:"recurrence_rule"`
+        Scope           string     `json:"scope" binding:"omitempty,oneof=this series"`
+}
+
+type ParseQuickAddInput struct {
+        Text     string  `json:"text" binding:"required"`
+        TimeZone *string `json:"time_zone"`
+}
+
+func priorityInput(priority *string) *model.Priority {
+        if priority == nil {
+                return nil
+        }
+        p := model.Priority(strings.ToUpper(*priority))
+        return &p
+}
+
+func quickAddJSON(parsed *model.QuickAddParse) gin.H {
+        return gin.H{
+                "title":           parsed.Title,
+                "due_date":        parsed.DueDate,
+                "due_date_text":   parsed.DueDateText,
+                "group":           parsed.Group,
+                "group_text":      parsed.GroupText,
+                "priority":        strings.ToLower(string(parsed.Priority)),
+                "priority_text":   parsed.PriorityText,
+                "recurrence_rule": parsed.RecurrenceRule,
+                "recurrence_text": parsed.RecurrenceText,
+                "time_zone":       parsed.TimeZone,
+        }
 }
 
 func GetTodos(c *gin.Context) {
         userID, _ := c.Get("user_id")
-        ctx := context.Background()
+        ctx := requestContext(c)
+
+        filter, sort, err := parseTodoQuery(c)
+        if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
 
         userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
-        todos, err := GQLClient.GetTodos(ctx, userIDStr)
+        todos, err := GQLClient.GetTodos(ctx, userIDStr, filter, sort)
         if err != nil {
                 c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch todos"})
                 return
@@ -38,7 +94,7 @@ func GetTodos(c *gin.Context) {
 func GetTodo(c *gin.Context) {
         userID, _ := c.Get("user_id")
         todoID := c.Param("id")
-        ctx := context.Background()
+        ctx := requestContext(c)
 
         userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
         todo, err := GQLClient.GetTodo(ctx, todoID, userIDStr)
@@ -52,7 +108,7 @@ func GetTodo(c *gin.Context) {
 
 func CreateTodo(c *gin.Context) {
         userID, _ := c.Get("user_id")
-        ctx := context.Background()
+        ctx := graph.WithUndo(requestContext(c))
 
         var input CreateTodoInput
         if err := c.ShouldBindJSON(&input); err != nil {
@@ -61,19 +117,74 @@ func CreateTodo(c *gin.Context) {
         }
 
         userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
-        todo, err := GQLClient.CreateTodo(ctx, userIDStr, input.Title, input.Description, input.GroupID)
+        create := model.CreateTodoInput{
+                Title:           input.Title,
+                Description:     input.Description,
+                GroupID:         input.GroupID,
+                StatusID:        input.StatusID,
+                AssigneeID:      input.AssigneeID,
+                DueDate:         input.DueDate,
+                EstimateMinutes: input.EstimateMinutes,
+                Priority:        priorityInput(input.Priority),
+                RecurrenceRule:  input.RecurrenceRule,
+                TimeZone:        input.TimeZone,
+        }
+
+        var todo *models.Todo
+        var parsed *model.QuickAddParse
+        var err error
+        if input.QuickAdd {
+                todo, parsed, err = GQLClient.QuickAddTodo(ctx, userIDStr, create)
+        } else {
+                todo, err = GQLClient.CreateTodo(ctx, userIDStr, create)
+        }
+        if forbidden(c, err) {
+                return
+        }
+        if errors.Is(err, recurrence.ErrInvalidRule) || errors.Is(err, graph.ErrInvalidInput) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
         if err != nil {
                 c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create todo"})
                 return
         }
 
-        c.JSON(http.StatusCreated, gin.H{"todo": todo})
+        response := gin.H{"todo": todo}
+        if parsed != nil {
+                response["parsed"] = quickAddJSON(parsed)
+        }
+        c.JSON(http.StatusCreated, undoable(ctx, response))
+}
+
+func ParseQuickAdd(c *gin.Context) {
+        userID, _ := c.Get("user_id")
+        ctx := requestContext(c)
+
+        var input ParseQuickAddInput
+        if err := c.ShouldBindJSON(&input); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
+
+        userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+        parsed, err := GQLClient.ParseQuickAdd(ctx, userIDStr, input.Text, input.TimeZone)
+        if errors.Is(err, graph.ErrInvalidInput) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
+        if err != nil {
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse text"})
+                return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"parsed": quickAddJSON(parsed)})
 }
 
 func UpdateTodo(c *gin.Context) {
         userID, _ := c.Get("user_id")
         todoID := c.Param("id")
-        ctx := context.Background()
+        ctx := graph.WithUndo(requestContext(c))
 
         var input UpdateTodoInput
         if err := c.ShouldBindJSON(&input); err != nil {
@@ -91,26 +202,218 @@ func UpdateTodo(c *gin.Context) {
                 description = &input.Description
         }
 
-        todo, err := GQLClient.UpdateTodo(ctx, todoID, userIDStr, title, description, input.Completed, input.GroupID)
+        var scope *model.EditScope
+        if input.Scope == "series" {
+                series := model.EditScopeSeries
+                scope = &series
+        }
+
+        todo, err := GQLClient.UpdateTodo(ctx, todoID, userIDStr, model.UpdateTodoInput{
+                Title:           title,
+                Description:     description,
+                Completed:       input.Completed,
+                StatusID:        input.StatusID,
+                GroupID:         input.GroupID,
+                AssigneeID:      input.AssigneeID,
+                DueDate:         input.DueDate,
+                EstimateMinutes: input.EstimateMinutes,
+                Priority:        priorityInput(input.Priority),
+                RecurrenceRule:  input.RecurrenceRule,
+                Scope:           scope,
+        })
+        if forbidden(c, err) {
+                return
+        }
+        if errors.Is(err, graph.ErrBlocked) {
+                c.JSON(http.StatusConflict, gin.H{"error": "Todo is blocked by open todos"})
+                return
+        }
+        if errors.Is(err, recurrence.ErrInvalidRule) || errors.Is(err, graph.ErrInvalidInput) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
         if err != nil {
                 c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
                 return
         }
 
-        c.JSON(http.StatusOK, gin.H{"todo": todo})
+        c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
 }
 
 func DeleteTodo(c *gin.Context) {
         userID, _ := c.Get("user_id")
         todoID := c.Param("id")
-        ctx := context.Background()
+        ctx := graph.WithUndo(requestContext(c))
 
         userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
         deleted, err := GQLClient.DeleteTodo(ctx, todoID, userIDStr)
+        if forbidden(c, err) {
+                return
+        }
         if err != nil || !deleted {
                 c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
                 return
         }
 
-        c.JSON(http.StatusOK, gin.H{"message": "Todo deleted successfully"})
+        c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Todo deleted successfully"}))
+}
+
+func SkipTodoOccurrence(c *gin.Context) {
+        userID, _ := c.Get("user_id")
+        todoID := c.Param("id")
+        ctx := graph.WithUndo(requestContext(c))
+
+        userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+        next, err := GQLClient.SkipTodoOccurrence(ctx, todoID, userIDStr)
+        if forbidden(c, err) {
+                return
+        }
+        if err != nil {
+                c.JSON(http.StatusNotFound, gin.H{"error": "Recurring todo not found"})
+                return
+        }
+
+        c.JSON(http.StatusOK, undoable(ctx, gin.H{"message": "Occurrence skipped", "next": next}))
+}
+
+func MoveTodo(c *gin.Context) {
+        userID, _ := c.Get("user_id")
+        todoID := c.Param("id")
+        ctx := graph.WithUndo(requestContext(c))
+
+        var input struct {
+                StatusID string `json:"status_id" binding:"required"`
+        }
+        if err := c.ShouldBindJSON(&input); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
+
+        userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+        todo, err := GQLClient.MoveTodo(ctx, todoID, userIDStr, input.StatusID)
+        if forbidden(c, err) {
+                return
+        }
+        if errors.Is(err, graph.ErrBlocked) {
+                c.JSON(http.StatusConflict, gin.H{"error": "Todo is blocked by open todos"})
+                return
+        }
+        if errors.Is(err, graph.ErrInvalidInput) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
+        if err != nil {
+                c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+                return
+        }
+
+        c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
+}
+
+var todoSortFields = map[string]model.TodoSortField{
+        "created_at":   model.TodoSortFieldCreatedAt,
+        "updated_at":   model.TodoSortFieldUpdatedAt,
+        "due_date":     model.TodoSortFieldDueDate,
+        "completed_at": model.TodoSortFieldCompletedAt,
+        "priority":     model.TodoSortFieldPriority,
+}
+
+// parseTodoQuery reads the listing filters and sort order from the query
+// string. completed_within (today, week or month) is resolved against the
+// time zone given in tz, defaulting to UTC; weeks start on Monday.
+func parseTodoQuery(c *gin.Context) (*model.TodoFilter, *model.TodoSort, error) {
+        filter := &model.TodoFilter{}
+
+        if v := c.Query("view"); v != "" {
+                view := model.TodoView(strings.ToUpper(v))
+                if !view.IsValid() {
+                        return nil, nil, fmt.Errorf("invalid view: %q", v)
+                }
+                filter.View = &view
+        }
+
+        if v := c.Query("completed"); v != "" {
+                completed, err := strconv.ParseBool(v)
+                if err != nil {
+                        return nil, nil, fmt.Errorf("invalid completed: %q", v)
+                }
+                filter.Completed = &completed
+        }
+
+        if v := c.Query("assigned_to_me"); v != "" {
+                assigned, err := strconv.ParseBool(v)
+                if err != nil {
+                        return nil, nil, fmt.Errorf("invalid assigned_to_me: %q", v)
+                }
+                filter.AssignedToMe = &assigned
+        }
+
+        if v := c.Query("group_id"); v != "" {
+                if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+                        return nil, nil, fmt.Errorf("invalid group_id: %q", v)
+                }
+                filter.GroupID = &v
+                if s := c.Query("subgroups"); s != "" {
+                        subgroups, err := strconv.ParseBool(s)
+                        if err != nil {
+                                return nil, nil, fmt.Errorf("invalid subgroups: %q", s)
+                        }
+                        filter.IncludeSubgroups = &subgroups
+                }
+        }
+
+        for _, p := range []struct {
+                name string
+                dst  **time.Time
+        }{
+                {"completed_after", &filter.CompletedAfter},
+                {"completed_before", &filter.CompletedBefore},
+        } {
+                if v := c.Query(p.name); v != "" {
+                        t, err := time.Parse(time.RFC3339, v)
+                        if err != nil {
+                                return nil, nil, fmt.Errorf("invalid %s: expected RFC 3339 time", p.name)
+                        }
+                        *p.dst = &t
+                }
+        }
+
+        if v := c.Query("completed_within"); v != "" {
+                loc := time.UTC
+                if tz := c.Query("tz"); tz != "" {
+                        l, err := time.LoadLocation(tz)
+                        if err != nil {
+                                return nil, nil, fmt.Errorf("invalid tz: %q", tz)
+                        }
+                        loc = l
+                }
+                now := time.Now().In(loc)
+                start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+                switch v {
+                case "today":
+                case "week":
+                        start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+                case "month":
+                        start = start.AddDate(0, 0, 1-start.Day())
+                default:
+                        return nil, nil, fmt.Errorf("invalid completed_within: %q", v)
+                }
+                filter.CompletedAfter = &start
+        }
+
+        var sort *model.TodoSort
+        if v := c.Query("sort"); v != "" {
+                direction := model.SortDirectionAsc
+                if strings.HasPrefix(v, "-") {
+                        direction = model.SortDirectionDesc
+                        v = v[1:]
+                }
+                field, ok := todoSortFields[v]
+                if !ok {
+                        return nil, nil, fmt.Errorf("invalid sort: %q", v)
+                }
+                sort = &model.TodoSort{Field: field, Direction: &direction}
+        }
+
+        return filter, sort, nil
 }
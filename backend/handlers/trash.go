@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"todo-app/graph"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The models hide deleted_at from JSON; the trash listing shows it.
+type trashedTodo struct {
+	*models.Todo
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type trashedGroup struct {
+	*models.Group
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func GetTrash(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	trash, err := GQLClient.GetTrash(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	todos := make([]trashedTodo, len(trash.Todos))
+	for i, todo := range trash.Todos {
+		todos[i] = trashedTodo{Todo: todo, DeletedAt: todo.DeletedAt.Time}
+	}
+	groups := make([]trashedGroup, len(trash.Groups))
+	for i, group := range trash.Groups {
+		groups[i] = trashedGroup{Group: group, DeletedAt: group.DeletedAt.Time}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"todos": todos, "groups": groups})
+}
+
+func RestoreTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	todo, err := GQLClient.RestoreTodo(ctx, todoID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"todo": todo}))
+}
+
+func RestoreGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := graph.WithUndo(requestContext(c))
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	group, err := GQLClient.RestoreGroup(ctx, groupID, userIDStr)
+	if forbidden(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, undoable(ctx, gin.H{"group": group}))
+}
+
+func PurgeTodo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	todoID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	purged, err := GQLClient.PurgeTodo(ctx, todoID, userIDStr)
+	if err != nil || !purged {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Todo permanently deleted"})
+}
+
+func PurgeGroup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	groupID := c.Param("id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	purged, err := GQLClient.PurgeGroup(ctx, groupID, userIDStr)
+	if err != nil || !purged {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Group permanently deleted"})
+}
+
+func EmptyTrash(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	count, err := GQLClient.EmptyTrash(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trash emptied", "deleted": count})
+}
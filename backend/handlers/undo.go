@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-app/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+// undoable adds the undo token of the operation run under ctx, if it
+// recorded anything, to a response body.
+func undoable(ctx context.Context, body gin.H) gin.H {
+	if token := graph.UndoToken(ctx); token != "" {
+		body["undo_token"] = token
+	}
+	return body
+}
+
+func Undo(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	token := c.Param("token")
+	ctx := requestContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	_, err := GQLClient.Undo(ctx, token, userIDStr)
+	if errors.Is(err, graph.ErrUndoExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": "Undo token has expired or was already used"})
+		return
+	}
+	if errors.Is(err, graph.ErrUndoConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot undo: affected items have changed since"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Undo token not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Operation undone"})
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/graph/model"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateWorkspaceInput struct {
+	Name                string   `json:"name" binding:"required"`
+	AllowedEmailDomains []string `json:"allowed_email_domains"`
+}
+
+type UpdateWorkspaceInput struct {
+	Name                *string  `json:"name"`
+	AllowedEmailDomains []string `json:"allowed_email_domains"`
+}
+
+type WorkspaceMemberInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=member admin"`
+}
+
+type WorkspaceRoleInput struct {
+	Role string `json:"role" binding:"required,oneof=member admin"`
+}
+
+type WorkspaceResponse struct {
+	ID                  uint      `json:"id"`
+	Name                string    `json:"name"`
+	AllowedEmailDomains []string  `json:"allowed_email_domains"`
+	Role                string    `json:"role,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type WorkspaceMemberResponse struct {
+	WorkspaceID uint      `json:"workspace_id"`
+	UserID      uint      `json:"user_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func workspaceResponse(ctx context.Context, workspace *models.Workspace, userID string) (WorkspaceResponse, error) {
+	role, err := GQLClient.WorkspaceRole(ctx, workspace, userID)
+	if err != nil {
+		return WorkspaceResponse{}, err
+	}
+	response := WorkspaceResponse{
+		ID:                  workspace.ID,
+		Name:                workspace.Name,
+		AllowedEmailDomains: workspace.AllowedEmailDomains,
+		CreatedAt:           workspace.CreatedAt,
+		UpdatedAt:           workspace.UpdatedAt,
+	}
+	if response.AllowedEmailDomains == nil {
+		response.AllowedEmailDomains = []string{}
+	}
+	if role != nil {
+		response.Role = strings.ToLower(string(*role))
+	}
+	return response, nil
+}
+
+func workspaceMemberResponse(ctx context.Context, member *models.WorkspaceMember) (WorkspaceMemberResponse, error) {
+	email, err := GQLClient.WorkspaceMemberEmail(ctx, member)
+	if err != nil {
+		return WorkspaceMemberResponse{}, err
+	}
+	return WorkspaceMemberResponse{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Email:       email,
+		Role:        member.Role,
+		CreatedAt:   member.CreatedAt,
+	}, nil
+}
+
+// GetWorkspaces lists the workspaces the user belongs to, along with the one
+// their token works in.
+func GetWorkspaces(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := accountContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	workspaces, err := GQLClient.GetWorkspaces(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspaces"})
+		return
+	}
+
+	response := make([]WorkspaceResponse, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		w, err := workspaceResponse(ctx, workspace, userIDStr)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspaces"})
+			return
+		}
+		response = append(response, w)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspaces": response, "current_workspace_id": c.GetUint("token_workspace_id")})
+}
+
+func GetWorkspace(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	ctx := accountContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	workspace, err := GQLClient.GetWorkspace(ctx, workspaceID, userIDStr)
+	if memberError(c, err, "Workspace not found") {
+		return
+	}
+
+	response, err := workspaceResponse(ctx, workspace, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspace": response})
+}
+
+func CreateWorkspace(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := accountContext(c)
+
+	var input CreateWorkspaceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	workspace, err := GQLClient.CreateWorkspace(ctx, userIDStr, input.Name, input.AllowedEmailDomains)
+	if memberError(c, err, "User not found") {
+		return
+	}
+
+	response, err := workspaceResponse(ctx, workspace, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"workspace": response})
+}
+
+// UpdateWorkspace changes a workspace's name and allowed email domains.
+// Sending allowed_email_domains as an empty list lifts the restriction.
+func UpdateWorkspace(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	ctx := accountContext(c)
+
+	var input UpdateWorkspaceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	workspace, err := GQLClient.UpdateWorkspace(ctx, workspaceID, userIDStr, input.Name, input.AllowedEmailDomains)
+	if memberError(c, err, "Workspace not found") {
+		return
+	}
+
+	response, err := workspaceResponse(ctx, workspace, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspace": response})
+}
+
+// SwitchWorkspace returns a token working in another workspace the user
+// belongs to.
+func SwitchWorkspace(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	ctx := accountContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	workspace, err := GQLClient.GetWorkspace(ctx, workspaceID, userIDStr)
+	if memberError(c, err, "Workspace not found") {
+		return
+	}
+	response, err := workspaceResponse(ctx, workspace, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace"})
+		return
+	}
+	user, err := GQLClient.GetUserByID(ctx, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	token, err := generateToken(*user, workspace.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "workspace": response})
+}
+
+func GetWorkspaceMembers(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	ctx := accountContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	members, err := GQLClient.GetWorkspaceMembers(ctx, workspaceID, userIDStr)
+	if memberError(c, err, "Workspace not found") {
+		return
+	}
+
+	response := make([]WorkspaceMemberResponse, 0, len(members))
+	for _, member := range members {
+		m, err := workspaceMemberResponse(ctx, member)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+			return
+		}
+		response = append(response, m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": response})
+}
+
+// AddWorkspaceMember adds an existing user to a workspace by email address,
+// subject to its allowed email domains.
+func AddWorkspaceMember(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	ctx := accountContext(c)
+
+	var input WorkspaceMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	member, err := GQLClient.AddWorkspaceMember(ctx, workspaceID, userIDStr, input.Email, model.WorkspaceRole(strings.ToUpper(input.Role)))
+	if memberError(c, err, "Workspace not found") {
+		return
+	}
+
+	response, err := workspaceMemberResponse(ctx, member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch member"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"member": response})
+}
+
+func UpdateWorkspaceMember(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	memberID := c.Param("userId")
+	ctx := accountContext(c)
+
+	var input WorkspaceRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	member, err := GQLClient.UpdateWorkspaceMember(ctx, workspaceID, memberID, userIDStr, model.WorkspaceRole(strings.ToUpper(input.Role)))
+	if memberError(c, err, "Member not found") {
+		return
+	}
+
+	response, err := workspaceMemberResponse(ctx, member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"member": response})
+}
+
+// RemoveWorkspaceMember removes someone from a workspace and from all its
+// groups. Members may remove themselves to leave.
+func RemoveWorkspaceMember(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	workspaceID := c.Param("id")
+	memberID := c.Param("userId")
+	ctx := accountContext(c)
+
+	userIDStr := strconv.FormatUint(uint64(userID.(uint)), 10)
+	removed, err := GQLClient.RemoveWorkspaceMember(ctx, workspaceID, memberID, userIDStr)
+	if memberError(c, err, "Member not found") {
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
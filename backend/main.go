@@ -1,12 +1,19 @@
 package main
 
 import (
+        "context"
         "log"
+        "os"
+        "time"
+        "todo-app/blobstore"
         "todo-app/config"
         "todo-app/graph"
         "todo-app/handlers"
         "todo-app/middleware"
         "todo-app/models"
+        "todo-app/notifier"
+        "todo-app/scheduler"
+        "todo-app/search"
 
         "github.com/gin-contrib/cors"
         "github.com/gin-gonic/gin"
@@ -15,22 +22,96 @@ import (
 func main() {
         config.ConnectDatabase()
 
-        if err := config.DB.AutoMigrate(&models.User{}, &models.Group{}, &models.Todo{}); err != nil {
+        if err := config.DB.AutoMigrate(
+                &models.User{},
+                &models.Group{},
+                &models.Todo{},
+                &models.Status{},
+                &models.Reminder{},
+                &models.Job{},
+                &models.Notification{},
+                &models.Revision{},
+                &models.UndoToken{},
+                &models.Comment{},
+                &models.Attachment{},
+                &models.Dependency{},
+                &models.TimeEntry{},
+                &models.Template{},
+                &models.TemplateTodo{},
+                &models.GroupMember{},
+                &models.GroupInvitation{},
+                &models.Workspace{},
+                &models.WorkspaceMember{},
+                &models.SmartList{},
+                &models.Pin{},
+        ); err != nil {
+                log.Fatal("Failed to migrate database:", err)
+        }
+        if err := search.Migrate(config.DB); err != nil {
+                log.Fatal("Failed to migrate database:", err)
+        }
+        if err := graph.MigrateGroupNames(config.DB); err != nil {
+                log.Fatal("Failed to migrate database:", err)
+        }
+        if err := graph.BackfillGroupOwners(config.DB); err != nil {
+                log.Fatal("Failed to migrate database:", err)
+        }
+        if err := graph.BackfillWorkspaces(config.DB); err != nil {
+                log.Fatal("Failed to migrate database:", err)
+        }
+        if err := graph.BackfillWorkspaceRecords(config.DB); err != nil {
                 log.Fatal("Failed to migrate database:", err)
         }
         log.Println("Database migrated successfully")
 
+        if err := models.RegisterWorkspaceScope(config.DB); err != nil {
+                log.Fatal("Failed to register workspace scope:", err)
+        }
+
         resolver := graph.NewResolver(config.DB)
+        if minutes := config.IntEnv("UNDO_WINDOW_MINUTES", 0); minutes > 0 {
+                resolver.UndoWindow = time.Duration(minutes) * time.Minute
+        }
+        blobs, err := blobstore.FromEnv()
+        if err != nil {
+                log.Fatal("Failed to configure attachment storage:", err)
+        }
+        resolver.Blobs = blobs
+        resolver.MaxAttachmentSize = int64(config.IntEnv("ATTACHMENT_MAX_MB", 10)) << 20
+        resolver.AttachmentQuota = int64(config.IntEnv("ATTACHMENT_QUOTA_MB", 100)) << 20
+        resolver.RefuseBlockedCompletion = os.Getenv("REFUSE_BLOCKED_COMPLETION") == "true"
         gqlClient := graph.NewClient(resolver)
         handlers.InitGraphQLClient(gqlClient)
         log.Println("GraphQL layer initialized successfully")
 
+        sched := scheduler.New(config.DB)
+        notifiers := notifier.FromEnv(config.DB)
+        sched.Handle(scheduler.KindReminder, scheduler.ReminderHandler(config.DB, notifiers))
+        sched.Handle(scheduler.KindGroupInvitation, scheduler.InvitationHandler(config.DB, notifiers))
+        sched.Handle(scheduler.KindTodoAssigned, scheduler.AssignmentHandler(config.DB, notifiers))
+        sched.Handle(scheduler.KindResurfaceSnoozed, scheduler.ResurfaceSnoozedHandler(config.DB, notifiers[notifier.ChannelInApp]))
+        sched.Every(scheduler.KindResurfaceSnoozed, time.Minute)
+        sched.Handle(scheduler.KindCleanupJobs, scheduler.CleanupJobsHandler(config.DB, 7*24*time.Hour))
+        sched.Every(scheduler.KindCleanupJobs, time.Hour)
+        if days := config.IntEnv("TRASH_RETENTION_DAYS", 30); days > 0 {
+                sched.Handle(scheduler.KindPurgeTrash, scheduler.PurgeTrashHandler(config.DB, time.Duration(days)*24*time.Hour))
+                sched.Every(scheduler.KindPurgeTrash, time.Hour)
+        }
+        revisionRetention := time.Duration(config.IntEnv("REVISION_RETENTION_DAYS", 90)) * 24 * time.Hour
+        sched.Handle(scheduler.KindPruneRevisions, scheduler.PruneRevisionsHandler(config.DB, revisionRetention, config.IntEnv("REVISION_MAX_PER_ENTITY", 50)))
+        sched.Every(scheduler.KindPruneRevisions, time.Hour)
+        sched.Handle(scheduler.KindCleanupUndoTokens, scheduler.CleanupUndoTokensHandler(config.DB, resolver.UndoWindow))
+        sched.Every(scheduler.KindCleanupUndoTokens, time.Hour)
+        sched.Handle(scheduler.KindCleanupAttachments, scheduler.CleanupAttachmentsHandler(config.DB, blobs, resolver.UndoWindow))
+        sched.Every(scheduler.KindCleanupAttachments, time.Hour)
+        go sched.Start(context.Background())
+
         r := gin.Default()
 
         r.Use(cors.New(cors.Config{
                 AllowOrigins:     []string{"*"},
                 AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-                AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+                AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.WorkspaceHeader},
                 ExposeHeaders:    []string{"Content-Length"},
                 AllowCredentials: true,
         }))
@@ -43,31 +124,175 @@ func main() {
                         auth.POST("/login", handlers.Login)
                 }
 
+                // Authorized by the signed URL rather than the JWT.
+                api.GET("/attachments/:id/download", handlers.DownloadAttachment)
+
+                // Account routes work outside of any one workspace.
+                account := api.Group("")
+                account.Use(middleware.AuthMiddleware())
+                {
+                        account.GET("/me", handlers.GetMe)
+                        account.PATCH("/me", handlers.UpdateMe)
+
+                        workspaces := account.Group("/workspaces")
+                        {
+                                workspaces.GET("", handlers.GetWorkspaces)
+                                workspaces.POST("", handlers.CreateWorkspace)
+                                workspaces.GET("/:id", handlers.GetWorkspace)
+                                workspaces.PUT("/:id", handlers.UpdateWorkspace)
+                                workspaces.POST("/:id/switch", handlers.SwitchWorkspace)
+                                workspaces.GET("/:id/members", handlers.GetWorkspaceMembers)
+                                workspaces.POST("/:id/members", handlers.AddWorkspaceMember)
+                                workspaces.PUT("/:id/members/:userId", handlers.UpdateWorkspaceMember)
+                                workspaces.DELETE("/:id/members/:userId", handlers.RemoveWorkspaceMember)
+                        }
+                }
+
                 protected := api.Group("")
-                protected.Use(middleware.AuthMiddleware())
+                protected.Use(middleware.AuthMiddleware(), middleware.WorkspaceMiddleware(config.DB))
                 {
-                        protected.GET("/me", handlers.GetMe)
+                        protected.GET("/search", handlers.Search)
 
                         todos := protected.Group("/todos")
                         {
                                 todos.GET("", handlers.GetTodos)
                                 todos.GET("/:id", handlers.GetTodo)
                                 todos.POST("", handlers.CreateTodo)
+                                todos.POST("/parse", handlers.ParseQuickAdd)
                                 todos.PUT("/:id", handlers.UpdateTodo)
                                 todos.DELETE("/:id", handlers.DeleteTodo)
+                                todos.POST("/:id/skip", handlers.SkipTodoOccurrence)
+                                todos.POST("/:id/status", handlers.MoveTodo)
+                                todos.POST("/:id/snooze", handlers.SnoozeTodo)
+                                todos.DELETE("/:id/snooze", handlers.UnsnoozeTodo)
+                                todos.POST("/:id/someday", handlers.MoveToSomeday)
+                                todos.DELETE("/:id/someday", handlers.RemoveFromSomeday)
+                                todos.POST("/:id/pin", handlers.PinTodo)
+                                todos.DELETE("/:id/pin", handlers.UnpinTodo)
+                                todos.GET("/:id/reminders", handlers.GetReminders)
+                                todos.POST("/:id/reminders", handlers.CreateReminder)
+                                todos.GET("/:id/dependencies", handlers.GetDependencies)
+                                todos.POST("/:id/dependencies", handlers.AddDependency)
+                                todos.DELETE("/:id/dependencies/:blockerId", handlers.RemoveDependency)
+                                todos.POST("/:id/timer", handlers.StartTimer)
+                                todos.GET("/:id/time-entries", handlers.GetTodoTimeEntries)
+                                todos.POST("/:id/time-entries", handlers.AddTimeEntry)
+                                todos.GET("/:id/attachments", handlers.GetAttachments)
+                                todos.POST("/:id/attachments", handlers.UploadAttachment)
+                                todos.GET("/:id/comments", handlers.GetComments)
+                                todos.POST("/:id/comments", handlers.AddComment)
+                                todos.GET("/:id/history", handlers.GetTodoHistory)
+                                todos.POST("/:id/history/:revisionId/revert", handlers.RevertTodo)
+                        }
+
+                        trash := protected.Group("/trash")
+                        {
+                                trash.GET("", handlers.GetTrash)
+                                trash.DELETE("", handlers.EmptyTrash)
+                                trash.POST("/todos/:id/restore", handlers.RestoreTodo)
+                                trash.DELETE("/todos/:id", handlers.PurgeTodo)
+                                trash.POST("/groups/:id/restore", handlers.RestoreGroup)
+                                trash.DELETE("/groups/:id", handlers.PurgeGroup)
+                        }
+
+                        statuses := protected.Group("/statuses")
+                        {
+                                statuses.GET("", handlers.GetStatuses)
+                                statuses.POST("", handlers.CreateStatus)
+                                statuses.PUT("/:id", handlers.UpdateStatus)
+                                statuses.DELETE("/:id", handlers.DeleteStatus)
+                        }
+
+                        protected.DELETE("/reminders/:id", handlers.DeleteReminder)
+
+                        protected.POST("/undo/:token", handlers.Undo)
+
+                        protected.DELETE("/attachments/:id", handlers.DeleteAttachment)
+
+                        templates := protected.Group("/templates")
+                        {
+                                templates.GET("", handlers.GetTemplates)
+                                templates.GET("/:id", handlers.GetTemplate)
+                                templates.POST("", handlers.CreateTemplate)
+                                templates.PUT("/:id", handlers.UpdateTemplate)
+                                templates.DELETE("/:id", handlers.DeleteTemplate)
+                                templates.POST("/:id/instantiate", handlers.InstantiateTemplate)
+                        }
+
+                        timer := protected.Group("/timer")
+                        {
+                                timer.GET("", handlers.GetRunningTimer)
+                                timer.DELETE("", handlers.StopTimer)
+                        }
+
+                        timeEntries := protected.Group("/time-entries")
+                        {
+                                timeEntries.GET("", handlers.GetTimeEntries)
+                                timeEntries.GET("/export", handlers.ExportTimeEntries)
+                                timeEntries.PUT("/:id", handlers.UpdateTimeEntry)
+                                timeEntries.DELETE("/:id", handlers.DeleteTimeEntry)
+                        }
+
+                        comments := protected.Group("/comments")
+                        {
+                                comments.PUT("/:id", handlers.UpdateComment)
+                                comments.DELETE("/:id", handlers.DeleteComment)
+                        }
+
+                        notifications := protected.Group("/notifications")
+                        {
+                                notifications.GET("", handlers.GetNotifications)
+                                notifications.POST("/:id/read", handlers.MarkNotificationRead)
                         }
 
                         groups := protected.Group("/groups")
                         {
                                 groups.GET("", handlers.GetGroups)
                                 groups.GET("/:id", handlers.GetGroup)
+                                groups.GET("/:id/todos", handlers.GetGroupTodos)
                                 groups.POST("", handlers.CreateGroup)
                                 groups.PUT("/:id", handlers.UpdateGroup)
                                 groups.DELETE("/:id", handlers.DeleteGroup)
+                                groups.POST("/:id/move", handlers.MoveGroup)
+                                groups.POST("/:id/archive", handlers.ArchiveGroup)
+                                groups.DELETE("/:id/archive", handlers.UnarchiveGroup)
+                                groups.POST("/:id/pin", handlers.PinGroup)
+                                groups.DELETE("/:id/pin", handlers.UnpinGroup)
+                                groups.GET("/:id/dependencies", handlers.GetDependencyGraph)
+                                groups.GET("/:id/time", handlers.GetGroupTime)
+                                groups.POST("/:id/template", handlers.SaveGroupAsTemplate)
+                                groups.GET("/:id/history", handlers.GetGroupHistory)
+                                groups.POST("/:id/history/:revisionId/revert", handlers.RevertGroup)
+                                groups.GET("/:id/members", handlers.GetGroupMembers)
+                                groups.PUT("/:id/members/:userId", handlers.UpdateGroupMember)
+                                groups.DELETE("/:id/members/:userId", handlers.RemoveGroupMember)
+                                groups.GET("/:id/invitations", handlers.GetGroupInvitations)
+                                groups.POST("/:id/invitations", handlers.InviteToGroup)
+                                groups.POST("/:id/transfer", handlers.TransferGroup)
+                        }
+
+                        protected.GET("/pins", handlers.GetPins)
+
+                        smartLists := protected.Group("/smart-lists")
+                        {
+                                smartLists.GET("", handlers.GetSmartLists)
+                                smartLists.GET("/:id", handlers.GetSmartList)
+                                smartLists.GET("/:id/todos", handlers.GetSmartListTodos)
+                                smartLists.POST("", handlers.CreateSmartList)
+                                smartLists.PUT("/:id", handlers.UpdateSmartList)
+                                smartLists.DELETE("/:id", handlers.DeleteSmartList)
+                        }
+
+                        invitations := protected.Group("/invitations")
+                        {
+                                invitations.GET("", handlers.GetInvitations)
+                                invitations.POST("/:id/accept", handlers.AcceptInvitation)
+                                invitations.POST("/:id/decline", handlers.DeclineInvitation)
+                                invitations.DELETE("/:id", handlers.RevokeInvitation)
                         }
 
+                        // Admins of the current workspace manage its users.
                         admin := protected.Group("/admin")
-                        admin.Use(middleware.AdminMiddleware())
                         {
                                 admin.GET("/users", handlers.GetAllUsers)
                                 admin.GET("/users/:id", handlers.GetUser)
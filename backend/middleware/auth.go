@@ -67,17 +67,8 @@ func AuthMiddleware() gin.HandlerFunc {
 
                 c.Set("user_id", userID)
                 c.Set("is_admin", isAdmin)
-                c.Next()
-        }
-}
-
-func AdminMiddleware() gin.HandlerFunc {
-        return func(c *gin.Context) {
-                isAdmin, exists := c.Get("is_admin")
-                if !exists || !isAdmin.(bool) {
-                        c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-                        c.Abort()
-                        return
+                if workspaceID, ok := claims["workspace_id"].(float64); ok {
+                        c.Set("token_workspace_id", uint(workspaceID))
                 }
                 c.Next()
         }
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WorkspaceHeader names the workspace a single request works in, overriding
+// the one in the token.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceMiddleware picks the workspace a request works in and sets
+// workspace_id and workspace_role. It is the one named by WorkspaceHeader,
+// which the user must be a member of, or else the one in their token,
+// falling back to the first they joined when they have since left it.
+func WorkspaceMiddleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+
+		var requested uint
+		header := c.GetHeader(WorkspaceHeader)
+		if header != "" {
+			id, err := strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+				c.Abort()
+				return
+			}
+			requested = uint(id)
+		} else {
+			requested = c.GetUint("token_workspace_id")
+		}
+
+		member, err := workspaceMember(db, userID, requested)
+		if err == nil && member == nil && header == "" && requested != 0 {
+			member, err = workspaceMember(db, userID, 0)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check workspace"})
+			c.Abort()
+			return
+		}
+		if member == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this workspace"})
+			c.Abort()
+			return
+		}
+
+		c.Set("workspace_id", member.WorkspaceID)
+		c.Set("workspace_role", member.Role)
+		c.Next()
+	}
+}
+
+// workspaceMember returns userID's membership of workspaceID, or of the
+// first workspace they joined when workspaceID is 0, and nil if there is
+// none.
+func workspaceMember(db *gorm.DB, userID, workspaceID uint) (*models.WorkspaceMember, error) {
+	query := models.AllWorkspaces(db).Where("user_id = ?", userID)
+	if workspaceID != 0 {
+		query = query.Where("workspace_id = ?", workspaceID)
+	}
+	var members []models.WorkspaceMember
+	if err := query.Order("id").Limit(1).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return &members[0], nil
+}
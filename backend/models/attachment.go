@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attachment is a file attached to a todo. Deleted attachments keep their
+// contents until their deletion can no longer be undone.
+type Attachment struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	TodoID      uint           `json:"todo_id" gorm:"not null;index"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"index"`
+	Filename    string         `json:"filename" gorm:"not null"`
+	ContentType string         `json:"content_type" gorm:"not null"`
+	Size        int64          `json:"size" gorm:"not null"`
+	StorageKey  string         `json:"-" gorm:"not null;uniqueIndex"`
+	CreatedAt   time.Time      `json:"created_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CommentKindComment  = "comment"
+	CommentKindActivity = "activity"
+)
+
+type Comment struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	TodoID      uint           `json:"todo_id" gorm:"not null;index"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"index"`
+	AuthorID    uint           `json:"author_id" gorm:"not null"`
+	Kind        string         `json:"kind" gorm:"not null;default:'comment'"`
+	Event       string         `json:"event,omitempty"`
+	Body        string         `json:"body" gorm:"type:text;not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Dependency struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TodoID      uint      `json:"todo_id" gorm:"not null;uniqueIndex:idx_dependencies_pair"`
+	BlockerID   uint      `json:"blocker_id" gorm:"not null;uniqueIndex:idx_dependencies_pair;index"`
+	WorkspaceID uint      `json:"workspace_id" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
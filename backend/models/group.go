@@ -12,6 +12,9 @@ type Group struct {
 	Description string         `json:"description"`
 	Color       string         `json:"color" gorm:"default:'#3B82F6'"`
 	UserID      uint           `json:"user_id" gorm:"not null"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"index"`
+	ParentID    *uint          `json:"parent_id" gorm:"index"`
+	ArchivedAt  *time.Time     `json:"archived_at" gorm:"index"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Group roles, from least to most privileged. Viewers can read the group and
+// its todos, editors can also change them, and the owner can additionally
+// manage the group itself and its members.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+type GroupMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	GroupID   uint      `json:"group_id" gorm:"not null;uniqueIndex:idx_group_members_group_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_group_members_group_user;index"`
+	Role      string    `json:"role" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GroupInvitation struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	GroupID     uint       `json:"group_id" gorm:"not null;index"`
+	Email       string     `json:"email" gorm:"not null;index"`
+	Role        string     `json:"role" gorm:"not null"`
+	InvitedBy   uint       `json:"invited_by" gorm:"not null"`
+	Status      string     `json:"status" gorm:"not null;default:'pending';index"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// RolesAtLeast returns minRole and every role above it.
+func RolesAtLeast(minRole string) []string {
+	switch minRole {
+	case RoleOwner:
+		return []string{RoleOwner}
+	case RoleEditor:
+		return []string{RoleEditor, RoleOwner}
+	}
+	return []string{RoleViewer, RoleEditor, RoleOwner}
+}
+
+// TodoAccess is a condition on the todos table matching the todos userID
+// holds at least minRole on: their own ungrouped todos and every todo in a
+// group they are a member of with a sufficient role.
+func TodoAccess(userID uint, minRole string) clause.Expr {
+	return clause.Expr{
+		SQL:  "((todos.group_id IS NULL AND todos.user_id = ?) OR todos.group_id IN (SELECT group_id FROM group_members WHERE user_id = ? AND role IN ?))",
+		Vars: []interface{}{userID, userID, RolesAtLeast(minRole)},
+	}
+}
+
+// GroupAccess is the same condition on the groups table.
+func GroupAccess(userID uint, minRole string) clause.Expr {
+	return clause.Expr{
+		SQL:  "groups.id IN (SELECT group_id FROM group_members WHERE user_id = ? AND role IN ?)",
+		Vars: []interface{}{userID, RolesAtLeast(minRole)},
+	}
+}
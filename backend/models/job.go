@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+type Job struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Kind        string     `json:"kind" gorm:"not null;index"`
+	Payload     string     `json:"payload"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"not null;default:'pending';index"`
+	Attempts    int        `json:"attempts" gorm:"default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"default:5"`
+	DedupeKey   *string    `json:"dedupe_key" gorm:"uniqueIndex"`
+	LockedBy    string     `json:"locked_by"`
+	LockedUntil *time.Time `json:"locked_until"`
+	LastError   string     `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
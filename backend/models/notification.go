@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TodoID    *uint      `json:"todo_id"`
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body"`
+	DedupeKey string     `json:"-" gorm:"uniqueIndex;not null"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pin marks a group or todo as a favorite of one user. Pins are kept apart
+// from what they pin, so every member of a shared group pins it on their own.
+// Position orders a user's pins of each entity type.
+type Pin struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_pins_user_entity"`
+	WorkspaceID uint      `json:"workspace_id" gorm:"index"`
+	EntityType  string    `json:"entity_type" gorm:"not null;uniqueIndex:idx_pins_user_entity"`
+	EntityID    uint      `json:"entity_id" gorm:"not null;uniqueIndex:idx_pins_user_entity;index"`
+	Position    int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PinnedFirst is an ORDER BY term putting the rows of table, whose records
+// are of entityType, that userID pinned first, by their pin position. It is
+// a plain string, unlike TodoAccess, so that gorm keeps it when later order
+// terms are added; entityType and table must not come from user input.
+func PinnedFirst(userID uint, entityType, table string) string {
+	return fmt.Sprintf("(SELECT pins.position FROM pins WHERE pins.user_id = %d AND pins.entity_type = '%s' AND pins.entity_id = %s.id) ASC NULLS LAST", userID, entityType, table)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Reminder struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	TodoID        uint           `json:"todo_id" gorm:"not null;index"`
+	UserID        uint           `json:"user_id" gorm:"not null"`
+	WorkspaceID   uint           `json:"workspace_id" gorm:"index"`
+	RemindAt      *time.Time     `json:"remind_at"`
+	OffsetMinutes *int           `json:"offset_minutes"`
+	Channel       string         `json:"channel" gorm:"not null;default:'in_app'"`
+	SentAt        *time.Time     `json:"sent_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// FireAt returns when the reminder should fire for todo: its absolute time,
+// or the todo's due date shifted by OffsetMinutes. It returns nil for a
+// relative reminder on a todo without a due date.
+func (r *Reminder) FireAt(todo *Todo) *time.Time {
+	if r.RemindAt != nil {
+		return r.RemindAt
+	}
+	if r.OffsetMinutes != nil && todo.DueDate != nil {
+		at := todo.DueDate.Add(time.Duration(*r.OffsetMinutes) * time.Minute)
+		return &at
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionRevert  = "revert"
+)
+
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// FieldChanges maps a field's column name to its change. It is stored as a
+// JSON document.
+type FieldChanges map[string]FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return fmt.Errorf("cannot scan %T into FieldChanges", value)
+}
+
+type Revision struct {
+	ID         uint         `json:"id" gorm:"primaryKey"`
+	EntityType string       `json:"entity_type" gorm:"not null;index:idx_revisions_entity"`
+	EntityID   uint         `json:"entity_id" gorm:"not null;index:idx_revisions_entity"`
+	ActorID    uint         `json:"actor_id" gorm:"not null"`
+	Action     string       `json:"action" gorm:"not null"`
+	Changes    FieldChanges `json:"changes" gorm:"type:text"`
+	Operation  string       `json:"-" gorm:"index"`
+	CreatedAt  time.Time    `json:"created_at" gorm:"index"`
+}
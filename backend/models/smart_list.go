@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Due date criteria of a smart list. Today, week and month match todos due
+// in that period of the list's time zone, weeks starting on Monday.
+const (
+	SmartListDueOverdue = "overdue"
+	SmartListDueToday   = "today"
+	SmartListDueWeek    = "week"
+	SmartListDueMonth   = "month"
+	SmartListDueNone    = "none"
+)
+
+// SmartListFilter is the saved query of a smart list, stored as JSON. Unset
+// criteria match every todo; set ones must all match.
+type SmartListFilter struct {
+	Completed        *bool  `json:"completed,omitempty"`
+	AssignedToMe     *bool  `json:"assigned_to_me,omitempty"`
+	MinPriority      *int   `json:"min_priority,omitempty"`
+	Due              string `json:"due,omitempty"`
+	GroupIDs         []uint `json:"group_ids,omitempty"`
+	IncludeSubgroups bool   `json:"include_subgroups,omitempty"`
+}
+
+func (f SmartListFilter) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (f *SmartListFilter) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = SmartListFilter{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+	return fmt.Errorf("cannot scan %T into SmartListFilter", value)
+}
+
+// SmartList is a saved filter that lists the matching todos its owner can
+// see, like a group that picks its own todos.
+type SmartList struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	UserID      uint            `json:"user_id" gorm:"not null;index"`
+	WorkspaceID uint            `json:"workspace_id" gorm:"index"`
+	Name        string          `json:"name" gorm:"not null"`
+	Filter      SmartListFilter `json:"filter" gorm:"type:text"`
+	TimeZone    string          `json:"time_zone" gorm:"not null;default:'UTC'"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Status struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Color       string         `json:"color" gorm:"default:'#6B7280'"`
+	Position    int            `json:"position" gorm:"default:0"`
+	IsDone      bool           `json:"is_done" gorm:"default:false"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"index"`
+	GroupID     *uint          `json:"group_id" gorm:"index"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
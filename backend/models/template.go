@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StringList is a list of strings stored as a JSON array.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = StringList{}
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+	return fmt.Errorf("cannot scan %T into StringList", value)
+}
+
+type Template struct {
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	UserID           uint           `json:"user_id" gorm:"not null;index"`
+	WorkspaceID      uint           `json:"workspace_id" gorm:"index"`
+	Name             string         `json:"name" gorm:"not null"`
+	Description      string         `json:"description"`
+	Shared           bool           `json:"shared" gorm:"default:false;index"`
+	GroupName        string         `json:"group_name"`
+	GroupDescription string         `json:"group_description"`
+	GroupColor       string         `json:"group_color"`
+	Todos            []TemplateTodo `json:"todos" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type TemplateTodo struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	TemplateID     uint       `json:"template_id" gorm:"not null;index"`
+	Position       int        `json:"position" gorm:"not null;default:0"`
+	Title          string     `json:"title" gorm:"not null"`
+	Description    string     `json:"description"`
+	DueOffsetDays  *int       `json:"due_offset_days"`
+	RecurrenceRule string     `json:"recurrence_rule"`
+	Checklist      StringList `json:"checklist" gorm:"type:text"`
+}
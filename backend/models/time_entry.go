@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type TimeEntry struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	TodoID      uint       `json:"todo_id" gorm:"not null;index"`
+	WorkspaceID uint       `json:"workspace_id" gorm:"index"`
+	UserID      uint       `json:"user_id" gorm:"not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null;index"`
+	EndedAt     *time.Time `json:"ended_at"`
+	Note        string     `json:"note"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -7,13 +7,26 @@ import (
 )
 
 type Todo struct {
-        ID          uint           `json:"id" gorm:"primaryKey"`
-        Title       string         `json:"title" gorm:"not null"`
-        Description string         `json:"description"`
-        Completed   bool           `json:"completed" gorm:"default:false"`
-        UserID      uint           `json:"user_id" gorm:"not null"`
-        GroupID     *uint          `json:"group_id"`
-        CreatedAt   time.Time      `json:"created_at"`
-        UpdatedAt   time.Time      `json:"updated_at"`
-        DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+        ID              uint           `json:"id" gorm:"primaryKey"`
+        Title           string         `json:"title" gorm:"not null"`
+        Description     string         `json:"description"`
+        Completed       bool           `json:"completed" gorm:"default:false"`
+        StatusID        *uint          `json:"status_id" gorm:"index"`
+        CompletedAt     *time.Time     `json:"completed_at" gorm:"index"`
+        CompletedBy     *uint          `json:"completed_by"`
+        UserID          uint           `json:"user_id" gorm:"not null"`
+        WorkspaceID     uint           `json:"workspace_id" gorm:"index"`
+        GroupID         *uint          `json:"group_id"`
+        AssigneeID      *uint          `json:"assignee_id" gorm:"index"`
+        DueDate         *time.Time     `json:"due_date"`
+        EstimateMinutes *int           `json:"estimate_minutes"`
+        Priority        int            `json:"priority" gorm:"not null;default:0"`
+        SnoozedUntil    *time.Time     `json:"snoozed_until" gorm:"index"`
+        Someday         bool           `json:"someday" gorm:"not null;default:false"`
+        RecurrenceRule  string         `json:"recurrence_rule"`
+        SeriesID        *uint          `json:"series_id" gorm:"index"`
+        Occurrence      int            `json:"occurrence" gorm:"default:0"`
+        CreatedAt       time.Time      `json:"created_at"`
+        UpdatedAt       time.Time      `json:"updated_at"`
+        DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type UndoToken struct {
+	Token     string     `json:"token" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	UndoneAt  *time.Time `json:"undone_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Workspace roles. Admins manage the workspace's settings and members and
+// may share templates and take over groups within it.
+const (
+	WorkspaceRoleMember = "member"
+	WorkspaceRoleAdmin  = "admin"
+)
+
+// Workspace owns groups, todos and templates. Users only ever see the
+// contents of the workspace they are working in.
+type Workspace struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	Name                string     `json:"name" gorm:"not null"`
+	AllowedEmailDomains StringList `json:"allowed_email_domains" gorm:"type:text"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type WorkspaceMember struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint      `json:"workspace_id" gorm:"not null;uniqueIndex:idx_workspace_members_workspace_user"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_workspace_members_workspace_user;index"`
+	Role        string    `json:"role" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AllowsEmail reports whether someone with email may join w: anyone when it
+// has no allowed domains, otherwise only addresses at one of them.
+func (w *Workspace) AllowsEmail(email string) bool {
+	if len(w.AllowedEmailDomains) == 0 {
+		return true
+	}
+	return w.MatchesEmail(email)
+}
+
+// MatchesEmail reports whether email is at one of w's allowed domains.
+func (w *Workspace) MatchesEmail(email string) bool {
+	domain := EmailDomain(email)
+	for _, allowed := range w.AllowedEmailDomains {
+		if strings.EqualFold(allowed, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailDomain returns the lower-cased domain part of email.
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+type workspaceKey struct{}
+
+// ErrNoWorkspace fails database calls on records that belong to a workspace
+// made with a context that names none, so that a code path missing its
+// workspace cannot see or create records of every workspace. Calls that must
+// reach across workspaces say so with AllWorkspaces.
+var ErrNoWorkspace = errors.New("no workspace in context")
+
+// WithWorkspace returns a copy of ctx that scopes database calls made with
+// it to workspaceID. A zero workspaceID lifts the scope; see AllWorkspaces.
+func WithWorkspace(ctx context.Context, workspaceID uint) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WorkspaceFromContext returns the workspace ctx is scoped to, if any.
+func WorkspaceFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(workspaceKey{}).(uint)
+	return id, ok && id != 0
+}
+
+// AllWorkspaces returns db without its workspace scope, for the calls that
+// must reach across workspaces, e.g. accepting an invitation to a group in
+// another one, background jobs and migrations. Records created with it must
+// name their workspace.
+func AllWorkspaces(db *gorm.DB) *gorm.DB {
+	return db.WithContext(WithWorkspace(db.Statement.Context, 0))
+}
+
+// RegisterWorkspaceScope makes every query, update and delete on a model
+// with a WorkspaceID field made with a workspace-scoped context match only
+// that workspace's rows, and fills in the WorkspaceID of records created
+// with one. Such calls fail with ErrNoWorkspace when their context names no
+// workspace and is not from AllWorkspaces, as do creates of records without
+// a workspace. Raw SQL is not scoped.
+func RegisterWorkspaceScope(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("workspace:assign", assignWorkspace); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("workspace:scope", scopeWorkspace); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("workspace:scope", scopeWorkspace); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("workspace:scope", scopeWorkspace); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("workspace:scope", scopeWorkspace)
+}
+
+// contextWorkspace returns the workspace ctx is scoped to, 0 for
+// AllWorkspaces, and whether ctx says either.
+func contextWorkspace(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(workspaceKey{}).(uint)
+	return id, ok
+}
+
+func scopeWorkspace(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Statement.Schema.LookUpField("WorkspaceID") == nil {
+		return
+	}
+	workspaceID, ok := contextWorkspace(db.Statement.Context)
+	if !ok {
+		db.AddError(ErrNoWorkspace)
+		return
+	}
+	if workspaceID == 0 {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "workspace_id"}, Value: workspaceID},
+	}})
+}
+
+func assignWorkspace(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField("WorkspaceID")
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	workspaceID, _ := contextWorkspace(ctx)
+	assign := func(record reflect.Value) {
+		if _, zero := field.ValueOf(ctx, record); !zero {
+			return
+		}
+		if workspaceID == 0 {
+			db.AddError(ErrNoWorkspace)
+			return
+		}
+		db.AddError(field.Set(ctx, record, workspaceID))
+	}
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			assign(rv.Index(i))
+		}
+	case reflect.Struct:
+		assign(rv)
+	}
+}
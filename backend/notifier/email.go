@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Email sends notifications through an SMTP relay. The message key is sent
+// as the Message-ID so that mail systems can discard redelivered copies.
+type Email struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewEmail(host, port, username, password, from string) *Email {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &Email{addr: net.JoinHostPort(host, port), auth: auth, from: from}
+}
+
+func (n *Email) Notify(ctx context.Context, msg Message) error {
+	if msg.Email == "" {
+		return fmt.Errorf("no email address for user %d", msg.UserID)
+	}
+
+	domain := "todo-app"
+	if at := strings.LastIndex(n.from, "@"); at >= 0 {
+		domain = n.from[at+1:]
+	}
+
+	body := strings.Join([]string{
+		"From: " + headerValue(n.from),
+		"To: " + headerValue(msg.Email),
+		"Subject: " + mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)),
+		"Message-ID: <" + headerValue(msg.Key) + "@" + headerValue(domain) + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.Email}, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// headerValue flattens s onto one line so that text from todos and users
+// cannot end a header early and inject headers of its own.
+func headerValue(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
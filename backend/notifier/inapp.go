@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InApp stores notifications in the database for the client to list.
+type InApp struct {
+	db *gorm.DB
+}
+
+func NewInApp(db *gorm.DB) *InApp {
+	return &InApp{db: db}
+}
+
+func (n *InApp) Notify(ctx context.Context, msg Message) error {
+	notification := &models.Notification{
+		UserID:    msg.UserID,
+		Title:     msg.Subject,
+		Body:      msg.Body,
+		DedupeKey: msg.Key,
+	}
+	if msg.TodoID != 0 {
+		todoID := msg.TodoID
+		notification.TodoID = &todoID
+	}
+
+	err := n.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedupe_key"}}, DoNothing: true}).
+		Create(notification).Error
+	if err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Package notifier delivers reminder notifications over pluggable channels.
+//
+// Delivery is at-least-once, so every Message carries a Key that stays the
+// same across retries. Notifiers pass it on (as a header, a unique column,
+// ...) so that receivers can drop duplicates.
+package notifier
+
+import (
+	"context"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInApp   = "in_app"
+)
+
+type Message struct {
+	Key     string `json:"key"`
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	TodoID  uint   `json:"todo_id"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Registry maps a channel name to the notifier delivering on it.
+type Registry map[string]Notifier
+
+// FromEnv builds a registry from the environment. In-app notifications are
+// always available; email requires SMTP_HOST and webhooks WEBHOOK_URL.
+func FromEnv(db *gorm.DB) Registry {
+	registry := Registry{
+		ChannelInApp: NewInApp(db),
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		registry[ChannelEmail] = NewEmail(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	}
+
+	if url := os.Getenv("WEBHOOK_URL"); url != "" {
+		registry[ChannelWebhook] = NewWebhook(url, os.Getenv("WEBHOOK_SECRET"))
+	}
+
+	return registry
+}
+
+// IsChannel reports whether name is a known channel, configured or not.
+func IsChannel(name string) bool {
+	switch name {
+	case ChannelEmail, ChannelWebhook, ChannelInApp:
+		return true
+	}
+	return false
+}
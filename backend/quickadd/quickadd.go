@@ -0,0 +1,565 @@
+// Package quickadd parses free-form todo text such as
+// "Pay rent tomorrow 9am #finance !high" or "明日9時に家賃を払う #finance"
+// into a title and structured fields.
+//
+// Recognised, in English and Japanese:
+//   - dates: today, tomorrow, weekdays, "next week", "in 3 days",
+//     "Jan 5", 2024-01-05, 今日, 明日, 明後日, 来週金曜, 3日後, 1月5日
+//   - times: 9am, 9:30pm, 21:00, noon, 午後3時, 9時半
+//   - recurrence: daily, every 2 weeks, every monday, every weekday, 毎日,
+//     毎週月曜, 平日, 2週間ごと
+//   - #name references (groups) and priority markers (!high, !1, !!!, !高)
+//
+// Matched phrases are removed from the title; everything else is kept as is.
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
+// DefaultHour and DefaultMinute are the time of day given to due dates that
+// were written without one.
+const (
+	DefaultHour   = 23
+	DefaultMinute = 59
+)
+
+type Result struct {
+	Title          string
+	Due            *time.Time
+	DueText        string
+	Refs           []string
+	Priority       string
+	PriorityText   string
+	Recurrence     string
+	RecurrenceText string
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type clock struct {
+	hour, minute int
+}
+
+type parser struct {
+	now     time.Time
+	text    string
+	date    *date
+	clock   *clock
+	byDay   []time.Weekday
+	matched []string
+}
+
+type rule struct {
+	re    *regexp.Regexp
+	apply func(p *parser, m []string) bool
+}
+
+// Parse extracts the structured fields from text. Relative phrases are
+// resolved against now, in now's location. isRef decides which #name tokens
+// are references; the others, such as "#123", stay in the title. A nil isRef
+// accepts every name.
+func Parse(text string, now time.Time, isRef func(name string) bool) *Result {
+	p := &parser{now: now, text: normalize(text)}
+	result := &Result{}
+
+	var kept []string
+	for _, token := range strings.Fields(p.text) {
+		switch {
+		case len(token) > 1 && token[0] == '#' && (isRef == nil || isRef(token[1:])):
+			result.Refs = append(result.Refs, token[1:])
+		case result.Priority == "" && priorityOf(token) != "":
+			result.Priority = priorityOf(token)
+			result.PriorityText = token
+		default:
+			kept = append(kept, token)
+		}
+	}
+	p.text = " " + strings.Join(kept, " ") + " "
+
+	if text, ok := p.first(recurrenceRules, func(m []string) { result.Recurrence = m[0] }); ok {
+		result.RecurrenceText = text
+	}
+	p.first(dateRules, nil)
+	p.first(timeRules, nil)
+
+	if p.date == nil && len(p.byDay) > 0 {
+		// A recurrence on given weekdays starts on the next of them.
+		d := p.nextWeekday(p.byDay, true)
+		p.date = &d
+		if !p.due().After(p.now) {
+			d = p.nextWeekday(p.byDay, false)
+		}
+	}
+	if p.date != nil || p.clock != nil {
+		due := p.due()
+		result.Due = &due
+		result.DueText = strings.Join(p.matched, " ")
+	}
+
+	result.Title = cleanTitle(p.text)
+	return result
+}
+
+// first applies the first rule in rules that matches, removing the matched
+// phrase from the text, and returns that phrase.
+func (p *parser) first(rules []rule, matched func([]string)) (string, bool) {
+	for _, r := range rules {
+		loc := r.re.FindStringSubmatchIndex(p.text)
+		if loc == nil {
+			continue
+		}
+		m := make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = p.text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		phrase := strings.TrimSpace(m[0])
+		if !r.apply(p, m) {
+			continue
+		}
+		p.text = p.text[:loc[0]] + " " + p.text[loc[1]:]
+		if matched != nil {
+			matched(m)
+		} else {
+			p.matched = append(p.matched, phrase)
+		}
+		return phrase, true
+	}
+	return "", false
+}
+
+func (p *parser) today() date {
+	y, m, d := p.now.Date()
+	return date{y, m, d}
+}
+
+func (p *parser) addDays(days int) date {
+	y, m, d := p.now.AddDate(0, 0, days).Date()
+	return date{y, m, d}
+}
+
+// nextWeekday returns the first day after today, or from today when
+// inclusive, that falls on one of days.
+func (p *parser) nextWeekday(days []time.Weekday, inclusive bool) date {
+	start := 1
+	if inclusive {
+		start = 0
+	}
+	for i := start; i < start+7; i++ {
+		day := p.now.AddDate(0, 0, i)
+		for _, wd := range days {
+			if day.Weekday() == wd {
+				return p.addDays(i)
+			}
+		}
+	}
+	return p.today()
+}
+
+// mondayAfter returns the Monday of the week after the current one.
+func (p *parser) mondayAfter() date {
+	return p.addDays(7 - (int(p.now.Weekday())+6)%7)
+}
+
+// calendarDate returns month/day in the given year, or when year is zero
+// the next such day that is not in the past.
+func (p *parser) calendarDate(year int, month time.Month, day int) (date, bool) {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return date{}, false
+	}
+	y := year
+	if y == 0 {
+		y = p.now.Year()
+		if time.Date(y, month, day, 0, 0, 0, 0, p.now.Location()).Before(time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())) {
+			y++
+		}
+	}
+	t := time.Date(y, month, day, 0, 0, 0, 0, p.now.Location())
+	if t.Month() != month {
+		return date{}, false
+	}
+	return date{y, month, day}, true
+}
+
+func (p *parser) setDate(d date) bool {
+	p.date = &d
+	return true
+}
+
+func (p *parser) setClock(hour, minute int) bool {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return false
+	}
+	p.clock = &clock{hour, minute}
+	return true
+}
+
+func (p *parser) due() time.Time {
+	loc := p.now.Location()
+	if p.date == nil {
+		// A time on its own means the next time the clock shows it.
+		t := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), p.clock.hour, p.clock.minute, 0, 0, loc)
+		if !t.After(p.now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+	c := clock{DefaultHour, DefaultMinute}
+	if p.clock != nil {
+		c = *p.clock
+	}
+	return time.Date(p.date.year, p.date.month, p.date.day, c.hour, c.minute, 0, 0, loc)
+}
+
+// normalize folds full-width digits and punctuation that Japanese input
+// methods produce into their ASCII forms.
+func normalize(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return '0' + (r - '０')
+		case r == '！':
+			return '!'
+		case r == '＃':
+			return '#'
+		case r == '：':
+			return ':'
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, text)
+}
+
+func priorityOf(token string) string {
+	switch strings.ToLower(token) {
+	case "!high", "!h", "!1", "!!!", "!urgent", "!高":
+		return PriorityHigh
+	case "!medium", "!med", "!m", "!2", "!!", "!中":
+		return PriorityMedium
+	case "!low", "!l", "!3", "!", "!低":
+		return PriorityLow
+	}
+	return ""
+}
+
+var (
+	danglingWords = regexp.MustCompile(`(?i)(^|\s)(on|at|by|due|every)(\s*)$`)
+	spaces        = regexp.MustCompile(`\s+`)
+)
+
+// cleanTitle collapses the gaps left by removed phrases and drops
+// prepositions left dangling at the end.
+func cleanTitle(text string) string {
+	title := strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+	for {
+		trimmed := strings.TrimSpace(danglingWords.ReplaceAllString(title, ""))
+		if trimmed == title {
+			break
+		}
+		title = trimmed
+	}
+	title = strings.TrimRight(title, " ,、")
+	return strings.TrimLeft(title, " ,、")
+}
+
+var englishWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var japaneseWeekdays = map[string]time.Weekday{
+	"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+	"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var englishMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+const (
+	weekdayPattern = `(sun|mon|tue|wed|thu|fri|sat)(?:day|sday|nesday|rsday|urday|s)?`
+	monthPattern   = `(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?`
+)
+
+func englishWeekday(s string) time.Weekday {
+	return englishWeekdays[strings.ToLower(s)[:3]]
+}
+
+func amount(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return numberWords[strings.ToLower(s)]
+}
+
+// unitFrequency maps a unit word to an RRULE frequency.
+func unitFrequency(unit string) string {
+	switch strings.ToLower(strings.TrimSuffix(strings.ToLower(unit), "s")) {
+	case "day", "日":
+		return "DAILY"
+	case "week", "週", "週間":
+		return "WEEKLY"
+	case "month", "か月", "ヶ月", "ヵ月", "カ月", "ケ月", "月":
+		return "MONTHLY"
+	case "year", "年":
+		return "YEARLY"
+	}
+	return ""
+}
+
+func recurrence(freq string, interval int, days []time.Weekday) string {
+	rule := "FREQ=" + freq
+	if interval > 1 {
+		rule += ";INTERVAL=" + strconv.Itoa(interval)
+	}
+	if len(days) > 0 {
+		codes := make([]string, len(days))
+		for i, d := range days {
+			codes[i] = weekdayCodes[d]
+		}
+		rule += ";BYDAY=" + strings.Join(codes, ",")
+	}
+	return rule
+}
+
+var weekdaysMonToFri = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// Recurrence rules replace m[0] with the RRULE they produce.
+var recurrenceRules = []rule{
+	{regexp.MustCompile(`(?i)\b(?:every|each)\s+weekday\b|毎?平日`), func(p *parser, m []string) bool {
+		p.byDay = weekdaysMonToFri
+		m[0] = recurrence("WEEKLY", 1, weekdaysMonToFri)
+		return true
+	}},
+	{regexp.MustCompile(`(?i)\bevery\s+` + weekdayPattern + `((?:\s*(?:,|and)\s*` + weekdayPattern + `)*)\b`), func(p *parser, m []string) bool {
+		days := []time.Weekday{englishWeekday(m[1])}
+		for _, extra := range regexp.MustCompile(`(?i)`+weekdayPattern).FindAllStringSubmatch(m[2], -1) {
+			days = append(days, englishWeekday(extra[1]))
+		}
+		p.byDay = days
+		m[0] = recurrence("WEEKLY", 1, days)
+		return true
+	}},
+	{regexp.MustCompile(`毎週([日月火水木金土])曜日?`), func(p *parser, m []string) bool {
+		p.byDay = []time.Weekday{japaneseWeekdays[m[1]]}
+		m[0] = recurrence("WEEKLY", 1, p.byDay)
+		return true
+	}},
+	{regexp.MustCompile(`(?i)\bevery\s+(\d+|other)\s+(day|week|month|year)s?\b`), func(p *parser, m []string) bool {
+		n := 2
+		if !strings.EqualFold(m[1], "other") {
+			n = amount(m[1])
+		}
+		if n < 1 {
+			return false
+		}
+		m[0] = recurrence(unitFrequency(m[2]), n, nil)
+		return true
+	}},
+	{regexp.MustCompile(`(\d+)(日|週間|週|か月|ヶ月|ヵ月|カ月|ケ月|年)(?:ごと|毎)に?`), func(p *parser, m []string) bool {
+		n := amount(m[1])
+		if n < 1 {
+			return false
+		}
+		m[0] = recurrence(unitFrequency(m[2]), n, nil)
+		return true
+	}},
+	{regexp.MustCompile(`(?i)\b(?:every|each)\s+(day|week|month|year)\b|\b(daily|weekly|monthly|yearly|annually)\b`), func(p *parser, m []string) bool {
+		switch strings.ToLower(m[1] + m[2]) {
+		case "day", "daily":
+			m[0] = recurrence("DAILY", 1, nil)
+		case "week", "weekly":
+			m[0] = recurrence("WEEKLY", 1, nil)
+		case "month", "monthly":
+			m[0] = recurrence("MONTHLY", 1, nil)
+		default:
+			m[0] = recurrence("YEARLY", 1, nil)
+		}
+		return true
+	}},
+	{regexp.MustCompile(`毎(日|週|月|年)`), func(p *parser, m []string) bool {
+		m[0] = recurrence(unitFrequency(m[1]), 1, nil)
+		return true
+	}},
+}
+
+// jaParticle swallows the particles that follow a Japanese date or time,
+// as in 明日までに or 9時に.
+const jaParticle = `(?:までに|まで|に|の)?`
+
+var dateRules = []rule{
+	{regexp.MustCompile(`(?i)\b(?:on\s+|by\s+|due\s+)?(\d{4})[-/](\d{1,2})[-/](\d{1,2})\b`), func(p *parser, m []string) bool {
+		y, _ := strconv.Atoi(m[1])
+		mo, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		day, ok := p.calendarDate(y, time.Month(mo), d)
+		return ok && p.setDate(day)
+	}},
+	{regexp.MustCompile(`(?:(\d{4})年)?(\d{1,2})月(\d{1,2})日` + jaParticle), func(p *parser, m []string) bool {
+		y, _ := strconv.Atoi(m[1])
+		mo, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		day, ok := p.calendarDate(y, time.Month(mo), d)
+		return ok && p.setDate(day)
+	}},
+	{regexp.MustCompile(`(?i)\b(?:on\s+|by\s+|due\s+)?` + monthPattern + `\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?\b`), func(p *parser, m []string) bool {
+		d, _ := strconv.Atoi(m[2])
+		y, _ := strconv.Atoi(m[3])
+		day, ok := p.calendarDate(y, englishMonths[strings.ToLower(m[1])], d)
+		return ok && p.setDate(day)
+	}},
+	{regexp.MustCompile(`(?i)\b(?:on\s+|by\s+|due\s+)?(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthPattern + `(?:,?\s+(\d{4}))?\b`), func(p *parser, m []string) bool {
+		d, _ := strconv.Atoi(m[1])
+		y, _ := strconv.Atoi(m[3])
+		day, ok := p.calendarDate(y, englishMonths[strings.ToLower(m[2])], d)
+		return ok && p.setDate(day)
+	}},
+	{regexp.MustCompile(`(?i)\b(?:by\s+|due\s+)?(?:the\s+)?day\s+after\s+tomorrow\b|明後日` + jaParticle + `|あさって` + jaParticle), func(p *parser, m []string) bool {
+		return p.setDate(p.addDays(2))
+	}},
+	{regexp.MustCompile(`(?i)\b(?:by\s+|due\s+)?(tomorrow|tmrw|tmr)\b|(明日|あした|あす)` + jaParticle), func(p *parser, m []string) bool {
+		return p.setDate(p.addDays(1))
+	}},
+	{regexp.MustCompile(`(?i)\b(?:by\s+|due\s+)?(today)\b|(今日|きょう)` + jaParticle), func(p *parser, m []string) bool {
+		return p.setDate(p.today())
+	}},
+	{regexp.MustCompile(`(?i)\b(tonight)\b|(今夜|今晩)` + jaParticle), func(p *parser, m []string) bool {
+		if p.clock == nil {
+			p.clock = &clock{20, 0}
+		}
+		return p.setDate(p.today())
+	}},
+	{regexp.MustCompile(`(?i)\bin\s+(\d+|an?|one|two|three|four|five|six|seven|eight|nine|ten)\s+(day|week|month|year)s?\b`), func(p *parser, m []string) bool {
+		n := amount(m[1])
+		switch unitFrequency(m[2]) {
+		case "DAILY":
+			return p.setDate(p.addDays(n))
+		case "WEEKLY":
+			return p.setDate(p.addDays(7 * n))
+		case "MONTHLY":
+			y, mo, d := p.now.AddDate(0, n, 0).Date()
+			return p.setDate(date{y, mo, d})
+		default:
+			y, mo, d := p.now.AddDate(n, 0, 0).Date()
+			return p.setDate(date{y, mo, d})
+		}
+	}},
+	{regexp.MustCompile(`(\d+)(日|週間|か月|ヶ月|ヵ月|カ月|ケ月|年)後` + jaParticle), func(p *parser, m []string) bool {
+		n := amount(m[1])
+		switch unitFrequency(m[2]) {
+		case "DAILY":
+			return p.setDate(p.addDays(n))
+		case "WEEKLY":
+			return p.setDate(p.addDays(7 * n))
+		case "MONTHLY":
+			y, mo, d := p.now.AddDate(0, n, 0).Date()
+			return p.setDate(date{y, mo, d})
+		default:
+			y, mo, d := p.now.AddDate(n, 0, 0).Date()
+			return p.setDate(date{y, mo, d})
+		}
+	}},
+	{regexp.MustCompile(`(?i)\b(?:on\s+|by\s+|due\s+)?next\s+` + weekdayPattern + `\b`), func(p *parser, m []string) bool {
+		monday := p.mondayAfter()
+		start := time.Date(monday.year, monday.month, monday.day, 0, 0, 0, 0, p.now.Location())
+		day := start.AddDate(0, 0, (int(englishWeekday(m[1]))+6)%7)
+		y, mo, d := day.Date()
+		return p.setDate(date{y, mo, d})
+	}},
+	{regexp.MustCompile(`(来週|今週)の?([日月火水木金土])曜日?` + jaParticle), func(p *parser, m []string) bool {
+		monday := p.mondayAfter()
+		start := time.Date(monday.year, monday.month, monday.day, 0, 0, 0, 0, p.now.Location())
+		if m[1] == "今週" {
+			start = start.AddDate(0, 0, -7)
+		}
+		day := start.AddDate(0, 0, (int(japaneseWeekdays[m[2]])+6)%7)
+		y, mo, d := day.Date()
+		return p.setDate(date{y, mo, d})
+	}},
+	{regexp.MustCompile(`(?i)\b(?:on\s+|by\s+|due\s+)?(?:this\s+)?` + weekdayPattern + `\b`), func(p *parser, m []string) bool {
+		return p.setDate(p.nextWeekday([]time.Weekday{englishWeekday(m[1])}, false))
+	}},
+	{regexp.MustCompile(`([日月火水木金土])曜日?` + jaParticle), func(p *parser, m []string) bool {
+		return p.setDate(p.nextWeekday([]time.Weekday{japaneseWeekdays[m[1]]}, false))
+	}},
+	{regexp.MustCompile(`(?i)\b(?:by\s+|due\s+)?next\s+(week|month|year)\b|(来週|来月|来年)` + jaParticle), func(p *parser, m []string) bool {
+		switch strings.ToLower(m[1]) + m[2] {
+		case "week", "来週":
+			return p.setDate(p.mondayAfter())
+		case "month", "来月":
+			y, mo, _ := p.now.Date()
+			first := time.Date(y, mo+1, 1, 0, 0, 0, 0, p.now.Location())
+			return p.setDate(date{first.Year(), first.Month(), 1})
+		default:
+			return p.setDate(date{p.now.Year() + 1, time.January, 1})
+		}
+	}},
+}
+
+var timeRules = []rule{
+	{regexp.MustCompile(`(?i)\b(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm|a\.m\.|p\.m\.)`), func(p *parser, m []string) bool {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		if hour < 1 || hour > 12 {
+			return false
+		}
+		hour %= 12
+		if strings.HasPrefix(strings.ToLower(m[3]), "p") {
+			hour += 12
+		}
+		return p.setClock(hour, minute)
+	}},
+	{regexp.MustCompile(`(午前|午後)?(\d{1,2})時(?:(\d{1,2})分|(半))?` + jaParticle), func(p *parser, m []string) bool {
+		hour, _ := strconv.Atoi(m[2])
+		minute, _ := strconv.Atoi(m[3])
+		if m[4] != "" {
+			minute = 30
+		}
+		if m[1] != "" && hour > 12 {
+			return false
+		}
+		if m[1] == "午後" && hour < 12 {
+			hour += 12
+		}
+		if m[1] == "午前" && hour == 12 {
+			hour = 0
+		}
+		return p.setClock(hour, minute)
+	}},
+	{regexp.MustCompile(`(?i)(?:\bat\s+)?\b([01]?\d|2[0-3]):([0-5]\d)\b` + jaParticle), func(p *parser, m []string) bool {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		return p.setClock(hour, minute)
+	}},
+	{regexp.MustCompile(`(?i)\b(?:at\s+)?(noon|midday|midnight)\b|(正午)` + jaParticle), func(p *parser, m []string) bool {
+		if strings.EqualFold(m[1], "midnight") {
+			return p.setClock(0, 0)
+		}
+		return p.setClock(12, 0)
+	}},
+}
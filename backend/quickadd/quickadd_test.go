@@ -0,0 +1,100 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+// wednesday is the fixed "now" of the tests: Wednesday, January 15th 2025.
+var wednesday = time.Date(2025, time.January, 15, 10, 0, 0, 0, jst)
+
+func isRef(name string) bool {
+	return name == "finance" || name == "home"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text       string
+		now        time.Time
+		title      string
+		due        string
+		dueText    string
+		refs       []string
+		priority   string
+		recurrence string
+	}{
+		{text: "Pay rent tomorrow 9am #finance !high", title: "Pay rent", due: "2025-01-16 09:00", dueText: "tomorrow 9am", refs: []string{"finance"}, priority: PriorityHigh},
+		{text: "明日9時に家賃を払う", title: "家賃を払う", due: "2025-01-16 09:00", dueText: "明日 9時に"},
+		{text: "明日の午後3時半 歯医者", title: "歯医者", due: "2025-01-16 15:30", dueText: "明日の 午後3時半"},
+		{text: "Standup every monday", title: "Standup", due: "2025-01-20 23:59", recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+		{text: "every weekday standup", title: "standup", due: "2025-01-15 23:59", recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{text: "毎週月曜 ゴミ出し", title: "ゴミ出し", due: "2025-01-20 23:59", recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+		{text: "Water plants every 2 weeks", title: "Water plants", recurrence: "FREQ=WEEKLY;INTERVAL=2"},
+		{text: "来週金曜 レポート提出", title: "レポート提出", due: "2025-01-24 23:59", dueText: "来週金曜"},
+		{text: "Renew passport Jan 31", now: time.Date(2025, time.February, 1, 10, 0, 0, 0, jst), title: "Renew passport", due: "2026-01-31 23:59", dueText: "Jan 31"},
+		{text: "Renew passport Jan 31", title: "Renew passport", due: "2025-01-31 23:59", dueText: "Jan 31"},
+		{text: "Pay Feb 30", title: "Pay Feb 30"},
+		{text: "Meeting 2025-03-05 14:30", title: "Meeting", due: "2025-03-05 14:30", dueText: "2025-03-05 14:30"},
+		{text: "Report due friday", title: "Report", due: "2025-01-17 23:59", dueText: "due friday"},
+		{text: "Taxes in 3 days", title: "Taxes", due: "2025-01-18 23:59", dueText: "in 3 days"},
+		{text: "3日後に電話", title: "電話", due: "2025-01-18 23:59", dueText: "3日後に"},
+		{text: "Lunch at noon", title: "Lunch", due: "2025-01-15 12:00", dueText: "at noon"},
+		{text: "Call back 9am", title: "Call back", due: "2025-01-16 09:00", dueText: "9am"},
+		{text: "Fix bug #123", title: "Fix bug #123"},
+		{text: "Fix bug #123 #home", title: "Fix bug #123", refs: []string{"home"}},
+		{text: "Deploy !!!", title: "Deploy", priority: PriorityHigh},
+		{text: "Deploy !2", title: "Deploy", priority: PriorityMedium},
+		{text: "掃除 !低", title: "掃除", priority: PriorityLow},
+		{text: "掃除 ！高", title: "掃除", priority: PriorityHigh},
+		{text: "Call mom !low !high", title: "Call mom !high", priority: PriorityLow},
+		{text: "Hello! world", title: "Hello! world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			now := tt.now
+			if now.IsZero() {
+				now = wednesday
+			}
+			got := Parse(tt.text, now, isRef)
+
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+			due := ""
+			if got.Due != nil {
+				if got.Due.Location() != now.Location() {
+					t.Errorf("Due is in %v, want %v", got.Due.Location(), now.Location())
+				}
+				due = got.Due.Format("2006-01-02 15:04")
+			}
+			if due != tt.due {
+				t.Errorf("Due = %q, want %q", due, tt.due)
+			}
+			if tt.dueText != "" && got.DueText != tt.dueText {
+				t.Errorf("DueText = %q, want %q", got.DueText, tt.dueText)
+			}
+			if !reflect.DeepEqual(got.Refs, tt.refs) {
+				t.Errorf("Refs = %q, want %q", got.Refs, tt.refs)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("Priority = %q, want %q", got.Priority, tt.priority)
+			}
+			if got.Recurrence != tt.recurrence {
+				t.Errorf("Recurrence = %q, want %q", got.Recurrence, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestParseNilIsRef(t *testing.T) {
+	got := Parse("Fix bug #123 #home", wednesday, nil)
+	if want := []string{"123", "home"}; !reflect.DeepEqual(got.Refs, want) {
+		t.Errorf("Refs = %q, want %q", got.Refs, want)
+	}
+	if got.Title != "Fix bug" {
+		t.Errorf("Title = %q, want %q", got.Title, "Fix bug")
+	}
+}
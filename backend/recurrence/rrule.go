@@ -0,0 +1,358 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used
+// by recurring todos: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY,
+// COUNT and UNTIL.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry. Ordinal is zero for every matching weekday in
+// the period, or the nth (negative counts from the end) weekday of the month
+// or year.
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    *time.Time
+}
+
+// maxIterations bounds the period scan so that a rule which can never match
+// (e.g. BYDAY=5MO with FREQ=MONTHLY and an UNTIL far in the future) ends.
+const maxIterations = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// A leading "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(value)
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, err := parseWeekdayNum(code)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			// Weeks always start on Monday; accept the default explicitly.
+			if value != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, fmt.Errorf("%w: ordinal BYDAY requires FREQ=MONTHLY or FREQ=YEARLY", ErrInvalidRule)
+		}
+	}
+
+	return rule, nil
+}
+
+// Normalize parses s and returns its canonical string form.
+func Normalize(s string) (string, error) {
+	rule, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: malformed UNTIL %q", ErrInvalidRule, value)
+}
+
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return WeekdayNum{}, fmt.Errorf("%w: malformed BYDAY %q", ErrInvalidRule, code)
+	}
+	weekday, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRule, code)
+	}
+	day := WeekdayNum{Weekday: weekday}
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("%w: malformed BYDAY %q", ErrInvalidRule, code)
+		}
+		day.Ordinal = n
+	}
+	return day, nil
+}
+
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			code := weekdayNames[day.Weekday]
+			if day.Ordinal != 0 {
+				code = strconv.Itoa(day.Ordinal) + code
+			}
+			codes[i] = code
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// After returns the first occurrence strictly after t for a series starting
+// at dtstart. COUNT is not applied here because callers track how many
+// occurrences have already been generated; see Nth.
+func (r *Rule) After(dtstart, t time.Time) (time.Time, bool) {
+	var found time.Time
+	ok := r.iterate(dtstart, func(occ time.Time) bool {
+		if occ.After(t) {
+			found = occ
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// Nth returns the nth (1-based) occurrence of the series, honouring COUNT.
+func (r *Rule) Nth(dtstart time.Time, n int) (time.Time, bool) {
+	if n < 1 || (r.Count > 0 && n > r.Count) {
+		return time.Time{}, false
+	}
+	var found time.Time
+	seen := 0
+	ok := r.iterate(dtstart, func(occ time.Time) bool {
+		seen++
+		if seen == n {
+			found = occ
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// iterate calls yield with each occurrence in chronological order until yield
+// returns false, the rule's UNTIL is passed or the iteration bound is hit. It
+// reports whether yield stopped the iteration.
+func (r *Rule) iterate(dtstart time.Time, yield func(time.Time) bool) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	for period := 0; period < maxIterations; period++ {
+		candidates := r.expand(dtstart, period*interval)
+		for _, occ := range candidates {
+			if occ.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && occ.After(*r.Until) {
+				return false
+			}
+			if !yield(occ) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expand returns the sorted occurrences that fall in the period offset
+// periods after the one containing dtstart.
+func (r *Rule) expand(dtstart time.Time, offset int) []time.Time {
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hh, mm, ss, 0, loc)
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+offset)
+		if len(r.ByDay) == 0 || r.matchesWeekday(day.Weekday()) {
+			days = append(days, day)
+		}
+	case Weekly:
+		// Weeks start on Monday.
+		shift := (int(dtstart.Weekday()) + 6) % 7
+		monday := at(y, m, d-shift+7*offset)
+		if len(r.ByDay) == 0 {
+			days = append(days, at(y, m, d+7*offset))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if r.matchesWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := at(y, m+time.Month(offset), 1)
+		if len(r.ByDay) == 0 {
+			// Months without the start's day of month are skipped.
+			day := at(first.Year(), first.Month(), d)
+			if day.Month() == first.Month() {
+				days = append(days, day)
+			}
+			break
+		}
+		days = r.byDayIn(first, first.AddDate(0, 1, 0))
+	case Yearly:
+		if len(r.ByDay) == 0 {
+			day := at(y+offset, m, d)
+			if day.Month() == m {
+				days = append(days, day)
+			}
+			break
+		}
+		first := at(y+offset, time.January, 1)
+		days = r.byDayIn(first, first.AddDate(1, 0, 0))
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// byDayIn returns the days in [start, end) selected by BYDAY, resolving
+// ordinals relative to that span.
+func (r *Rule) byDayIn(start, end time.Time) []time.Time {
+	byWeekday := make(map[time.Weekday][]time.Time)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for _, wd := range r.ByDay {
+		matches := byWeekday[wd.Weekday]
+		var picked []time.Time
+		switch {
+		case wd.Ordinal == 0:
+			picked = matches
+		case wd.Ordinal > 0 && wd.Ordinal <= len(matches):
+			picked = []time.Time{matches[wd.Ordinal-1]}
+		case wd.Ordinal < 0 && -wd.Ordinal <= len(matches):
+			picked = []time.Time{matches[len(matches)+wd.Ordinal]}
+		}
+		for _, day := range picked {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	return days
+}
+
+func (r *Rule) matchesWeekday(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"rrule:freq=weekly;interval=2;byday=mo,we", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "FREQ=MONTHLY;BYDAY=-1FR"},
+		{"FREQ=YEARLY;BYDAY=2SU;COUNT=5", "FREQ=YEARLY;BYDAY=2SU;COUNT=5"},
+		{"FREQ=DAILY;UNTIL=20250131", "FREQ=DAILY;UNTIL=20250131T235959Z"},
+		{"FREQ=DAILY;UNTIL=20250131T120000Z", "FREQ=DAILY;UNTIL=20250131T120000Z"},
+		{"FREQ=WEEKLY;WKST=MO;INTERVAL=1", "FREQ=WEEKLY"},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"RRULE:",
+		"INTERVAL=2",
+		"FREQ",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;UNTIL=2025-01-01",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=54MO",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;WKST=SU",
+		"FREQ=DAILY;BYMONTH=1",
+	} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidRule", in, err)
+		}
+	}
+}
+
+func TestParseDateOnlyUntilIncludesDay(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;UNTIL=20250131")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, time.January, 31, 23, 59, 59, 0, time.UTC); !rule.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", rule.Until, want)
+	}
+}
+
+func TestNth(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		n       int
+		want    time.Time
+		ok      bool
+	}{
+		{"first is dtstart", "FREQ=DAILY", date(2025, 1, 1, 9), 1, date(2025, 1, 1, 9), true},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", date(2025, 1, 1, 9), 3, date(2025, 1, 7, 9), true},
+		{"weekly interval", "FREQ=WEEKLY;INTERVAL=2", date(2025, 1, 1, 9), 3, date(2025, 1, 29, 9), true},
+		{"weekly byday skips days before dtstart", "FREQ=WEEKLY;BYDAY=MO,WE", date(2025, 1, 1, 9), 2, date(2025, 1, 6, 9), true},
+		{"weekly byday second week", "FREQ=WEEKLY;BYDAY=MO,WE", date(2025, 1, 1, 9), 3, date(2025, 1, 8, 9), true},
+		{"monthly on the 31st skips February", "FREQ=MONTHLY", date(2025, 1, 31, 9), 2, date(2025, 3, 31, 9), true},
+		{"monthly on the 31st skips April", "FREQ=MONTHLY", date(2025, 1, 31, 9), 3, date(2025, 5, 31, 9), true},
+		{"monthly on the 31st July and August", "FREQ=MONTHLY", date(2025, 1, 31, 9), 5, date(2025, 8, 31, 9), true},
+		{"last friday in January", "FREQ=MONTHLY;BYDAY=-1FR", date(2025, 1, 1, 10), 1, date(2025, 1, 31, 10), true},
+		{"last friday in February", "FREQ=MONTHLY;BYDAY=-1FR", date(2025, 1, 1, 10), 2, date(2025, 2, 28, 10), true},
+		{"last friday in March", "FREQ=MONTHLY;BYDAY=-1FR", date(2025, 1, 1, 10), 3, date(2025, 3, 28, 10), true},
+		{"second tuesday", "FREQ=MONTHLY;BYDAY=2TU", date(2025, 1, 1, 8), 2, date(2025, 2, 11, 8), true},
+		{"yearly on leap day", "FREQ=YEARLY", date(2024, 2, 29, 9), 2, date(2028, 2, 29, 9), true},
+		{"within count", "FREQ=DAILY;COUNT=3", date(2025, 1, 1, 9), 3, date(2025, 1, 3, 9), true},
+		{"past count", "FREQ=DAILY;COUNT=3", date(2025, 1, 1, 9), 4, time.Time{}, false},
+		{"zero", "FREQ=DAILY", date(2025, 1, 1, 9), 0, time.Time{}, false},
+		{"date-only until includes its day", "FREQ=DAILY;UNTIL=20250103", date(2025, 1, 1, 18), 3, date(2025, 1, 3, 18), true},
+		{"past date-only until", "FREQ=DAILY;UNTIL=20250103", date(2025, 1, 1, 18), 4, time.Time{}, false},
+		{"never matches within bound", "FREQ=MONTHLY;BYDAY=6MO", date(2025, 1, 1, 9), 1, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := rule.Nth(tt.dtstart, tt.n)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("Nth(%d) = %v, %v; want %v, %v", tt.n, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+		want    time.Time
+		ok      bool
+	}{
+		{"next day", "FREQ=DAILY", date(2025, 1, 1, 9), date(2025, 1, 5, 12), date(2025, 1, 6, 9), true},
+		{"strictly after an occurrence", "FREQ=DAILY", date(2025, 1, 1, 9), date(2025, 1, 5, 9), date(2025, 1, 6, 9), true},
+		{"before dtstart", "FREQ=DAILY", date(2025, 1, 10, 9), date(2025, 1, 1, 0), date(2025, 1, 10, 9), true},
+		{"monthly on the 31st", "FREQ=MONTHLY", date(2025, 1, 31, 9), date(2025, 2, 1, 0), date(2025, 3, 31, 9), true},
+		{"count is ignored", "FREQ=DAILY;COUNT=2", date(2025, 1, 1, 9), date(2025, 1, 4, 0), date(2025, 1, 4, 9), true},
+		{"past until", "FREQ=DAILY;UNTIL=20250103", date(2025, 1, 1, 9), date(2025, 1, 3, 10), time.Time{}, false},
+		{"never matches within bound", "FREQ=MONTHLY;BYDAY=6MO", date(2025, 1, 1, 9), date(2025, 1, 1, 9), time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := rule.After(tt.dtstart, tt.after)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("After(%v) = %v, %v; want %v, %v", tt.after, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		offset  int
+		want    []time.Time
+	}{
+		{"month without the day", "FREQ=MONTHLY", date(2025, 1, 31, 9), 1, nil},
+		{"month with the day", "FREQ=MONTHLY", date(2025, 1, 31, 9), 2, []time.Time{date(2025, 3, 31, 9)}},
+		{"whole week from Monday", "FREQ=WEEKLY;BYDAY=WE,MO", date(2025, 1, 1, 9), 0, []time.Time{date(2024, 12, 30, 9), date(2025, 1, 1, 9)}},
+		{"every friday of the month", "FREQ=MONTHLY;BYDAY=FR", date(2025, 2, 10, 9), 0, []time.Time{date(2025, 2, 7, 9), date(2025, 2, 14, 9), date(2025, 2, 21, 9), date(2025, 2, 28, 9)}},
+		{"duplicate days once", "FREQ=MONTHLY;BYDAY=-1FR,4FR", date(2025, 2, 1, 9), 0, []time.Time{date(2025, 2, 28, 9)}},
+		{"daily byday filter", "FREQ=DAILY;BYDAY=MO", date(2025, 1, 1, 9), 1, nil},
+		{"yearly without leap day", "FREQ=YEARLY", date(2024, 2, 29, 9), 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rule.expand(tt.dtstart, tt.offset)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expand(%d) = %v, want %v", tt.offset, got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("expand(%d)[%d] = %v, want %v", tt.offset, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
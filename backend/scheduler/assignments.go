@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"todo-app/models"
+	"todo-app/notifier"
+
+	"gorm.io/gorm"
+)
+
+const KindTodoAssigned = "todos.assigned"
+
+type assignmentPayload struct {
+	TodoID     uint `json:"todo_id"`
+	AssigneeID uint `json:"assignee_id"`
+	AssignedBy uint `json:"assigned_by"`
+}
+
+func assignmentKey(todo *models.Todo) string {
+	return fmt.Sprintf("assigned:%d:%d:%d", todo.ID, *todo.AssigneeID, todo.UpdatedAt.UnixNano())
+}
+
+// ScheduleAssignment enqueues a notification telling todo's assignee that
+// assignedBy gave it to them.
+func ScheduleAssignment(tx *gorm.DB, todo *models.Todo, assignedBy uint) error {
+	payload := assignmentPayload{TodoID: todo.ID, AssigneeID: *todo.AssigneeID, AssignedBy: assignedBy}
+	return Enqueue(tx, KindTodoAssigned, payload, time.Now(), assignmentKey(todo))
+}
+
+// AssignmentHandler tells assignees about todos given to them, in the app and
+// by email when an email notifier is configured. Todos deleted or assigned to
+// someone else in the meantime are dropped.
+func AssignmentHandler(db *gorm.DB, notifiers notifier.Registry) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload assignmentPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid assignment payload: %w", err)
+		}
+
+		var todo models.Todo
+		if err := db.WithContext(ctx).First(&todo, payload.TodoID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if todo.AssigneeID == nil || *todo.AssigneeID != payload.AssigneeID {
+			return nil
+		}
+
+		var assignee models.User
+		if err := db.WithContext(ctx).First(&assignee, payload.AssigneeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		var assigner models.User
+		if err := db.WithContext(ctx).Unscoped().First(&assigner, payload.AssignedBy).Error; err != nil {
+			return err
+		}
+
+		msg := notifier.Message{
+			Key:     fmt.Sprintf("assigned:%d", job.ID),
+			UserID:  assignee.ID,
+			Email:   assignee.Email,
+			TodoID:  todo.ID,
+			Subject: "Assigned to you: " + todo.Title,
+			Body:    fmt.Sprintf("%s assigned %q to you.", assigner.Email, todo.Title),
+		}
+		if email, ok := notifiers[notifier.ChannelEmail]; ok {
+			if err := email.Notify(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return notifiers[notifier.ChannelInApp].Notify(ctx, msg)
+	}
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"todo-app/models"
+	"todo-app/notifier"
+
+	"gorm.io/gorm"
+)
+
+const KindGroupInvitation = "groups.invitation"
+
+type invitationPayload struct {
+	InvitationID uint `json:"invitation_id"`
+}
+
+func invitationKey(invitation *models.GroupInvitation) string {
+	return fmt.Sprintf("invitation:%d:%d", invitation.ID, invitation.UpdatedAt.Unix())
+}
+
+// ScheduleInvitation enqueues delivery of invitation. Sending an invitation
+// again after it was updated delivers it again.
+func ScheduleInvitation(tx *gorm.DB, invitation *models.GroupInvitation) error {
+	payload := invitationPayload{InvitationID: invitation.ID}
+	return Enqueue(tx, KindGroupInvitation, payload, time.Now(), invitationKey(invitation))
+}
+
+// InvitationHandler delivers group invitations by email when an email
+// notifier is configured, and in the app when the address already belongs to
+// a user. Invitations answered in the meantime are dropped.
+func InvitationHandler(db *gorm.DB, notifiers notifier.Registry) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload invitationPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid invitation payload: %w", err)
+		}
+
+		var invitation models.GroupInvitation
+		if err := db.WithContext(ctx).First(&invitation, payload.InvitationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if invitation.Status != models.InvitationPending {
+			return nil
+		}
+
+		var group models.Group
+		if err := db.WithContext(ctx).First(&group, invitation.GroupID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		var inviter models.User
+		if err := db.WithContext(ctx).Unscoped().First(&inviter, invitation.InvitedBy).Error; err != nil {
+			return err
+		}
+
+		msg := notifier.Message{
+			Key:     invitationKey(&invitation),
+			Email:   invitation.Email,
+			Subject: fmt.Sprintf("%s invited you to %s", inviter.Email, group.Name),
+			Body: fmt.Sprintf("%s invited you to join the list %q as %s.\n\nOpen your invitations to accept or decline before %s.",
+				inviter.Email, group.Name, invitation.Role, invitation.ExpiresAt.Format(time.RFC1123)),
+		}
+		if email, ok := notifiers[notifier.ChannelEmail]; ok {
+			if err := email.Notify(ctx, msg); err != nil {
+				return err
+			}
+		}
+
+		var users []models.User
+		if err := db.WithContext(ctx).Where("LOWER(email) = ?", invitation.Email).Limit(1).Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		msg.UserID = users[0].ID
+		return notifiers[notifier.ChannelInApp].Notify(ctx, msg)
+	}
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+	"todo-app/blobstore"
+	"todo-app/models"
+	"todo-app/trash"
+
+	"gorm.io/gorm"
+)
+
+const KindCleanupJobs = "jobs.cleanup"
+
+// CleanupJobsHandler deletes finished jobs older than retention. Failed jobs
+// are kept for inspection.
+func CleanupJobsHandler(db *gorm.DB, retention time.Duration) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		cutoff := time.Now().Add(-retention)
+		return db.WithContext(ctx).Where("status = ? AND updated_at < ?", models.JobDone, cutoff).Delete(&models.Job{}).Error
+	}
+}
+
+const KindCleanupUndoTokens = "undo.cleanup"
+
+// CleanupUndoTokensHandler deletes undo tokens whose window has passed.
+func CleanupUndoTokensHandler(db *gorm.DB, window time.Duration) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		cutoff := time.Now().Add(-window)
+		return db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.UndoToken{}).Error
+	}
+}
+
+const KindPurgeTrash = "trash.purge"
+
+// PurgeTrashHandler permanently deletes todos and groups that have been in
+// the trash for longer than retention.
+func PurgeTrashHandler(db *gorm.DB, retention time.Duration) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			todos, groups, err := trash.PurgeOlderThan(tx, time.Now().Add(-retention))
+			if err != nil {
+				return err
+			}
+			if todos > 0 || groups > 0 {
+				log.Printf("Purged %d todos and %d groups from trash", todos, groups)
+			}
+			return nil
+		})
+	}
+}
+
+const KindPruneRevisions = "revisions.prune"
+
+// PruneRevisionsHandler deletes history entries older than retention and
+// keeps at most maxPerEntity of the newest entries for each todo or group.
+// A zero retention or maxPerEntity disables that limit.
+func PruneRevisionsHandler(db *gorm.DB, retention time.Duration, maxPerEntity int) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		db := db.WithContext(ctx)
+		var pruned int64
+		if retention > 0 {
+			result := db.Where("created_at < ?", time.Now().Add(-retention)).Delete(&models.Revision{})
+			if result.Error != nil {
+				return result.Error
+			}
+			pruned += result.RowsAffected
+		}
+		if maxPerEntity > 0 {
+			result := db.Exec(`DELETE FROM revisions WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY entity_type, entity_id ORDER BY id DESC) AS n
+					FROM revisions
+				) ranked WHERE n > ?
+			)`, maxPerEntity)
+			if result.Error != nil {
+				return result.Error
+			}
+			pruned += result.RowsAffected
+		}
+		if pruned > 0 {
+			log.Printf("Pruned %d revisions", pruned)
+		}
+		return nil
+	}
+}
+
+const KindCleanupAttachments = "attachments.cleanup"
+
+// CleanupAttachmentsHandler deletes attachments, and their blobs, whose todo
+// has been purged from the trash or that were deleted longer than window ago,
+// when the deletion can no longer be undone.
+func CleanupAttachmentsHandler(db *gorm.DB, blobs blobstore.BlobStore, window time.Duration) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		var orphans []models.Attachment
+		err := db.WithContext(ctx).Unscoped().
+			Where("NOT EXISTS (SELECT 1 FROM todos WHERE todos.id = attachments.todo_id) OR deleted_at < ?", time.Now().Add(-window)).
+			Limit(500).
+			Find(&orphans).Error
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range orphans {
+			if err := blobs.Delete(ctx, attachment.StorageKey); err != nil {
+				return err
+			}
+			if err := db.WithContext(ctx).Unscoped().Delete(&attachment).Error; err != nil {
+				return err
+			}
+		}
+		if len(orphans) > 0 {
+			log.Printf("Deleted %d orphaned attachments", len(orphans))
+		}
+		return nil
+	}
+}
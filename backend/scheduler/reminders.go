@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-app/models"
+	"todo-app/notifier"
+
+	"gorm.io/gorm"
+)
+
+const KindReminder = "reminder"
+
+type reminderPayload struct {
+	ReminderID uint      `json:"reminder_id"`
+	FireAt     time.Time `json:"fire_at"`
+}
+
+func reminderKey(reminderID uint, fireAt time.Time) string {
+	return fmt.Sprintf("reminder:%d:%d", reminderID, fireAt.Unix())
+}
+
+// ScheduleReminder enqueues delivery of reminder at its current fire time.
+// Jobs for earlier fire times are left in place; the handler drops them once
+// it sees they no longer match the reminder, so rescheduling never needs to
+// find and cancel the previous job.
+func ScheduleReminder(tx *gorm.DB, reminder *models.Reminder, todo *models.Todo) error {
+	fireAt := reminder.FireAt(todo)
+	if fireAt == nil || fireAt.Before(time.Now()) {
+		return nil
+	}
+	payload := reminderPayload{ReminderID: reminder.ID, FireAt: *fireAt}
+	return Enqueue(tx, KindReminder, payload, *fireAt, reminderKey(reminder.ID, *fireAt))
+}
+
+// ScheduleTodoReminders reschedules every reminder of todo, e.g. after its due
+// date changed.
+func ScheduleTodoReminders(tx *gorm.DB, todo *models.Todo) error {
+	var reminders []models.Reminder
+	if err := tx.Where("todo_id = ?", todo.ID).Find(&reminders).Error; err != nil {
+		return fmt.Errorf("failed to fetch reminders: %w", err)
+	}
+	for i := range reminders {
+		if err := ScheduleReminder(tx, &reminders[i], todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReminderHandler delivers reminder jobs through the notifier registered for
+// the reminder's channel.
+func ReminderHandler(db *gorm.DB, notifiers notifier.Registry) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload reminderPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid reminder payload: %w", err)
+		}
+
+		var reminder models.Reminder
+		if err := db.WithContext(ctx).First(&reminder, payload.ReminderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		var todo models.Todo
+		if err := db.WithContext(ctx).First(&todo, reminder.TodoID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if todo.Completed {
+			return nil
+		}
+
+		// Fire times are compared to the second, as reminderKey keys them:
+		// the database may keep a time less precisely than the payload.
+		fireAt := reminder.FireAt(&todo)
+		if fireAt == nil || fireAt.Unix() != payload.FireAt.Unix() {
+			// The reminder or the todo's due date changed after this job was
+			// scheduled; a job for the new time exists.
+			return nil
+		}
+
+		n, ok := notifiers[reminder.Channel]
+		if !ok {
+			return fmt.Errorf("notification channel %q is not configured", reminder.Channel)
+		}
+
+		var user models.User
+		if err := db.WithContext(ctx).First(&user, reminder.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		body := []string{todo.Title}
+		if todo.DueDate != nil {
+			body = append(body, "Due: "+todo.DueDate.Format(time.RFC1123))
+		}
+		if todo.Description != "" {
+			body = append(body, "", todo.Description)
+		}
+
+		msg := notifier.Message{
+			Key:     reminderKey(reminder.ID, payload.FireAt),
+			UserID:  user.ID,
+			Email:   user.Email,
+			TodoID:  todo.ID,
+			Subject: "Reminder: " + todo.Title,
+			Body:    strings.Join(body, "\n"),
+		}
+		if err := n.Notify(ctx, msg); err != nil {
+			return err
+		}
+
+		return db.WithContext(ctx).Model(&reminder).Update("sent_at", time.Now()).Error
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+	"todo-app/models"
+	"todo-app/notifier"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type recordingNotifier struct {
+	messages []notifier.Message
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, msg notifier.Message) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// TestReminderHandlerIgnoresSubsecondDifferences delivers a reminder whose
+// fire time the database keeps less precisely than its job's payload, as
+// Postgres does with nanoseconds.
+func TestReminderHandlerIgnoresSubsecondDifferences(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Todo{}, &models.Reminder{}, &models.Job{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := models.WithWorkspace(context.Background(), 1)
+	db = db.WithContext(ctx)
+
+	user := &models.User{Email: "ann@example.com", Password: "x"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	todo := &models.Todo{Title: "Call", UserID: user.ID}
+	if err := db.Create(todo).Error; err != nil {
+		t.Fatal(err)
+	}
+	remindAt := time.Now().Add(time.Hour).Truncate(time.Second).Add(123456 * time.Microsecond)
+	reminder := &models.Reminder{TodoID: todo.ID, UserID: user.ID, RemindAt: &remindAt, Channel: "in_app"}
+	if err := db.Create(reminder).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(fireAt time.Time) int {
+		t.Helper()
+		payload, err := json.Marshal(reminderPayload{ReminderID: reminder.ID, FireAt: fireAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := &recordingNotifier{}
+		handler := ReminderHandler(db, notifier.Registry{"in_app": n})
+		if err := handler(ctx, &models.Job{Kind: KindReminder, Payload: string(payload)}); err != nil {
+			t.Fatal(err)
+		}
+		return len(n.messages)
+	}
+
+	if sent := run(remindAt.Add(789 * time.Nanosecond)); sent != 1 {
+		t.Errorf("sent %d reminders for a payload off by nanoseconds, want 1", sent)
+	}
+	if sent := run(remindAt.Add(-time.Minute)); sent != 0 {
+		t.Errorf("sent %d reminders for an outdated payload, want 0", sent)
+	}
+}
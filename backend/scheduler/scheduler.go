@@ -0,0 +1,231 @@
+// Package scheduler runs background jobs stored in the jobs table.
+//
+// Jobs survive restarts because they live in the database. Each replica
+// polls for due jobs and claims them with SELECT ... FOR UPDATE SKIP LOCKED
+// and a lease, so a job is run by one replica at a time; a job whose lease
+// expires (its replica died mid-run) is claimed again, which makes delivery
+// at-least-once. Enqueueing with a dedupe key is idempotent, which lets every
+// replica schedule the same periodic task without running it twice.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HandlerFunc func(ctx context.Context, job *models.Job) error
+
+type periodicTask struct {
+	kind     string
+	interval time.Duration
+}
+
+type Scheduler struct {
+	db           *gorm.DB
+	workerID     string
+	handlers     map[string]HandlerFunc
+	periodic     []periodicTask
+	pollInterval time.Duration
+	lease        time.Duration
+	batchSize    int
+}
+
+func New(db *gorm.DB) *Scheduler {
+	hostname, _ := os.Hostname()
+
+	pollInterval := 5 * time.Second
+	if v := os.Getenv("SCHEDULER_POLL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			pollInterval = d
+		}
+	}
+
+	return &Scheduler{
+		db:           db,
+		workerID:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		handlers:     make(map[string]HandlerFunc),
+		pollInterval: pollInterval,
+		lease:        2 * time.Minute,
+		batchSize:    20,
+	}
+}
+
+// Handle registers the handler for jobs of the given kind.
+func (s *Scheduler) Handle(kind string, handler HandlerFunc) {
+	s.handlers[kind] = handler
+}
+
+// Every enqueues a job of the given kind once per interval. The kind must
+// also have a handler registered with Handle.
+func (s *Scheduler) Every(kind string, interval time.Duration) {
+	s.periodic = append(s.periodic, periodicTask{kind: kind, interval: interval})
+}
+
+// Enqueue adds a job to run at runAt. When dedupeKey is non-empty and a job
+// with the same key already exists, nothing is added. db may be a
+// transaction so that the job is only scheduled if the caller commits.
+func Enqueue(db *gorm.DB, kind string, payload interface{}, runAt time.Time, dedupeKey string) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &models.Job{
+		Kind:    kind,
+		Payload: string(data),
+		RunAt:   runAt,
+		Status:  models.JobPending,
+	}
+	if dedupeKey != "" {
+		job.DedupeKey = &dedupeKey
+	}
+
+	err = db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedupe_key"}}, DoNothing: true}).Create(job).Error
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	log.Printf("Scheduler started (worker %s)", s.workerID)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.schedulePeriodic()
+		s.runDueJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) schedulePeriodic() {
+	now := time.Now()
+	for _, task := range s.periodic {
+		slot := now.Truncate(task.interval)
+		key := fmt.Sprintf("%s:%d", task.kind, slot.Unix())
+		if err := Enqueue(s.db, task.kind, struct{}{}, slot, key); err != nil {
+			log.Printf("Scheduler: failed to schedule %s: %v", task.kind, err)
+		}
+	}
+}
+
+func (s *Scheduler) runDueJobs(ctx context.Context) {
+	for {
+		jobs, err := s.claim()
+		if err != nil {
+			log.Printf("Scheduler: failed to claim jobs: %v", err)
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+
+		for _, job := range jobs {
+			if ctx.Err() != nil {
+				return
+			}
+			s.run(ctx, job)
+		}
+	}
+}
+
+// claim locks a batch of due jobs for this worker. Jobs left running by a
+// worker whose lease has expired are claimed again.
+func (s *Scheduler) claim() ([]*models.Job, error) {
+	var jobs []*models.Job
+	now := time.Now()
+	lockedUntil := now.Add(s.lease)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", models.JobPending, now, models.JobRunning, now).
+			Order("run_at").
+			Limit(s.batchSize).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+			job.Status = models.JobRunning
+			job.LockedBy = s.workerID
+			job.LockedUntil = &lockedUntil
+			job.Attempts++
+		}
+
+		return tx.Model(&models.Job{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       models.JobRunning,
+			"locked_by":    s.workerID,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}).Error
+	})
+
+	return jobs, err
+}
+
+func (s *Scheduler) run(ctx context.Context, job *models.Job) {
+	handler, ok := s.handlers[job.Kind]
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler for job kind %q", job.Kind)
+	} else {
+		// Jobs are not run on behalf of any one workspace, and handlers
+		// reach whichever the records they work on are in.
+		jobCtx, cancel := context.WithTimeout(models.WithWorkspace(ctx, 0), s.lease)
+		err = handler(jobCtx, job)
+		cancel()
+	}
+
+	updates := map[string]interface{}{
+		"locked_by":    "",
+		"locked_until": nil,
+	}
+	switch {
+	case err == nil:
+		updates["status"] = models.JobDone
+		updates["last_error"] = ""
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("Scheduler: job %d (%s) failed permanently: %v", job.ID, job.Kind, err)
+		updates["status"] = models.JobFailed
+		updates["last_error"] = err.Error()
+	default:
+		log.Printf("Scheduler: job %d (%s) failed, retrying: %v", job.ID, job.Kind, err)
+		updates["status"] = models.JobPending
+		updates["last_error"] = err.Error()
+		updates["run_at"] = time.Now().Add(backoff(job.Attempts))
+	}
+
+	// Only release the job if this worker still holds it; if the lease ran
+	// out another worker may have claimed it in the meantime.
+	if err := s.db.Model(&models.Job{}).Where("id = ? AND locked_by = ?", job.ID, s.workerID).Updates(updates).Error; err != nil {
+		log.Printf("Scheduler: failed to update job %d: %v", job.ID, err)
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return d
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-app/models"
+	"todo-app/notifier"
+
+	"gorm.io/gorm"
+)
+
+const KindResurfaceSnoozed = "todos.resurface"
+
+// resurfaceBatchSize bounds the todos woken per run; the rest wait for the
+// next one.
+const resurfaceBatchSize = 500
+
+// ResurfaceSnoozedHandler brings back todos whose snooze has passed: it clears
+// snoozed_until and tells the owner through inApp. Open todos only are
+// announced.
+func ResurfaceSnoozedHandler(db *gorm.DB, inApp notifier.Notifier) HandlerFunc {
+	return func(ctx context.Context, job *models.Job) error {
+		var todos []models.Todo
+		err := db.WithContext(ctx).
+			Where("snoozed_until <= ?", time.Now()).
+			Order("snoozed_until").
+			Limit(resurfaceBatchSize).
+			Find(&todos).Error
+		if err != nil {
+			return fmt.Errorf("failed to fetch snoozed todos: %w", err)
+		}
+
+		for _, todo := range todos {
+			if !todo.Completed {
+				msg := notifier.Message{
+					Key:     fmt.Sprintf("resurface:%d:%d", todo.ID, todo.SnoozedUntil.Unix()),
+					UserID:  todo.UserID,
+					TodoID:  todo.ID,
+					Subject: "Back from snooze: " + todo.Title,
+					Body:    todo.Title,
+				}
+				if err := inApp.Notify(ctx, msg); err != nil {
+					return err
+				}
+			}
+
+			// The todo may have been snoozed again since it was read.
+			err := db.WithContext(ctx).Model(&models.Todo{}).
+				Where("id = ? AND snoozed_until = ?", todo.ID, *todo.SnoozedUntil).
+				Update("snoozed_until", nil).Error
+			if err != nil {
+				return fmt.Errorf("failed to resurface todo: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,279 @@
+// Package search finds todos by the words in their title and description.
+//
+// On PostgreSQL it uses a generated tsvector column with a GIN index, ranks
+// hits with ts_rank and highlights them with ts_headline. On other databases
+// it falls back to case-insensitive LIKE matching, which is slow on large
+// tables but needs no schema support.
+package search
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"todo-app/models"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Hit is a todo matching a search with its relevance and highlighted text.
+// Title and Snippet are HTML-escaped with matches wrapped in <mark>.
+type Hit struct {
+	Todo    models.Todo
+	Rank    float64
+	Title   string
+	Snippet string
+}
+
+// The text search configuration. "simple" neither stems nor drops stop
+// words, so it behaves the same for every language users write todos in.
+const textConfig = "simple"
+
+// Delimiters passed to ts_headline; they cannot occur in user input after
+// escaping, so matches can be marked up safely after the text is escaped.
+const (
+	startSel = "\x01"
+	stopSel  = "\x02"
+)
+
+// Migrate adds the search column and index to the todos table. It must run
+// after the table has been migrated and is a no-op on other databases.
+func Migrate(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	statements := []string{
+		`ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('` + textConfig + `', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('` + textConfig + `', coalesce(description, '')), 'B')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_todos_search_vector ON todos USING GIN (search_vector)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to migrate search: %w", err)
+		}
+	}
+	return nil
+}
+
+// Terms splits a query into the words it searches for. Everything but
+// letters and digits separates words, which also keeps tsquery operators
+// out of the query.
+func Terms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Todos returns up to limit todos userID can see, best match first, that
+// contain every term of query, the last one also as a prefix so that results
+// update while the user is typing. scope may further restrict the todos
+// searched.
+func Todos(db *gorm.DB, userID uint, query string, limit int, scope func(*gorm.DB) *gorm.DB) ([]Hit, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if db.Dialector.Name() == "postgres" {
+		return fullText(db, userID, terms, limit, scope)
+	}
+	return like(db, userID, terms, limit, scope)
+}
+
+type row struct {
+	models.Todo
+	Rank         float64
+	TitleHeading string
+	Snippet      string
+}
+
+func fullText(db *gorm.DB, userID uint, terms []string, limit int, scope func(*gorm.DB) *gorm.DB) ([]Hit, error) {
+	// Every term matches as a prefix: "rep" finds "report" and "repair".
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	tsquery := strings.Join(parts, " & ")
+
+	options := "StartSel=" + startSel + ", StopSel=" + stopSel
+	query := db.Model(&models.Todo{}).
+		Select(`todos.*,
+			ts_rank(search_vector, to_tsquery('`+textConfig+`', @q)) AS rank,
+			ts_headline('`+textConfig+`', title, to_tsquery('`+textConfig+`', @q), @title_options) AS title_heading,
+			ts_headline('`+textConfig+`', description, to_tsquery('`+textConfig+`', @q), @snippet_options) AS snippet`,
+			map[string]interface{}{
+				"q":               tsquery,
+				"title_options":   options + ", HighlightAll=true",
+				"snippet_options": options + ", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \"",
+			}).
+		Where(models.TodoAccess(userID, models.RoleViewer)).
+		Where("search_vector @@ to_tsquery('"+textConfig+"', ?)", tsquery).
+		Order("rank DESC, todos.updated_at DESC").
+		Limit(limit)
+	if scope != nil {
+		query = scope(query)
+	}
+
+	var rows []row
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search todos: %w", err)
+	}
+
+	hits := make([]Hit, len(rows))
+	for i, r := range rows {
+		hits[i] = Hit{
+			Todo:    r.Todo,
+			Rank:    r.Rank,
+			Title:   markup(r.TitleHeading),
+			Snippet: markup(r.Snippet),
+		}
+	}
+	return hits, nil
+}
+
+// markup escapes text from ts_headline and turns its delimiters into <mark>
+// elements.
+func markup(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, startSel, "<mark>")
+	return strings.ReplaceAll(text, stopSel, "</mark>")
+}
+
+func like(db *gorm.DB, userID uint, terms []string, limit int, scope func(*gorm.DB) *gorm.DB) ([]Hit, error) {
+	query := db.Where(models.TodoAccess(userID, models.RoleViewer))
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		query = query.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	if scope != nil {
+		query = scope(query)
+	}
+
+	// Rank in Go: the candidates have to be loaded to build snippets anyway.
+	var todos []models.Todo
+	if err := query.Order("updated_at DESC").Limit(limit * 5).Find(&todos).Error; err != nil {
+		return nil, fmt.Errorf("failed to search todos: %w", err)
+	}
+
+	hits := make([]Hit, len(todos))
+	for i, todo := range todos {
+		hits[i] = Hit{
+			Todo:    todo,
+			Rank:    likeRank(todo, terms),
+			Title:   highlight(todo.Title, terms, 0),
+			Snippet: highlight(todo.Description, terms, 30),
+		}
+	}
+	sortHits(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+// likeRank weighs title matches above description matches, mirroring the
+// A and B weights of the tsvector.
+func likeRank(todo models.Todo, terms []string) float64 {
+	title, description := strings.ToLower(todo.Title), strings.ToLower(todo.Description)
+	var rank float64
+	for _, term := range terms {
+		rank += float64(strings.Count(title, term)) + 0.4*float64(strings.Count(description, term))
+	}
+	return rank
+}
+
+func sortHits(hits []Hit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Todo.UpdatedAt.After(hits[j].Todo.UpdatedAt)
+	})
+}
+
+// highlight wraps occurrences of terms in text in <mark> elements, escaping
+// the rest. With words > 0 the text is cut to about that many words around
+// the first match.
+func highlight(text string, terms []string, words int) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Lower-casing changed the length; match on the original text.
+		lower = runes
+	}
+
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == term {
+				for j := i; j < i+len(t); j++ {
+					marked[j] = true
+				}
+				if first < 0 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if words > 0 {
+		start, end = window(runes, first, words)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+	open := false
+	for i := start; i < end; i++ {
+		if marked[i] && !open {
+			b.WriteString("<mark>")
+			open = true
+		} else if !marked[i] && open {
+			b.WriteString("</mark>")
+			open = false
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+	if end < len(runes) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+// window returns the span of words words of runes starting words/3 words
+// before index at, without the spaces around it.
+func window(runes []rune, at, words int) (int, int) {
+	if at < 0 {
+		at = 0
+	}
+	start := at
+	for skipped := 0; start > 0 && skipped < words/3; start-- {
+		if unicode.IsSpace(runes[start-1]) && start < at {
+			if skipped++; skipped == words/3 {
+				break
+			}
+		}
+	}
+	end := start
+	for counted := 0; end < len(runes); end++ {
+		if unicode.IsSpace(runes[end]) {
+			if counted++; counted == words {
+				break
+			}
+		}
+	}
+	return start, end
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Buy milk", []string{"buy", "milk"}},
+		{"  rep:* & !foo | bar  ", []string{"rep", "foo", "bar"}},
+		{"e-mail 2024", []string{"e", "mail", "2024"}},
+		{"ÜBER Straße", []string{"über", "straße"}},
+		{"会議 東京", []string{"会議", "東京"}},
+		{"", []string{}},
+		{"&|!()<>", []string{}},
+	}
+	for _, tt := range tests {
+		got := Terms(tt.query)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"report", "report"},
+		{"50%", `50\%`},
+		{"snake_case", `snake\_case`},
+		{`back\slash`, `back\\slash`},
+		{`%_\`, `\%\_\\`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.term); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		words int
+		want  string
+	}{
+		{"escapes html", `Fish & <chips> "to go"`, []string{"chips"}, 0, "Fish &amp; &lt;<mark>chips</mark>&gt; &#34;to go&#34;"},
+		{"ignores case", "Report and REPORT", []string{"report"}, 0, "<mark>Report</mark> and <mark>REPORT</mark>"},
+		{"several terms", "quarterly report draft", []string{"draft", "quarter"}, 0, "<mark>quarter</mark>ly report <mark>draft</mark>"},
+		{"overlapping terms merge", "report", []string{"rep", "report"}, 0, "<mark>report</mark>"},
+		{"adjacent matches merge", "abab", []string{"ab"}, 0, "<mark>abab</mark>"},
+		{"no match", "a < b", []string{"zzz"}, 0, "a &lt; b"},
+		{"multibyte", "東京で会議", []string{"会議"}, 0, "東京で<mark>会議</mark>"},
+		{"multibyte between ascii", "Café <Ünïcode> day", []string{"ünïcode"}, 0, "Café &lt;<mark>Ünïcode</mark>&gt; day"},
+		{"lower-casing changes length", "İstanbul trip", []string{"stanbul"}, 0, "İ<mark>stanbul</mark> trip"},
+		{"window around match", "one two three four five six seven eight nine ten", []string{"six"}, 6, "… four five <mark>six</mark> seven eight nine …"},
+		{"window without match", "one two three four five six seven eight nine ten", []string{"zzz"}, 6, "one two three four five six …"},
+		{"window fits text", "one two three", []string{"two"}, 6, "one <mark>two</mark> three"},
+		{"window multibyte", "α β γ δ ε ζ η θ ι κ", []string{"ζ"}, 3, "… ε <mark>ζ</mark> η …"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms, tt.words); got != tt.want {
+				t.Errorf("highlight(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	runes := []rune("one two three four five six seven eight nine ten")
+	tests := []struct {
+		name       string
+		at, words  int
+		start, end int
+	}{
+		{"middle", 24, 6, 14, 44},
+		{"no match", -1, 6, 0, 27},
+		{"near the start", 4, 6, 0, 27},
+		{"near the end", 45, 6, 34, 48},
+		{"fewer than three words", 24, 2, 24, 33},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := window(runes, tt.at, tt.words)
+			if start != tt.start || end != tt.end {
+				t.Errorf("window(%d, %d) = %d, %d (%q); want %d, %d (%q)",
+					tt.at, tt.words, start, end, string(runes[start:end]), tt.start, tt.end, string(runes[tt.start:tt.end]))
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package trash permanently removes soft-deleted todos and groups together
+// with the rows that only exist for them.
+package trash
+
+import (
+	"fmt"
+	"time"
+	"todo-app/models"
+
+	"gorm.io/gorm"
+)
+
+// PurgeTodos hard-deletes the given soft-deleted todos that userID may edit,
+// their reminders, comments, dependencies, time entries, pins and history.
+// Their attachments are left for CleanupAttachmentsHandler, which removes
+// them with their contents once their todo is gone. Todos that are not in
+// the trash are left alone. It returns the number of todos removed.
+func PurgeTodos(tx *gorm.DB, userID uint, ids []uint) (int64, error) {
+	var trashed []uint
+	if err := tx.Unscoped().Model(&models.Todo{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Where(models.TodoAccess(userID, models.RoleEditor)).
+		Pluck("id", &trashed).Error; err != nil {
+		return 0, fmt.Errorf("failed to find trashed todos: %w", err)
+	}
+	return purgeTodos(tx, trashed)
+}
+
+// PurgeGroups hard-deletes the given soft-deleted groups owned by userID,
+// their status columns, members, invitations, pins and history. Trashed
+// todos and subgroups still pointing at a purged group are detached from it.
+// It returns the number of groups removed.
+func PurgeGroups(tx *gorm.DB, userID uint, ids []uint) (int64, error) {
+	var trashed []uint
+	if err := tx.Unscoped().Model(&models.Group{}).
+		Where("id IN ? AND user_id = ? AND deleted_at IS NOT NULL", ids, userID).
+		Pluck("id", &trashed).Error; err != nil {
+		return 0, fmt.Errorf("failed to find trashed groups: %w", err)
+	}
+	return purgeGroups(tx, trashed)
+}
+
+// PurgeOlderThan hard-deletes every todo and group that has been in the
+// trash since before cutoff.
+func PurgeOlderThan(tx *gorm.DB, cutoff time.Time) (todos, groups int64, err error) {
+	var todoIDs, groupIDs []uint
+	if err := tx.Unscoped().Model(&models.Todo{}).Where("deleted_at < ?", cutoff).Pluck("id", &todoIDs).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to find expired todos: %w", err)
+	}
+	if err := tx.Unscoped().Model(&models.Group{}).Where("deleted_at < ?", cutoff).Pluck("id", &groupIDs).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to find expired groups: %w", err)
+	}
+
+	if todos, err = purgeTodos(tx, todoIDs); err != nil {
+		return 0, 0, err
+	}
+	if groups, err = purgeGroups(tx, groupIDs); err != nil {
+		return 0, 0, err
+	}
+	return todos, groups, nil
+}
+
+func purgeTodos(tx *gorm.DB, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := tx.Unscoped().Where("todo_id IN ?", ids).Delete(&models.Reminder{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge reminders: %w", err)
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", "todo", ids).Delete(&models.Revision{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge history: %w", err)
+	}
+	if err := tx.Unscoped().Where("todo_id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge comments: %w", err)
+	}
+	if err := tx.Where("todo_id IN ? OR blocker_id IN ?", ids, ids).Delete(&models.Dependency{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge dependencies: %w", err)
+	}
+	if err := tx.Where("todo_id IN ?", ids).Delete(&models.TimeEntry{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge time entries: %w", err)
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", "todo", ids).Delete(&models.Pin{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge pins: %w", err)
+	}
+	result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Todo{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge todos: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func purgeGroups(tx *gorm.DB, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := tx.Unscoped().Model(&models.Todo{}).Where("group_id IN ?", ids).Update("group_id", nil).Error; err != nil {
+		return 0, fmt.Errorf("failed to detach todos: %w", err)
+	}
+	if err := tx.Unscoped().Model(&models.Group{}).Where("parent_id IN ?", ids).Update("parent_id", nil).Error; err != nil {
+		return 0, fmt.Errorf("failed to detach subgroups: %w", err)
+	}
+	if err := tx.Unscoped().Where("group_id IN ?", ids).Delete(&models.Status{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge statuses: %w", err)
+	}
+	if err := tx.Where("group_id IN ?", ids).Delete(&models.GroupMember{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge members: %w", err)
+	}
+	if err := tx.Where("group_id IN ?", ids).Delete(&models.GroupInvitation{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge invitations: %w", err)
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", "group", ids).Delete(&models.Revision{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge history: %w", err)
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", "group", ids).Delete(&models.Pin{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to purge pins: %w", err)
+	}
+	result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Group{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge groups: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}